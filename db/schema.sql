@@ -13,8 +13,42 @@ CREATE TABLE `accounts` (
                                   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
                                   `pwd_update` timestamp DEFAULT NULL,
                                   `del_req` timestamp DEFAULT NULL,
-                                  `status` enum('ACTIVE','INACTIVE') NOT NULL DEFAULT 'ACTIVE',
-                                  PRIMARY KEY (`id`)
+                                  `status` enum('ACTIVE','INACTIVE','SUSPENDED') NOT NULL DEFAULT 'ACTIVE',
+                                  `role` enum('USER','MODERATOR') NOT NULL DEFAULT 'USER',
+                                  PRIMARY KEY (`id`),
+                                  KEY `idx_accounts_user_name` (`user_name`),
+                                  KEY `idx_accounts_f_name` (`f_name`),
+                                  KEY `idx_accounts_l_name` (`l_name`),
+                                  KEY `idx_accounts_status` (`status`, `del_req`)
+);
+
+CREATE TABLE `follows` (
+                                  `follower_id` int(11) NOT NULL,
+                                  `followee_id` int(11) NOT NULL,
+                                  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  PRIMARY KEY (`follower_id`, `followee_id`),
+                                  KEY `idx_follows_followee` (`followee_id`),
+                                  FOREIGN KEY (`follower_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE,
+                                  FOREIGN KEY (`followee_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `blocks` (
+                                  `blocker_id` int(11) NOT NULL,
+                                  `blocked_id` int(11) NOT NULL,
+                                  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  PRIMARY KEY (`blocker_id`, `blocked_id`),
+                                  KEY `idx_blocks_blocked` (`blocked_id`),
+                                  FOREIGN KEY (`blocker_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE,
+                                  FOREIGN KEY (`blocked_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `mutes` (
+                                  `muter_id` int(11) NOT NULL,
+                                  `muted_id` int(11) NOT NULL,
+                                  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  PRIMARY KEY (`muter_id`, `muted_id`),
+                                  FOREIGN KEY (`muter_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE,
+                                  FOREIGN KEY (`muted_id`) REFERENCES `accounts` (`id`) ON DELETE CASCADE
 );
 
 -- run the following commands from project root:
@@ -113,6 +113,46 @@ func (a account) GetUser(ctx *app.Context) (interface{}, error) {
 	return a.service.GetAccountWithBlogs(ctx, username)
 }
 
+// Follow makes the logged in user follow the user in the path.
+func (a account) Follow(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Follow(ctx, ctx.Request.PathParam("username"))
+}
+
+// Unfollow makes the logged in user stop following the user in the path.
+func (a account) Unfollow(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Unfollow(ctx, ctx.Request.PathParam("username"))
+}
+
+// Block makes the logged in user block the user in the path.
+func (a account) Block(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Block(ctx, ctx.Request.PathParam("username"))
+}
+
+// Unblock makes the logged in user stop blocking the user in the path.
+func (a account) Unblock(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Unblock(ctx, ctx.Request.PathParam("username"))
+}
+
+// GetBlocked lists the users the logged in user blocks.
+func (a account) GetBlocked(ctx *app.Context) (interface{}, error) {
+	return a.service.GetBlocked(ctx)
+}
+
+// Mute makes the logged in user mute the user in the path.
+func (a account) Mute(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Mute(ctx, ctx.Request.PathParam("username"))
+}
+
+// Unmute makes the logged in user stop muting the user in the path.
+func (a account) Unmute(ctx *app.Context) (interface{}, error) {
+	return nil, a.service.Unmute(ctx, ctx.Request.PathParam("username"))
+}
+
+// GetMuted lists the users the logged in user mutes.
+func (a account) GetMuted(ctx *app.Context) (interface{}, error) {
+	return a.service.GetMuted(ctx)
+}
+
 // Update updates user details but not password.
 func (a account) Update(ctx *app.Context) (interface{}, error) {
 	user, err := ctx.Request.UnmarshalUser()
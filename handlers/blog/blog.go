@@ -1,8 +1,10 @@
 package blog
 
 import (
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/handlers"
 
@@ -10,6 +12,8 @@ import (
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/imaging"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/response"
 	"github.com/Aakanksha-jais/picshot-golang-backend/services"
 )
 
@@ -34,33 +38,111 @@ func New(service services.Blog) handlers.Blog {
 }
 
 func (b blog) GetAll(ctx *app.Context) (interface{}, error) {
-	limit, err := strconv.Atoi(ctx.Request.QueryParam("limit"))
-	if err != nil || limit < 0 {
-		return nil, errors.InvalidParam{Param: "limit"}
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := blogFilter(ctx.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, err := b.service.GetAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: blogs, NextCursor: models.NextBlogCursor(blogs, page)}, nil
+}
+
+// blogFilter reads the filters of a blog listing from the query params.
+// Dates are accepted as RFC3339 timestamps or as plain dates, and tags as a comma separated list.
+// A plain date in "to" includes the whole of that day.
+func blogFilter(r *app.Request) (*models.BlogFilter, error) {
+	filter := &models.BlogFilter{
+		Blog:   models.Blog{Status: r.QueryParam("status")},
+		Author: strings.TrimSpace(r.QueryParam("author")),
+		Sort:   r.QueryParam("sort"),
+	}
+
+	var err error
+
+	if filter.CreatedAfter, _, err = parseDate(r.QueryParam("from")); err != nil {
+		return nil, errors.InvalidParam{Param: "from"}
+	}
+
+	to, dateOnly, err := parseDate(r.QueryParam("to"))
+	if err != nil {
+		return nil, errors.InvalidParam{Param: "to"}
+	}
+
+	if dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	filter.CreatedBefore = to
+
+	if tags := r.QueryParam("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			tag = strings.TrimSpace(tag)
+			if !strings.HasPrefix(tag, "#") {
+				tag = "#" + tag
+			}
+
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	switch r.QueryParam("match") {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return nil, errors.InvalidParam{Param: "match"}
+	}
+
+	if hasImages := r.QueryParam("has_images"); hasImages != "" {
+		value, err := strconv.ParseBool(hasImages)
+		if err != nil {
+			return nil, errors.InvalidParam{Param: "has_images"}
+		}
+
+		filter.HasImages = &value
 	}
 
-	pageNo, err := strconv.Atoi(ctx.Request.QueryParam("pageno"))
-	if err != nil || pageNo < 0 {
-		return nil, errors.InvalidParam{Param: "pageno"}
+	return filter, nil
+}
+
+// parseDate parses a timestamp or a plain date, and tells which one it was.
+func parseDate(value string) (t time.Time, dateOnly bool, err error) {
+	if value == "" {
+		return time.Time{}, false, nil
 	}
 
-	return b.service.GetAll(ctx, nil, &models.Page{Limit: int64(limit), PageNo: int64(pageNo)})
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+
+	t, err = time.Parse("2006-01-02", value)
+
+	return t, err == nil, err
 }
 
 func (b blog) GetAllByTag(ctx *app.Context) (interface{}, error) {
 	tag := ctx.Request.PathParam("tag")
 
-	limit, err := strconv.Atoi(ctx.Request.QueryParam("limit"))
-	if err != nil || limit < 0 {
-		return nil, errors.InvalidParam{Param: "limit"}
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
 	}
 
-	pageNo, err := strconv.Atoi(ctx.Request.QueryParam("pageno"))
-	if err != nil || pageNo < 0 {
-		return nil, errors.InvalidParam{Param: "pageno"}
+	blogs, err := b.service.GetAllByTagName(ctx, tag, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return b.service.GetAllByTagName(ctx, tag, &models.Page{Limit: int64(limit), PageNo: int64(pageNo)})
+	return models.Paginated{Data: blogs, NextCursor: models.NextBlogCursor(blogs, page)}, nil
 }
 
 func (b blog) GetBlogsByUser(ctx *app.Context) (interface{}, error) {
@@ -75,7 +157,17 @@ func (b blog) GetBlogsByUser(ctx *app.Context) (interface{}, error) {
 		return nil, errors.InvalidParam{Param: "account ID"}
 	}
 
-	return b.service.GetAll(ctx, &models.Blog{AccountID: int64(id)}, nil)
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, err := b.service.GetAll(ctx, &models.BlogFilter{Blog: models.Blog{AccountID: int64(id)}}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: blogs, NextCursor: models.NextBlogCursor(blogs, page)}, nil
 }
 
 func (b blog) Get(ctx *app.Context) (interface{}, error) {
@@ -84,21 +176,55 @@ func (b blog) Get(ctx *app.Context) (interface{}, error) {
 	return b.service.GetByID(ctx, blogID)
 }
 
+// GetBySlug serves a blog of an author by its slug. A blog found by an old slug is redirected to its current slug.
+func (b blog) GetBySlug(ctx *app.Context) (interface{}, error) {
+	username := ctx.Request.PathParam("username")
+	slug := ctx.Request.PathParam("slug")
+
+	blog, err := b.service.GetBySlug(ctx, username, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if blog.Slug != slug {
+		return response.Redirect{Location: "/user/" + url.PathEscape(username) + "/blogs/" + blog.Slug}, nil
+	}
+
+	return blog, nil
+}
+
 func (b blog) Create(ctx *app.Context) (interface{}, error) {
-	fileHeaders := ctx.Request.ParseImages()
+	fileHeaders, err := ctx.Request.ParseImages(imaging.NewLimits(ctx.Config).MaxRequestSize)
+	if err != nil {
+		return nil, err
+	}
 
 	blog := &models.Blog{
-		Title:   ctx.Request.FormValue("title"),
-		Summary: ctx.Request.FormValue("summary"),
-		Content: ctx.Request.FormValue("content"),
-		Tags:    strings.Split(ctx.Request.FormValue("tags"), ","),
+		Title:         ctx.Request.FormValue("title"),
+		Summary:       ctx.Request.FormValue("summary"),
+		Content:       ctx.Request.FormValue("content"),
+		ContentFormat: ctx.Request.FormValue("content_format"),
+		Tags:          strings.Split(ctx.Request.FormValue("tags"), ","),
+		Status:        ctx.Request.FormValue("status"),
+		Visibility:    ctx.Request.FormValue("visibility"),
+		Uploads:       formList(ctx.Request, "uploads"),
+	}
+
+	publishAt, err := parsePublishAt(ctx.Request)
+	if err != nil {
+		return nil, err
 	}
 
+	blog.PublishAt = publishAt
+
 	return b.service.Create(ctx, blog, fileHeaders)
 }
 
 func (b blog) Update(ctx *app.Context) (interface{}, error) {
-	fileHeaders := ctx.Request.ParseImages()
+	fileHeaders, err := ctx.Request.ParseImages(imaging.NewLimits(ctx.Config).MaxRequestSize)
+	if err != nil {
+		return nil, err
+	}
 
 	tags := make([]string, 0)
 
@@ -107,12 +233,126 @@ func (b blog) Update(ctx *app.Context) (interface{}, error) {
 	}
 
 	blog := &models.Blog{
-		BlogID:  ctx.Request.PathParam("blogid"),
-		Title:   ctx.Request.FormValue("title"),
-		Summary: ctx.Request.FormValue("summary"),
-		Content: ctx.Request.FormValue("content"),
-		Tags:    tags,
+		BlogID:        ctx.Request.PathParam("blogid"),
+		Title:         ctx.Request.FormValue("title"),
+		Summary:       ctx.Request.FormValue("summary"),
+		Content:       ctx.Request.FormValue("content"),
+		ContentFormat: ctx.Request.FormValue("content_format"),
+		Tags:          tags,
+		Status:        ctx.Request.FormValue("status"),
+		Visibility:    ctx.Request.FormValue("visibility"),
+		Images:        imageOrder(ctx.Request),
+		Uploads:       formList(ctx.Request, "uploads"),
 	}
 
+	publishAt, err := parsePublishAt(ctx.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	blog.PublishAt = publishAt
+
 	return b.service.Update(ctx, blog, fileHeaders)
 }
+
+// imageOrder reads the final order of the images of a blog, as repeated or comma separated "images" fields.
+// Images are listed by their ID, and uploaded images as "upload:<n>" by their position in the upload.
+// It is nil if the field is not sent, and empty if all the images are removed.
+func imageOrder(r *app.Request) []models.Image {
+	ids := formList(r, "images")
+	if ids == nil {
+		return nil
+	}
+
+	images := make([]models.Image, 0, len(ids))
+
+	for _, id := range ids {
+		images = append(images, models.Image{ID: id})
+	}
+
+	return images
+}
+
+// formList reads a list sent as repeated or comma separated form fields.
+// It is nil if the field is not sent.
+func formList(r *app.Request, key string) []string {
+	values, ok := r.FormValues(key)
+	if !ok {
+		return nil
+	}
+
+	list := make([]string, 0)
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// parsePublishAt reads the RFC3339 time at which a scheduled blog is to be published.
+func parsePublishAt(r *app.Request) (*time.Time, error) {
+	value := r.FormValue("publish_at")
+	if value == "" {
+		return nil, nil
+	}
+
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: "publish_at"}
+	}
+
+	return &publishAt, nil
+}
+
+func (b blog) GetRevisions(ctx *app.Context) (interface{}, error) {
+	blogID := ctx.Request.PathParam("blogid")
+
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := b.service.GetRevisions(ctx, blogID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: revisions, NextCursor: models.NextOffsetCursor(len(revisions), page)}, nil
+}
+
+func (b blog) Restore(ctx *app.Context) (interface{}, error) {
+	blogID := ctx.Request.PathParam("blogid")
+
+	rev, err := strconv.ParseInt(ctx.Request.PathParam("rev"), 10, 64)
+	if err != nil || rev <= 0 {
+		return nil, errors.InvalidParam{Param: "rev"}
+	}
+
+	return b.service.Restore(ctx, blogID, rev)
+}
+
+// CreateUpload issues a presigned URL to upload a picture to, for a JSON body of its content type and size.
+func (b blog) CreateUpload(ctx *app.Context) (interface{}, error) {
+	var upload models.Upload
+
+	if err := ctx.Request.Unmarshal(&upload); err != nil {
+		return nil, err
+	}
+
+	if upload.ContentType == "" {
+		return nil, errors.MissingParam{Param: "content_type"}
+	}
+
+	return b.service.CreateUpload(ctx, &upload)
+}
+
+func (b blog) ConfirmUpload(ctx *app.Context) (interface{}, error) {
+	uploadID := ctx.Request.PathParam("uploadid")
+
+	return b.service.ConfirmUpload(ctx, uploadID)
+}
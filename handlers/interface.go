@@ -12,6 +12,14 @@ type Account interface {
 	Logout(ctx *app.Context) (interface{}, error)
 	Delete(ctx *app.Context) (interface{}, error)
 	GetUser(ctx *app.Context) (interface{}, error)
+	Follow(ctx *app.Context) (interface{}, error)
+	Unfollow(ctx *app.Context) (interface{}, error)
+	Block(ctx *app.Context) (interface{}, error)
+	Unblock(ctx *app.Context) (interface{}, error)
+	GetBlocked(ctx *app.Context) (interface{}, error)
+	Mute(ctx *app.Context) (interface{}, error)
+	Unmute(ctx *app.Context) (interface{}, error)
+	GetMuted(ctx *app.Context) (interface{}, error)
 	SendOTP(c *app.Context) (interface{}, error)
 	VerifyPhone(c *app.Context) (interface{}, error)
 	UpdatePassword(ctx *app.Context) (interface{}, error)
@@ -20,6 +28,7 @@ type Account interface {
 
 type Blog interface {
 	Get(ctx *app.Context) (interface{}, error)
+	GetBySlug(ctx *app.Context) (interface{}, error)
 	Browse(ctx *app.Context) (interface{}, error)
 	Delete(ctx *app.Context) (interface{}, error)
 	Create(ctx *app.Context) (interface{}, error)
@@ -27,4 +36,28 @@ type Blog interface {
 	GetAll(ctx *app.Context) (interface{}, error)
 	GetAllByTag(ctx *app.Context) (interface{}, error)
 	GetBlogsByUser(ctx *app.Context) (interface{}, error)
+	GetRevisions(ctx *app.Context) (interface{}, error)
+	Restore(ctx *app.Context) (interface{}, error)
+	CreateUpload(ctx *app.Context) (interface{}, error)
+	ConfirmUpload(ctx *app.Context) (interface{}, error)
+}
+
+type Tag interface {
+	GetAll(ctx *app.Context) (interface{}, error)
+	Autocomplete(ctx *app.Context) (interface{}, error)
+	Trending(ctx *app.Context) (interface{}, error)
+}
+
+type Search interface {
+	Blogs(ctx *app.Context) (interface{}, error)
+	Users(ctx *app.Context) (interface{}, error)
+}
+
+type Moderation interface {
+	ReportBlog(ctx *app.Context) (interface{}, error)
+	ReportUser(ctx *app.Context) (interface{}, error)
+	GetReports(ctx *app.Context) (interface{}, error)
+	Act(ctx *app.Context) (interface{}, error)
+	GetActions(ctx *app.Context) (interface{}, error)
+	GetWarnings(ctx *app.Context) (interface{}, error)
 }
@@ -0,0 +1,141 @@
+package moderation
+
+import (
+	"strconv"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/handlers"
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+)
+
+type moderation struct {
+	service services.Moderation
+}
+
+func New(service services.Moderation) handlers.Moderation {
+	return moderation{service: service}
+}
+
+// ReportBlog reports the blog in the path, with a reason code and details in the body.
+func (m moderation) ReportBlog(ctx *app.Context) (interface{}, error) {
+	var report models.Report
+
+	if err := ctx.Request.Unmarshal(&report); err != nil {
+		return nil, err
+	}
+
+	return m.service.ReportBlog(ctx, ctx.Request.PathParam("blogid"), &report)
+}
+
+// ReportUser reports the user in the path, with a reason code and details in the body.
+func (m moderation) ReportUser(ctx *app.Context) (interface{}, error) {
+	var report models.Report
+
+	if err := ctx.Request.Unmarshal(&report); err != nil {
+		return nil, err
+	}
+
+	return m.service.ReportUser(ctx, ctx.Request.PathParam("username"), &report)
+}
+
+// GetReports lists the moderation queue, filtered by status, kind, reason, blog_id and user_id.
+func (m moderation) GetReports(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := queryID(ctx.Request, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &models.ReportFilter{
+		Status: ctx.Request.QueryParam("status"),
+		Kind:   ctx.Request.QueryParam("kind"),
+		Reason: ctx.Request.QueryParam("reason"),
+		BlogID: ctx.Request.QueryParam("blog_id"),
+		UserID: userID,
+	}
+
+	reports, err := m.service.GetReports(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: reports, NextCursor: models.NextOffsetCursor(len(reports), page)}, nil
+}
+
+// Act takes the moderation action in the body, on a blog_id, a user_name or the blog or the user of a report_id.
+func (m moderation) Act(ctx *app.Context) (interface{}, error) {
+	var action models.ModerationAction
+
+	if err := ctx.Request.Unmarshal(&action); err != nil {
+		return nil, err
+	}
+
+	return m.service.Act(ctx, &action)
+}
+
+// GetActions lists the audit trail of moderation, filtered by action, moderator_id, blog_id and user_id.
+func (m moderation) GetActions(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	moderatorID, err := queryID(ctx.Request, "moderator_id")
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := queryID(ctx.Request, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &models.ModerationFilter{
+		Action:      ctx.Request.QueryParam("action"),
+		ModeratorID: moderatorID,
+		BlogID:      ctx.Request.QueryParam("blog_id"),
+		UserID:      userID,
+	}
+
+	actions, err := m.service.GetActions(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: actions, NextCursor: models.NextOffsetCursor(len(actions), page)}, nil
+}
+
+// GetWarnings lists the warnings the logged in user received.
+func (m moderation) GetWarnings(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := m.service.GetWarnings(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: warnings, NextCursor: models.NextOffsetCursor(len(warnings), page)}, nil
+}
+
+func queryID(r *app.Request, key string) (int64, error) {
+	value := r.QueryParam(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.InvalidParam{Param: key}
+	}
+
+	return id, nil
+}
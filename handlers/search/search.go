@@ -0,0 +1,44 @@
+package search
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/handlers"
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+)
+
+type search struct {
+	service services.Search
+}
+
+func New(service services.Search) handlers.Search {
+	return search{service: service}
+}
+
+func (s search) Blogs(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.service.Blogs(ctx, ctx.Request.QueryParam("q"), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: hits, NextCursor: models.NextOffsetCursor(len(hits), page)}, nil
+}
+
+func (s search) Users(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.service.Users(ctx, ctx.Request.QueryParam("q"), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: users, NextCursor: models.NextOffsetCursor(len(users), page)}, nil
+}
@@ -0,0 +1,38 @@
+package tag
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/handlers"
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+)
+
+type tag struct {
+	service services.Tag
+}
+
+func New(service services.Tag) handlers.Tag {
+	return tag{service: service}
+}
+
+func (t tag) GetAll(ctx *app.Context) (interface{}, error) {
+	page, err := ctx.Request.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := t.service.GetAll(ctx, page, ctx.Request.QueryParam("sort"))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginated{Data: tags, NextCursor: models.NextOffsetCursor(len(tags), page)}, nil
+}
+
+func (t tag) Autocomplete(ctx *app.Context) (interface{}, error) {
+	return t.service.Autocomplete(ctx, ctx.Request.QueryParam("prefix"))
+}
+
+func (t tag) Trending(ctx *app.Context) (interface{}, error) {
+	return t.service.Trending(ctx, ctx.Request.QueryParam("window"))
+}
@@ -1,19 +1,47 @@
 package main
 
 import (
+	"net/http"
+	"time"
+
 	picshot "github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/ratelimit"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/textfilter"
 
 	handlerAccount "github.com/Aakanksha-jais/picshot-golang-backend/handlers/account"
 	handlerBlog "github.com/Aakanksha-jais/picshot-golang-backend/handlers/blog"
+	handlerModeration "github.com/Aakanksha-jais/picshot-golang-backend/handlers/moderation"
+	handlerSearch "github.com/Aakanksha-jais/picshot-golang-backend/handlers/search"
+	handlerTag "github.com/Aakanksha-jais/picshot-golang-backend/handlers/tag"
 
 	serviceAccount "github.com/Aakanksha-jais/picshot-golang-backend/services/account"
 	serviceBlog "github.com/Aakanksha-jais/picshot-golang-backend/services/blog"
+	serviceModeration "github.com/Aakanksha-jais/picshot-golang-backend/services/moderation"
+	serviceSearch "github.com/Aakanksha-jais/picshot-golang-backend/services/search"
 	serviceTag "github.com/Aakanksha-jais/picshot-golang-backend/services/tag"
 
 	storeAccount "github.com/Aakanksha-jais/picshot-golang-backend/stores/account"
+	storeAudit "github.com/Aakanksha-jais/picshot-golang-backend/stores/audit"
+	storeBlob "github.com/Aakanksha-jais/picshot-golang-backend/stores/blob"
+	storeBlock "github.com/Aakanksha-jais/picshot-golang-backend/stores/block"
 	storeBlog "github.com/Aakanksha-jais/picshot-golang-backend/stores/blog"
+	storeFollow "github.com/Aakanksha-jais/picshot-golang-backend/stores/follow"
 	storeImage "github.com/Aakanksha-jais/picshot-golang-backend/stores/image"
+	storeMute "github.com/Aakanksha-jais/picshot-golang-backend/stores/mute"
+	storeReport "github.com/Aakanksha-jais/picshot-golang-backend/stores/report"
+	storeRevision "github.com/Aakanksha-jais/picshot-golang-backend/stores/revision"
+	storeSearch "github.com/Aakanksha-jais/picshot-golang-backend/stores/search"
 	storeTag "github.com/Aakanksha-jais/picshot-golang-backend/stores/tag"
+	storeUpload "github.com/Aakanksha-jais/picshot-golang-backend/stores/upload"
+)
+
+const (
+	publishInterval = time.Minute
+	expiryInterval  = 5 * time.Minute
+	orphanInterval  = 24 * time.Hour
+	renderInterval  = 10 * time.Minute
+	filterInterval  = 30 * time.Second
 )
 
 func main() {
@@ -23,14 +51,50 @@ func main() {
 	blogStore := storeBlog.New()
 	tagStore := storeTag.New()
 	accountStore := storeAccount.New()
-	imageStore := storeImage.New()
+	revisionStore := storeRevision.New()
+	uploadStore := storeUpload.New()
+	blobStore := storeBlob.New()
+	followStore := storeFollow.New()
+	blockStore := storeBlock.New()
+	muteStore := storeMute.New()
+	reportStore := storeReport.New()
+	auditStore := storeAudit.New()
+
+	// images are stored in an S3 bucket (or an S3 compatible store, with AWS_ENDPOINT set),
+	// or in a local directory that the app serves itself
+	imageStore := storeImage.NewS3(app.Config)
+	if app.Get("IMAGE_STORAGE") == "local" {
+		imageStore = storeImage.NewLocal(app.Config)
+		app.Files(constants.ImagesPath, app.GetOrDefault("IMAGE_DIR", storeImage.DefaultDir))
+	}
+
+	// the embedded search index is meant for local and offline use
+	searchIndex := storeSearch.NewMongo()
+	if app.Get("SEARCH_INDEX") == "embedded" {
+		searchIndex = storeSearch.NewEmbedded(blogStore)
+	}
+
+	// the rules of the content filter are read from a file, and read again when it changes
+	contentFilter, err := textfilter.Load(app.Get("CONTENT_FILTER_FILE"))
+	if err != nil {
+		app.Fatalf("cannot load the content filter: %v", err)
+	}
 
 	tagService := serviceTag.New(tagStore)
-	blogService := serviceBlog.New(blogStore, tagService, imageStore)
-	accountService := serviceAccount.New(accountStore, blogService)
+	blogService := serviceBlog.New(blogStore, tagService, imageStore, searchIndex, accountStore, revisionStore, uploadStore, blobStore,
+		followStore, blockStore, muteStore, reportStore, contentFilter)
+	accountService := serviceAccount.New(accountStore, blogService, followStore, blockStore, muteStore)
+	searchService := serviceSearch.New(searchIndex, accountStore, blockStore, muteStore)
+	moderationService := serviceModeration.New(reportStore, auditStore, accountStore, blogService)
+
+	// tokens stay valid until they expire, so the status of the account is checked on every request
+	app.Guard(accountService.CheckActive)
 
 	blogHandler := handlerBlog.New(blogService)
 	accountHandler := handlerAccount.New(accountService)
+	searchHandler := handlerSearch.New(searchService)
+	tagHandler := handlerTag.New(tagService)
+	moderationHandler := handlerModeration.New(moderationService)
 
 	// JWKS Endpoint
 	app.GET("/.well-known/jwks.json", accountHandler.JWKSEndpoint)
@@ -42,12 +106,31 @@ func main() {
 	app.GET("/myaccount", accountHandler.Get)
 	app.PUT("/myaccount", accountHandler.Update)
 	app.GET("/user/{username}", accountHandler.GetUser)
+	app.POST("/user/{username}/follow", accountHandler.Follow)
+	app.DELETE("/user/{username}/follow", accountHandler.Unfollow)
+	app.POST("/user/{username}/block", accountHandler.Block)
+	app.DELETE("/user/{username}/block", accountHandler.Unblock)
+	app.POST("/user/{username}/mute", accountHandler.Mute)
+	app.DELETE("/user/{username}/mute", accountHandler.Unmute)
+	app.GET("/myaccount/blocks", accountHandler.GetBlocked)
+	app.GET("/myaccount/mutes", accountHandler.GetMuted)
 	app.GET("/available", accountHandler.CheckAvailability)
 	app.PUT("/password", accountHandler.UpdatePassword)
 	app.DELETE("/myaccount", accountHandler.Delete)
 	app.POST("/send-otp/{phone}", accountHandler.SendOTP)
 	app.POST("/verify-phone", accountHandler.VerifyPhone)
 
+	// Routes for Search
+	// registered before the blog routes, so that "/{accountid}/blogs" does not shadow them
+	app.GET("/search/blogs", searchHandler.Blogs)
+	app.GET("/search/users", searchHandler.Users)
+
+	// Routes for Tags
+	// registered before "/tags/{tag}", so that the tag name does not shadow them
+	app.GET("/tags", tagHandler.GetAll)
+	app.GET("/tags/autocomplete", tagHandler.Autocomplete)
+	app.GET("/tags/trending", tagHandler.Trending)
+
 	// Routes for Blogs
 	app.GET("/blogs", blogHandler.GetAll)
 	app.GET("/browse", blogHandler.Browse)
@@ -56,7 +139,72 @@ func main() {
 	app.GET("/tags/{tag}", blogHandler.GetAllByTag)
 	app.PUT("/blogs/{blogid}", blogHandler.Update)
 	app.DELETE("/blogs/{blogid}", blogHandler.Delete)
+	app.GET("/blogs/{blogid}/revisions", blogHandler.GetRevisions)
+	app.POST("/blogs/{blogid}/revisions/{rev}/restore", blogHandler.Restore)
 	app.GET("/{accountid}/blogs", blogHandler.GetBlogsByUser)
+	app.GET("/user/{username}/blogs/{slug}", blogHandler.GetBySlug)
+
+	// Routes for reports and moderation
+	// moderators are accounts with the MODERATOR role, every action they take is recorded in the audit trail
+	app.POST("/blogs/{blogid}/report", moderationHandler.ReportBlog)
+	app.POST("/user/{username}/report", moderationHandler.ReportUser)
+	app.GET("/moderation/reports", moderationHandler.GetReports)
+	app.POST("/moderation/actions", moderationHandler.Act)
+	app.GET("/moderation/actions", moderationHandler.GetActions)
+	app.GET("/myaccount/warnings", moderationHandler.GetWarnings)
+
+	// Routes for presigned uploads of images
+	app.POST("/uploads", blogHandler.CreateUpload)
+	app.POST("/uploads/{uploadid}/confirm", blogHandler.ConfirmUpload)
+
+	// Rate limits of the routes open to abuse or costly to serve, as "<limit>/<period>", overridable by configs.
+	// Logins and signups are counted by client IP, as they come without claims.
+	rateLimits := []struct {
+		method, pattern, config, value string
+		key                            ratelimit.Key
+	}{
+		{http.MethodPost, "/login", "RATE_LIMIT_LOGIN", "5/1m", ratelimit.ByIP},
+		{http.MethodPost, "/signup", "RATE_LIMIT_SIGNUP", "10/1h", ratelimit.ByIP},
+		{http.MethodPost, "/send-otp/{phone}", "RATE_LIMIT_SEND_OTP", "3/10m", ratelimit.ByUser},
+		{http.MethodPost, "/verify-phone", "RATE_LIMIT_VERIFY_PHONE", "5/10m", ratelimit.ByUser},
+		{http.MethodPost, "/blog", "RATE_LIMIT_CREATE_BLOG", "30/1h", ratelimit.ByUser},
+	}
+
+	for _, limit := range rateLimits {
+		policy, err := ratelimit.Parse(app.GetOrDefault(limit.config, limit.value), limit.key)
+		if err != nil {
+			app.Fatalf("invalid %s: %v", limit.config, err)
+		}
+
+		app.RateLimit(limit.method, limit.pattern, policy)
+	}
+
+	// Background publisher of scheduled blogs
+	app.Every("publish-scheduled-blogs", publishInterval, blogService.PublishScheduled)
+
+	// Background renderer of blogs rendered by an older version of the markup policy
+	app.Every("render-stale-blogs", renderInterval, blogService.RenderStale)
+
+	// Reload of the rules of the content filter, once their file changes
+	app.Every("reload-content-filter", filterInterval, func(ctx *picshot.Context) error {
+		reloaded, err := contentFilter.Reload()
+		if reloaded {
+			ctx.Infof("content filter reloaded")
+		}
+
+		return err
+	})
+
+	// Cleanup of uploads that were not confirmed or attached in time
+	app.Every("expire-uploads", expiryInterval, blogService.ExpireUploads)
+
+	// Cleanup of stored images that no blog refers to, which are only reported with IMAGE_GC_DRY_RUN=true
+	dryRun := app.Get("IMAGE_GC_DRY_RUN") == "true"
+
+	app.Every("collect-orphaned-images", orphanInterval, func(ctx *picshot.Context) error {
+		_, err := blogService.CollectOrphans(ctx, dryRun)
+		return err
+	})
 
 	app.Start()
 }
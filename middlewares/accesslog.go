@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+)
+
+// RequestIDHeader carries the ID of a request, from the client or a proxy in front of the app, and back in the response.
+const RequestIDHeader = "X-Request-ID"
+
+//nolint:gochecknoglobals // compiled once, used for every request
+var requestIDPattern = regexp.MustCompile(`^[0-9A-Za-z._:-]{1,128}$`)
+
+type requestKey int
+
+const requestInfoKey requestKey = 1
+
+// RequestInfo is what is known of a request as it is served, for its access log.
+// The route is filled in by Route once the router matched it, and the account by Authentication.
+type RequestInfo struct {
+	ID     string
+	Route  string // pattern of the matched route
+	UserID int64
+	Logger log.Logger // logs with the ID of the request
+}
+
+// RequestInfoFrom returns the RequestInfo of a request served through AccessLog, or nil.
+func RequestInfoFrom(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey).(*RequestInfo)
+	return info
+}
+
+// requestLogger returns the logger of a request, which logs with its ID.
+func requestLogger(r *http.Request, logger log.Logger) log.Logger {
+	if info := RequestInfoFrom(r.Context()); info != nil {
+		return info.Logger
+	}
+
+	return logger
+}
+
+// AccessLog assigns an ID to every request, or keeps the X-Request-ID it comes with, and sends it back in the response.
+// Once a request is served, it logs its method, route, status, latency, size of the response and account.
+// It wraps the router, so that requests that match no route are logged as well.
+func AccessLog(logger log.Logger) func(inner http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(RequestIDHeader)
+			if !requestIDPattern.MatchString(id) {
+				id = uuid.New().String()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+
+			info := &RequestInfo{ID: id, Logger: logger.With(log.Fields{"request_id": id})}
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			inner.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), requestInfoKey, info)))
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+
+			fields := log.Fields{
+				"method":     r.Method,
+				"route":      info.Route,
+				"status":     recorder.status,
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes":      recorder.bytes,
+			}
+
+			if info.Route == "" {
+				fields["path"] = r.URL.Path // no route matched, the path tells what was asked for
+			}
+
+			if info.UserID != 0 {
+				fields["user_id"] = info.UserID
+			}
+
+			info.Logger.With(fields).Infof("%s %s %d", r.Method, r.URL.Path, recorder.status)
+		})
+	}
+}
+
+// Route records the pattern of the route a request matched in its RequestInfo.
+// It is the first middleware of the router, so that requests refused by the others are logged with their route.
+func Route(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info := RequestInfoFrom(r.Context()); info != nil {
+			info.Route = routePattern(r)
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
+
+func routePattern(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	pattern, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	return pattern
+}
+
+// responseRecorder records the status and the size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err
+}
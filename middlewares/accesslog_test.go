@@ -0,0 +1,123 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+)
+
+// accessLine is a log line of AccessLog, as the logger writes it out of a terminal.
+type accessLine struct {
+	Message string
+	Fields  map[string]interface{}
+}
+
+//nolint:lll // test cases need to be readable
+func TestAccessLog(t *testing.T) {
+	var (
+		out  bytes.Buffer
+		seen string // request ID the handler was served with
+	)
+
+	router := mux.NewRouter()
+	router.Use(Route)
+	router.HandleFunc("/blogs/{blogid}", func(w http.ResponseWriter, r *http.Request) {
+		info := RequestInfoFrom(r.Context())
+		seen = info.ID
+		info.UserID = 2
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})
+	router.HandleFunc("/blogs", func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestInfoFrom(r.Context()).ID
+
+		_, _ = w.Write([]byte("[]"))
+	})
+
+	handler := AccessLog(log.NewMockLogger(&out))(router)
+
+	tests := []struct {
+		description string
+		path        string
+		requestID   string
+		kept        bool
+		fields      map[string]interface{}
+	}{
+		{description: "request ID of the client is kept", path: "/blogs/MSI8WKNSH9", requestID: "req-1.a:b_c", kept: true, fields: map[string]interface{}{"method": "GET", "route": "/blogs/{blogid}", "status": float64(201), "bytes": float64(7), "user_id": float64(2)}},
+		{description: "request without an ID", path: "/blogs", fields: map[string]interface{}{"method": "GET", "route": "/blogs", "status": float64(200), "bytes": float64(2)}},
+		{description: "request ID that is not safe to log", path: "/blogs", requestID: "a b\nc", fields: map[string]interface{}{"route": "/blogs", "status": float64(200)}},
+		{description: "path that matches no route", path: "/nowhere", fields: map[string]interface{}{"route": "", "path": "/nowhere", "status": float64(404), "bytes": float64(19)}},
+	}
+
+	for i, tc := range tests {
+		out.Reset()
+
+		seen = ""
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+		if tc.requestID != "" {
+			r.Header.Set(RequestIDHeader, tc.requestID)
+		}
+
+		handler.ServeHTTP(w, r)
+
+		id := w.Header().Get(RequestIDHeader)
+
+		if tc.kept {
+			assert.Equal(t, tc.requestID, id, "TEST [%v], failed.\n%s", i+1, tc.description)
+		} else {
+			_, err := uuid.Parse(id)
+			assert.NoError(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+
+		if seen != "" {
+			assert.Equal(t, id, seen, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+
+		var line accessLine
+
+		if !assert.NoError(t, json.Unmarshal(out.Bytes(), &line), "TEST [%v], failed.\n%s", i+1, tc.description) {
+			continue
+		}
+
+		assert.Equal(t, id, line.Fields["request_id"], "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		for key, value := range tc.fields {
+			assert.Equal(t, value, line.Fields[key], "TEST [%v], failed.\n%s: %s", i+1, tc.description, key)
+		}
+
+		if _, ok := tc.fields["user_id"]; !ok {
+			assert.NotContains(t, line.Fields, "user_id", "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+func TestResponseRecorder(t *testing.T) {
+	w := httptest.NewRecorder()
+	recorder := &responseRecorder{ResponseWriter: w}
+
+	// the first status is the one sent, and the size of every write is counted
+	recorder.WriteHeader(http.StatusAccepted)
+	recorder.WriteHeader(http.StatusInternalServerError)
+	_, _ = recorder.Write([]byte("abc"))
+	_, _ = recorder.Write([]byte("de"))
+
+	assert.Equal(t, http.StatusAccepted, recorder.status, "TEST, failed.\nstatus of the response")
+	assert.Equal(t, 5, recorder.bytes, "TEST, failed.\nsize of the response")
+
+	implicit := &responseRecorder{ResponseWriter: httptest.NewRecorder()}
+	_, _ = implicit.Write([]byte("abc"))
+
+	assert.Equal(t, http.StatusOK, implicit.status, "TEST, failed.\nwrite without a status")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
 )
 
@@ -18,6 +19,8 @@ func Authentication(logger log.Logger, options auth.Options) func(inner http.Han
 
 	return func(inner http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := requestLogger(r, logger)
+
 			logger.Debugf("request on endpoint: %s", r.URL.String())
 
 			if err, ok := exemptPath(oAuth, r); ok {
@@ -37,6 +40,10 @@ func Authentication(logger log.Logger, options auth.Options) func(inner http.Han
 				jwtIDKey := auth.JWTContextKey("claims")
 				r = r.WithContext(context.WithValue(r.Context(), jwtIDKey, claims))
 
+				if info := RequestInfoFrom(r.Context()); info != nil {
+					info.UserID = claims.UserID
+				}
+
 				logger.Debugf("user_id: %v authorized to make request on %v.", r.Context().Value(jwtIDKey).(*auth.Claims).UserID, r.URL.Path)
 
 				inner.ServeHTTP(w, r)
@@ -60,5 +67,10 @@ func exemptPath(oAuth *auth.OAuth, req *http.Request) (error, bool) {
 		return errors.Error{Type: "login-error", Msg: "logout before logging in"}, false
 	}
 
+	// images are public, like those served from a bucket
+	if req.Method == http.MethodGet && strings.HasPrefix(url, constants.ImagesPath) {
+		return nil, true
+	}
+
 	return nil, strings.HasSuffix(url, "/available") || strings.HasSuffix(url, "/.well-known/jwks.json")
 }
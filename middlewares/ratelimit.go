@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/ratelimit"
+	response2 "github.com/Aakanksha-jais/picshot-golang-backend/pkg/response"
+)
+
+// RateLimit limits the requests on the routes that have a policy, found by the method and pattern of the matched route.
+// Requests are counted by the account of their claims or by the client IP, as the policy says.
+// With trustProxy set, the client IP is the last address of X-Forwarded-For, the one the proxy in front of the app adds.
+// It is not read otherwise, as the header is sent by the client then.
+// A store that fails lets the request through, as it is no fault of the client.
+func RateLimit(logger log.Logger, store ratelimit.Store, policies map[string]ratelimit.Policy, trustProxy bool) func(inner http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routeOf(r)
+
+			policy, ok := policies[route]
+			if !ok {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(route+"|"+clientKey(r, policy.Key, trustProxy), policy, time.Now())
+			if err != nil {
+				requestLogger(r, logger).Errorf("cannot rate limit %s: %s", route, err.Error())
+				inner.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(result.Reset))
+			w.Header().Set("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+seconds(policy.Period))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", seconds(result.RetryAfter))
+				response2.WriteResponse(w, errors.TooManyRequests{RetryAfter: result.RetryAfter}, nil, requestLogger(r, logger))
+
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitRoute is the name a policy of RateLimit is set for.
+func RateLimitRoute(method, pattern string) string {
+	return method + " " + pattern
+}
+
+func routeOf(r *http.Request) string {
+	return RateLimitRoute(r.Method, routePattern(r))
+}
+
+// clientKey identifies the client of a request, by the account of its claims or by its IP.
+func clientKey(r *http.Request, key ratelimit.Key, trustProxy bool) string {
+	if key == ratelimit.ByUser {
+		if claims, ok := r.Context().Value(auth.JWTContextKey("claims")).(*auth.Claims); ok && claims.UserID != 0 {
+			return "user:" + strconv.FormatInt(claims.UserID, 10)
+		}
+	}
+
+	return "ip:" + clientIP(r, trustProxy)
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			addresses := strings.Split(forwarded, ",")
+
+			return strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// seconds writes a duration as whole seconds, rounded up so that a client waiting that long is not refused again.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/ratelimit"
+)
+
+//nolint:lll // test cases need to be readable
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		description string
+		remoteAddr  string
+		forwarded   string
+		trustProxy  bool
+		output      string
+	}{
+		{description: "address of the connection", remoteAddr: "10.0.0.1:1234", output: "10.0.0.1"},
+		{description: "forwarded header sent by the client", remoteAddr: "10.0.0.1:1234", forwarded: "1.1.1.1", output: "10.0.0.1"},
+		{description: "address added by the proxy", remoteAddr: "10.0.0.1:1234", forwarded: "1.1.1.1, 2.2.2.2", trustProxy: true, output: "2.2.2.2"},
+		{description: "proxy that adds no header", remoteAddr: "10.0.0.1:1234", trustProxy: true, output: "10.0.0.1"},
+		{description: "address without a port", remoteAddr: "10.0.0.1", output: "10.0.0.1"},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+		r.RemoteAddr = tc.remoteAddr
+
+		if tc.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tc.forwarded)
+		}
+
+		assert.Equal(t, tc.output, clientIP(r, tc.trustProxy), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	withClaims := r.WithContext(context.WithValue(r.Context(), auth.JWTContextKey("claims"), &auth.Claims{UserID: 2}))
+
+	assert.Equal(t, "user:2", clientKey(withClaims, ratelimit.ByUser, false), "TEST, failed.\naccount of the claims")
+	assert.Equal(t, "ip:10.0.0.1", clientKey(withClaims, ratelimit.ByIP, false), "TEST, failed.\nclient IP of a request with claims")
+	assert.Equal(t, "ip:10.0.0.1", clientKey(r, ratelimit.ByUser, false), "TEST, failed.\nclient IP of a request without claims")
+}
+
+//nolint:lll // test cases need to be readable
+func TestRateLimit(t *testing.T) {
+	policies := map[string]ratelimit.Policy{RateLimitRoute(http.MethodPost, "/login"): {Limit: 1, Period: time.Minute, Key: ratelimit.ByIP}}
+
+	router := mux.NewRouter()
+	router.Use(RateLimit(log.NewLogger(), ratelimit.NewMemory(), policies, false))
+	router.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodPost)
+	router.HandleFunc("/blogs", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	tests := []struct {
+		description string
+		method      string
+		path        string
+		status      int
+		headers     map[string]string
+	}{
+		{description: "request within the limit", method: http.MethodPost, path: "/login", status: http.StatusOK, headers: map[string]string{"RateLimit-Limit": "1", "RateLimit-Remaining": "0", "RateLimit-Reset": "60", "RateLimit-Policy": "1;w=60", "Retry-After": ""}},
+		{description: "request over the limit", method: http.MethodPost, path: "/login", status: http.StatusTooManyRequests, headers: map[string]string{"RateLimit-Remaining": "0", "Retry-After": "60"}},
+		{description: "route without a policy", method: http.MethodGet, path: "/blogs", status: http.StatusOK, headers: map[string]string{"RateLimit-Limit": ""}},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		for header, value := range tc.headers {
+			assert.Equal(t, value, w.Header().Get(header), "TEST [%v], failed.\n%s: %s", i+1, tc.description, header)
+		}
+	}
+}
@@ -14,9 +14,17 @@ type Account struct {
 	Blogs      []Blog       // List of Blogs posted by Account
 	CreatedAt  time.Time    // Time of Creation of Account
 	DelRequest sql.NullTime // Time Stamp of Account Delete Request
-	Status     string       // Account Active or Inactive
+	Status     string       // Account Active, Inactive or Suspended
+	Role       string       // Role of the Account, user or moderator
 }
 
+// Roles of an Account, and the status of a suspended Account.
+const (
+	RoleUser         = "USER"
+	RoleModerator    = "MODERATOR"
+	AccountSuspended = "SUSPENDED"
+)
+
 const (
 	UserName  = `user_name`
 	Email     = `email`
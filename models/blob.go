@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Blob is the content of a picture, stored once however many images show it.
+// Its renditions are named after the hash of the content, and its references are the images that show it.
+type Blob struct {
+	Hash      string    `bson:"_id" json:"hash"`              // SHA-256 of the uploaded file, hex encoded
+	Image     Image     `bson:"image" json:"image"`           // Renditions of the content
+	Refs      int64     `bson:"refs" json:"refs"`             // Number of images that show the content
+	CreatedOn time.Time `bson:"created_on" json:"created_on"` // Time the content was first stored
+}
@@ -6,16 +6,104 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-// Blog is filterable by blog id, account id and title ONLY.
+// Blogs can be listed newest first (default) or oldest first.
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+)
+
+// A blog is written as a draft, or scheduled to be published at a later time.
+// Only published blogs are visible to everyone, the rest are visible to their author alone.
+// Blogs created before the status existed have none, and are published.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// A published blog is listed to everyone (public), reachable by its link alone (unlisted),
+// visible to the followers of its author, or to its author alone (private).
+// Blogs created before the visibility existed have none, and are public.
+const (
+	VisibilityPublic    = "public"
+	VisibilityUnlisted  = "unlisted"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+)
+
+// The content of a blog is written as plain text or markdown, and served rendered to HTML as well.
+// Blogs created before the format existed have none, and are plain text.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// Blog is filterable by blog id, account id, title and status ONLY.
 type Blog struct {
-	BlogID    string    `bson:"_id" json:"blog_id"`           // Unique Blog ID
-	AccountID int64     `bson:"account_id" json:"account_id"` // ID of Account associated with the Blog
-	Title     string    `bson:"title" json:"title"`           // Title of Blog
-	Summary   string    `bson:"summary" json:"summary"`       // Summary by-line
-	Content   string    `bson:"content" json:"content"`       // Detailed Content of Blog
-	Tags      []string  `bson:"tags" json:"tags,omitempty"`   // List of Tags associated with the Blog
-	CreatedOn time.Time `bson:"created_on" json:"created_on"` // Date of Creation of Blog
-	Images    []string  `bson:"images" json:"images"`         // URL of images stored in cloud
+	BlogID        string     `bson:"_id" json:"blog_id"`                               // Unique Blog ID
+	AccountID     int64      `bson:"account_id" json:"account_id"`                     // ID of Account associated with the Blog
+	Title         string     `bson:"title" json:"title"`                               // Title of Blog
+	Slug          string     `bson:"slug,omitempty" json:"slug,omitempty"`             // URL-safe name of the Blog, unique per Account
+	OldSlugs      []string   `bson:"old_slugs,omitempty" json:"-"`                     // Slugs the Blog had before, redirected to the Slug
+	Summary       string     `bson:"summary" json:"summary"`                           // Summary by-line
+	Content       string     `bson:"content" json:"content"`                           // Detailed Content of Blog
+	ContentFormat string     `bson:"content_format,omitempty" json:"content_format"`   // Format of the Content, plain or markdown
+	ContentHTML   string     `bson:"content_html,omitempty" json:"content_html"`       // Content rendered to sanitized HTML
+	RenderVersion int        `bson:"render_version,omitempty" json:"-"`                // Version of the markup policy the HTML is rendered by
+	Tags          []string   `bson:"tags" json:"tags,omitempty"`                       // List of Tags associated with the Blog
+	CreatedOn     time.Time  `bson:"created_on" json:"created_on"`                     // Date of Creation of Blog
+	Images        []Image    `bson:"images" json:"images"`                             // Images stored in cloud, in every size
+	Likes         int64      `bson:"likes,omitempty" json:"likes"`                     // Number of Likes on the Blog
+	Status        string     `bson:"status,omitempty" json:"status,omitempty"`         // Draft, scheduled, published or archived
+	Visibility    string     `bson:"visibility,omitempty" json:"visibility,omitempty"` // Public, unlisted, followers or private
+	Hidden        bool       `bson:"hidden,omitempty" json:"hidden,omitempty"`         // Hidden by a moderator from everyone but the author
+	Notice        string     `bson:"-" json:"notice,omitempty"`                        // Notice to the author of a hidden Blog
+	PublishAt     *time.Time `bson:"publish_at,omitempty" json:"publish_at,omitempty"` // Time at which a scheduled Blog is published
+	Uploads       []string   `bson:"-" json:"-"`                                       // IDs of confirmed Uploads to attach, on create and update only
+}
+
+// Format returns the format of the content, plain text unless it is set.
+func (b Blog) Format() string {
+	if b.ContentFormat == "" {
+		return FormatPlain
+	}
+
+	return b.ContentFormat
+}
+
+// IsPublished tells if the blog is visible to everyone.
+func (b Blog) IsPublished() bool {
+	return b.Status == "" || b.Status == StatusPublished
+}
+
+// IsPublic tells if the blog is listed to everyone once it is published.
+func (b Blog) IsPublic() bool {
+	return b.Visibility == "" || b.Visibility == VisibilityPublic
+}
+
+// IsListed tells if the blog is listed to everyone: published, public and not hidden by a moderator.
+// Tags list such blogs alone, so that the tag directory counts no blog the viewer cannot see.
+func (b Blog) IsListed() bool {
+	return b.IsPublished() && b.IsPublic() && !b.Hidden
+}
+
+// PublishedFilter matches the blogs that are published.
+func PublishedFilter() bson.E {
+	return bson.E{Key: "status", Value: bson.M{"$in": bson.A{StatusPublished, nil}}}
+}
+
+// ListedFilter matches the blogs that are listed to a viewer who follows the given accounts:
+// published blogs that are public, or visible to the followers of an account the viewer follows.
+// Unlisted, private and hidden blogs are never listed to anyone but their author.
+func ListedFilter(following []int64) bson.D {
+	visible := bson.A{bson.M{"visibility": bson.M{"$in": bson.A{VisibilityPublic, nil}}}}
+
+	if len(following) != 0 {
+		visible = append(visible, bson.M{"visibility": VisibilityFollowers, "account_id": bson.M{"$in": following}})
+	}
+
+	return bson.D{PublishedFilter(), {Key: "hidden", Value: bson.M{"$ne": true}}, {Key: "$or", Value: visible}}
 }
 
 func (b Blog) GetFilter() bson.D {
@@ -33,5 +121,91 @@ func (b Blog) GetFilter() bson.D {
 		filter = append(filter, bson.E{Key: "title", Value: b.Title})
 	}
 
+	if b.Status != "" {
+		filter = append(filter, bson.E{Key: "status", Value: b.Status})
+	}
+
 	return filter
 }
+
+// BlogFilter narrows down a listing of blogs beyond the exact matches of Blog.
+// Zero values of the fields do not filter.
+type BlogFilter struct {
+	Blog                    // Exact match on blog id, account id and title
+	CreatedAfter  time.Time // Blogs created on or after this time
+	CreatedBefore time.Time // Blogs created before this time
+	Tags          []string  // Blogs having any of the tags
+	AllTags       bool      // Blogs having all of the tags, instead of any
+	Author        string    // Username of the author, resolved to the account id by the service
+	HasImages     *bool     // Blogs with (or without) images
+	Sort          string    // Sort order, newest first if empty
+	Viewer        int64     // Account id of the viewer, who sees their own unpublished blogs as well
+	Following     []int64   // Account ids the viewer follows, resolved by the service, whose blogs for followers are listed
+	IDs           []string  // Blogs among the IDs
+	Hidden        []int64   // Account ids hidden from the viewer by a block or a mute, resolved by the service
+}
+
+func (f BlogFilter) GetFilter() bson.D {
+	filter := f.Blog.GetFilter()
+
+	switch {
+	case f.Status != "" && f.Status != StatusPublished:
+		// the service limits unpublished statuses to the blogs of the viewer
+	case f.Viewer != 0:
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			ListedFilter(f.Following),
+			bson.D{{Key: "account_id", Value: f.Viewer}},
+		}})
+	default:
+		filter = append(filter, ListedFilter(nil)...)
+	}
+
+	if f.IDs != nil {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.M{"$in": f.IDs}})
+	}
+
+	// a listing by an author is hidden by the service instead, as the author is filtered already
+	if len(f.Hidden) != 0 && f.AccountID == 0 {
+		filter = append(filter, bson.E{Key: "account_id", Value: bson.M{"$nin": f.Hidden}})
+	}
+
+	created := bson.M{}
+
+	if !f.CreatedAfter.IsZero() {
+		created["$gte"] = f.CreatedAfter
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		created["$lt"] = f.CreatedBefore
+	}
+
+	if len(created) != 0 {
+		filter = append(filter, bson.E{Key: "created_on", Value: created})
+	}
+
+	if len(f.Tags) != 0 {
+		operator := "$in"
+		if f.AllTags {
+			operator = "$all"
+		}
+
+		filter = append(filter, bson.E{Key: "tags", Value: bson.M{operator: f.Tags}})
+	}
+
+	if f.HasImages != nil {
+		// a blog has images if its first image exists
+		filter = append(filter, bson.E{Key: "images.0", Value: bson.M{"$exists": *f.HasImages}})
+	}
+
+	return filter
+}
+
+// GetSort returns the sort order of the listing. Ties are broken by _id, so that pages are stable.
+func (f BlogFilter) GetSort() bson.D {
+	switch f.Sort {
+	case SortOldest:
+		return bson.D{{Key: "created_on", Value: 1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "created_on", Value: -1}, {Key: "_id", Value: -1}}
+	}
+}
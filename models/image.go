@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Image is a picture of a Blog, stored in a rendition for every size.
+type Image struct {
+	ID        string    `bson:"id" json:"id"`               // Unique Image ID
+	Thumbnail Rendition `bson:"thumbnail" json:"thumbnail"` // Smallest rendition, for listings
+	Medium    Rendition `bson:"medium" json:"medium"`       // Rendition for screens of phones
+	Full      Rendition `bson:"full" json:"full"`           // Largest rendition
+	Hash      string    `bson:"hash,omitempty" json:"-"`    // Hash of the Blob the renditions belong to, empty for images stored before blobs
+}
+
+// Rendition is one size of an Image.
+type Rendition struct {
+	URL    string `bson:"url" json:"url"`                           // URL of the rendition stored in cloud
+	Width  int    `bson:"width,omitempty" json:"width,omitempty"`   // Width in pixels, unknown for images stored before renditions
+	Height int    `bson:"height,omitempty" json:"height,omitempty"` // Height in pixels, unknown for images stored before renditions
+}
+
+// ImageFromURL describes an image stored before renditions were generated, as the bare URL of the upload.
+// Every rendition is the upload itself, and its file name is its ID.
+func ImageFromURL(url string) Image {
+	rendition := Rendition{URL: url}
+
+	return Image{ID: fileName(url), Thumbnail: rendition, Medium: rendition, Full: rendition}
+}
+
+// Renditions returns the renditions of the image, smallest first.
+func (i Image) Renditions() []Rendition {
+	return []Rendition{i.Thumbnail, i.Medium, i.Full}
+}
+
+// FileNames returns the names of the files of all the renditions in the storage.
+func (i Image) FileNames() []string {
+	names := make([]string, 0, 3)
+	seen := make(map[string]bool)
+
+	for _, rendition := range i.Renditions() {
+		if name := fileName(rendition.URL); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// UnmarshalBSONValue decodes an image, which is a bare URL for blogs written before renditions were generated.
+func (i *Image) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+
+	if url, ok := raw.StringValueOK(); ok {
+		*i = ImageFromURL(url)
+		return nil
+	}
+
+	type image Image // decodes the document as usual, without this method
+
+	var img image
+
+	if err := raw.Unmarshal(&img); err != nil {
+		return fmt.Errorf("cannot decode image: %w", err)
+	}
+
+	*i = Image(img)
+
+	return nil
+}
+
+func fileName(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// StoredFile is a file in the image storage.
+type StoredFile struct {
+	Name       string
+	Size       int64 // bytes
+	ModifiedOn time.Time
+}
+
+// OrphanReport totals a collection of the files in the image storage that no blog refers to.
+type OrphanReport struct {
+	DryRun        bool  `json:"dry_run"`        // orphans are counted and not deleted
+	Scanned       int   `json:"scanned"`        // files listed in the storage
+	Referenced    int   `json:"referenced"`     // files that blogs refer to
+	Recent        int   `json:"recent"`         // files no blog refers to, within the grace period
+	Orphaned      int   `json:"orphaned"`       // files no blog refers to, past the grace period
+	OrphanedBytes int64 `json:"orphaned_bytes"` // size of the orphaned files
+	Deleted       int   `json:"deleted"`        // orphaned files deleted
+	Failed        int   `json:"failed"`         // orphaned files that could not be deleted
+}
@@ -1,6 +1,91 @@
 package models
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
 type Page struct {
 	Limit  int64
-	PageNo int64
+	PageNo int64   // Page Number, kept for clients that have not moved to cursors yet
+	Cursor *Cursor // Position at which the page starts, takes precedence over the page number
+}
+
+// Cursor is a position in a list, handed to clients as an opaque string.
+// Chronological lists of blogs are paged by (created_on, _id) of the last blog of the previous page,
+// which is stable when blogs are inserted while scrolling. Ranked lists are paged by offset.
+type Cursor struct {
+	CreatedOn time.Time `json:"c,omitempty"`
+	ID        string    `json:"i,omitempty"`
+	Offset    int64     `json:"o,omitempty"`
+}
+
+// Paginated is a page of a list, along with the cursor of the next page.
+// NextCursor is empty on the last page.
+type Paginated struct {
+	Data       interface{}
+	NextCursor string
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor handed out by Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// IsKeyset tells if the page starts after a (created_on, _id) position.
+func (p *Page) IsKeyset() bool {
+	return p != nil && p.Cursor != nil && p.Cursor.ID != ""
+}
+
+// Skip returns the number of items before the page, for offset cursors and page numbers.
+func (p *Page) Skip() int64 {
+	switch {
+	case p == nil:
+		return 0
+	case p.Cursor != nil:
+		return p.Cursor.Offset
+	case p.PageNo > 1:
+		return (p.PageNo - 1) * p.Limit
+	default:
+		return 0
+	}
+}
+
+// NextBlogCursor returns the cursor of the page after the blogs, or an empty string if there is none.
+func NextBlogCursor(blogs []*Blog, page *Page) string {
+	if page == nil || page.Limit == 0 || int64(len(blogs)) < page.Limit {
+		return ""
+	}
+
+	last := blogs[len(blogs)-1]
+
+	return Cursor{CreatedOn: last.CreatedOn, ID: last.BlogID}.Encode()
+}
+
+// NextOffsetCursor returns the cursor of the page after a page of n ranked items, or an empty string if there is none.
+func NextOffsetCursor(n int, page *Page) string {
+	if page == nil || page.Limit == 0 || int64(n) < page.Limit {
+		return ""
+	}
+
+	return Cursor{Offset: page.Skip() + int64(n)}.Encode()
 }
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Kinds of content a Report is about.
+const (
+	ReportBlog = "blog"
+	ReportUser = "user"
+)
+
+// Reasons a blog or a user is reported for.
+const (
+	ReasonSpam          = "spam"
+	ReasonHarassment    = "harassment"
+	ReasonHateSpeech    = "hate_speech"
+	ReasonViolence      = "violence"
+	ReasonNudity        = "nudity"
+	ReasonImpersonation = "impersonation"
+	ReasonOther         = "other"
+)
+
+// Statuses of a Report.
+const (
+	ReportOpen      = "open"      // waiting in the moderation queue
+	ReportResolved  = "resolved"  // acted upon by a moderator
+	ReportDismissed = "dismissed" // reviewed by a moderator, and left as it is
+)
+
+// FilterReporter is the ReporterID of the reports filed by the content filter.
+const FilterReporter = 0
+
+// Report is a blog or a user flagged by an account for a moderator to review.
+// An account has a single open report on a blog or a user.
+type Report struct {
+	ID         string     `bson:"_id" json:"report_id"`                               // Unique Report ID
+	Kind       string     `bson:"kind" json:"kind"`                                   // Blog or User
+	BlogID     string     `bson:"blog_id,omitempty" json:"blog_id,omitempty"`         // ID of the reported Blog
+	UserID     int64      `bson:"user_id" json:"user_id"`                             // ID of the reported Account, or of the author of the reported Blog
+	ReporterID int64      `bson:"reporter_id" json:"reporter_id"`                     // ID of Account that made the Report, or FilterReporter
+	Reason     string     `bson:"reason" json:"reason"`                               // Reason code of the Report
+	Details    string     `bson:"details,omitempty" json:"details,omitempty"`         // Details given by the reporter
+	Status     string     `bson:"status" json:"status"`                               // Open, resolved or dismissed
+	CreatedOn  time.Time  `bson:"created_on" json:"created_on"`                       // Time of creation of the Report
+	ActionID   string     `bson:"action_id,omitempty" json:"action_id,omitempty"`     // ID of the ModerationAction that closed the Report
+	ResolvedOn *time.Time `bson:"resolved_on,omitempty" json:"resolved_on,omitempty"` // Time the Report was closed
+}
+
+// ReportFilter filters the moderation queue. Reports are listed oldest first.
+type ReportFilter struct {
+	ID     string
+	Status string // Open if empty
+	Kind   string
+	Reason string
+	BlogID string
+	UserID int64
+}
+
+func (f ReportFilter) GetFilter() bson.D {
+	status := f.Status
+	if status == "" {
+		status = ReportOpen
+	}
+
+	filter := bson.D{{Key: "status", Value: status}}
+
+	if f.ID != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: f.ID})
+	}
+
+	if f.Kind != "" {
+		filter = append(filter, bson.E{Key: "kind", Value: f.Kind})
+	}
+
+	if f.Reason != "" {
+		filter = append(filter, bson.E{Key: "reason", Value: f.Reason})
+	}
+
+	if f.BlogID != "" {
+		filter = append(filter, bson.E{Key: "blog_id", Value: f.BlogID})
+	}
+
+	if f.UserID != 0 {
+		filter = append(filter, bson.E{Key: "user_id", Value: f.UserID})
+	}
+
+	if f.Status != "" {
+		filter = append(filter, bson.E{Key: "status", Value: f.Status})
+	}
+
+	return filter
+}
+
+// Actions a moderator takes.
+const (
+	ActionHideBlog   = "hide_blog"   // hides a blog from everyone but its author
+	ActionUnhideBlog = "unhide_blog" // lists a hidden blog again
+	ActionWarn       = "warn"        // warns an account, which sees the warning
+	ActionSuspend    = "suspend"     // suspends an account, which cannot log in any more
+	ActionDismiss    = "dismiss"     // closes a report without any other action
+)
+
+// Statuses of a ModerationAction. An action is recorded as pending before it is taken,
+// so that no action is taken without a record of it, and is completed or failed once it is taken.
+const (
+	ActionPending   = "pending"
+	ActionCompleted = "completed"
+	ActionFailed    = "failed"
+)
+
+// ModerationAction is an entry of the audit trail of moderation, recorded for every action a moderator takes.
+// Actions are never deleted, and are updated only with the status they end in.
+type ModerationAction struct {
+	ID          string    `bson:"_id" json:"action_id"`                           // Unique ModerationAction ID
+	Action      string    `bson:"action" json:"action"`                           // Action taken
+	ModeratorID int64     `bson:"moderator_id" json:"moderator_id,omitempty"`     // ID of Account of the moderator
+	BlogID      string    `bson:"blog_id,omitempty" json:"blog_id,omitempty"`     // ID of the Blog acted upon
+	UserID      int64     `bson:"user_id" json:"user_id"`                         // ID of the Account acted upon, or of the author of the Blog
+	UserName    string    `bson:"-" json:"user_name,omitempty"`                   // Username of the Account acted upon, on input
+	ReportID    string    `bson:"report_id,omitempty" json:"report_id,omitempty"` // ID of the Report the action is taken on
+	Note        string    `bson:"note,omitempty" json:"note,omitempty"`           // Note of the moderator, shown to the account for a warning
+	Status      string    `bson:"status" json:"status"`                           // Pending, completed or failed
+	CreatedOn   time.Time `bson:"created_on" json:"created_on"`                   // Time of the action
+}
+
+// ModerationFilter filters the audit trail. Actions are listed latest first.
+type ModerationFilter struct {
+	Action      string
+	ModeratorID int64
+	BlogID      string
+	UserID      int64
+	Status      string
+}
+
+func (f ModerationFilter) GetFilter() bson.D {
+	filter := bson.D{}
+
+	if f.Action != "" {
+		filter = append(filter, bson.E{Key: "action", Value: f.Action})
+	}
+
+	if f.ModeratorID != 0 {
+		filter = append(filter, bson.E{Key: "moderator_id", Value: f.ModeratorID})
+	}
+
+	if f.BlogID != "" {
+		filter = append(filter, bson.E{Key: "blog_id", Value: f.BlogID})
+	}
+
+	if f.UserID != 0 {
+		filter = append(filter, bson.E{Key: "user_id", Value: f.UserID})
+	}
+
+	return filter
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Revision is an immutable snapshot of the text and tags of a blog, recorded at its creation and at every update.
+type Revision struct {
+	BlogID        string    `bson:"blog_id" json:"blog_id"`                                   // ID of the Blog
+	Number        int64     `bson:"rev" json:"rev"`                                           // Revision Number, starting at 1 for every Blog
+	AccountID     int64     `bson:"account_id" json:"account_id"`                             // ID of Account that made the change
+	CreatedOn     time.Time `bson:"created_on" json:"created_on"`                             // Time of the change
+	Title         string    `bson:"title" json:"title"`                                       // Title of Blog
+	Summary       string    `bson:"summary" json:"summary"`                                   // Summary by-line
+	Content       string    `bson:"content" json:"content"`                                   // Detailed Content of Blog
+	ContentFormat string    `bson:"content_format,omitempty" json:"content_format,omitempty"` // Format of the Content, plain or markdown
+	Tags          []string  `bson:"tags" json:"tags,omitempty"`                               // List of Tags associated with the Blog
+	Changes       []string  `bson:"changes" json:"changes"`                                   // Summary of the changes from the previous revision
+}
@@ -0,0 +1,8 @@
+package models
+
+// SearchHit is a single blog that matched a search query.
+type SearchHit struct {
+	Blog       *Blog             `json:"blog"`                 // Blog that matched the query
+	Score      float64           `json:"score"`                // Relevance of the blog to the query
+	Highlights map[string]string `json:"highlights,omitempty"` // Matched fragments keyed by field name
+}
@@ -1,6 +1,30 @@
 package models
 
+import "time"
+
+// Tags can be sorted by popularity (number of blogs) or by name.
+const (
+	TagSortPopularity = "popularity"
+	TagSortName       = "name"
+)
+
 type Tag struct {
-	Name       string   `bson:"_id" json:"name"`                  // Tag Name that appears
-	BlogIDList []string `bson:"blog_id_list" json:"blog_id_list"` // List of BlogIDs associated with the Tag
+	Name       string   `bson:"_id" json:"name"`                            // Tag Name that appears
+	BlogIDList []string `bson:"blog_id_list" json:"blog_id_list,omitempty"` // List of BlogIDs associated with the Tag
+	BlogCount  int64    `bson:"blog_count,omitempty" json:"blog_count"`     // Number of Blogs associated with the Tag
+}
+
+// TagEvent records that a tag was attached to a blog at a point in time.
+type TagEvent struct {
+	Tag       string    `bson:"tag" json:"tag"`               // Tag Name
+	BlogID    string    `bson:"blog_id" json:"blog_id"`       // ID of the Blog the Tag was attached to
+	CreatedOn time.Time `bson:"created_on" json:"created_on"` // Time at which the Tag was attached
+}
+
+// TrendingTag compares the usage of a tag within a time window to its usage in the window before it.
+type TrendingTag struct {
+	Name          string  `bson:"_id" json:"name"`                      // Tag Name
+	Count         int64   `bson:"count" json:"count"`                   // Number of times the Tag was used within the window
+	BaselineCount int64   `bson:"baseline_count" json:"baseline_count"` // Number of times the Tag was used in the previous window
+	Growth        float64 `bson:"growth" json:"growth"`                 // Relative growth in usage over the baseline
 }
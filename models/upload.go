@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Statuses of an Upload.
+const (
+	UploadPending   = "pending"   // URL issued, file not confirmed yet
+	UploadConfirmed = "confirmed" // file checked and processed into an Image, ready to be attached to a blog
+)
+
+// Upload is a picture uploaded by a client straight to the storage, through a presigned URL.
+// It is confirmed once the file is in the storage, and then attached to a blog, which uses the upload up.
+// Uploads that are not attached before they expire are deleted along with their files.
+type Upload struct {
+	ID          string            `bson:"_id" json:"id"`                          // Unique Upload ID
+	AccountID   int64             `bson:"account_id" json:"-"`                    // ID of Account that uploads the file
+	Name        string            `bson:"name" json:"-"`                          // Name of the uploaded file in the storage
+	ContentType string            `bson:"content_type" json:"content_type"`       // Content type declared for the file
+	Size        int64             `bson:"size" json:"size"`                       // Size of the file in bytes, declared for the file
+	Status      string            `bson:"status" json:"status"`                   // Status of the Upload
+	Image       *Image            `bson:"image,omitempty" json:"image,omitempty"` // Image the file is processed into on confirmation
+	CreatedOn   time.Time         `bson:"created_on" json:"created_on"`           // Time of creation of the Upload
+	ExpiresAt   time.Time         `bson:"expires_at" json:"expires_at"`           // Time the Upload must be confirmed (or attached) by
+	URL         string            `bson:"-" json:"url,omitempty"`                 // Presigned URL to PUT the file at
+	Headers     map[string]string `bson:"-" json:"headers,omitempty"`             // Headers to be sent along with the file
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Aakanksha-jais/picshot-golang-backend/middlewares"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/ratelimit"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
 
@@ -18,6 +20,8 @@ import (
 
 type App struct {
 	server *server
+	jobs   []job
+	guards []Guard
 	log.Logger
 	configs.Config
 	datastore.DataStore
@@ -69,7 +73,34 @@ func (a *App) add(method, pattern string, h Handler) {
 	a.server.Router.Add(method, pattern, h)
 }
 
-func (a App) Start() {
+// RateLimit limits the requests on a route, registered by its method and pattern, by a token bucket policy.
+// Limits are to be set before the app is started.
+func (a *App) RateLimit(method, pattern string, policy ratelimit.Policy) {
+	a.server.rateLimits[middlewares.RateLimitRoute(method, pattern)] = policy
+}
+
+// Guard adds a check that every request is to pass before its Handler is called.
+// Guards are to be added before the app is started.
+func (a *App) Guard(guard Guard) {
+	a.guards = append(a.guards, guard)
+}
+
+// Files serves the files of a directory for http GET method under a path prefix.
+func (a *App) Files(prefix, dir string) {
+	a.server.Router.Files(prefix, dir)
+}
+
+// withLogger returns a copy of the app that logs with the logger, for the context of a request to log with the request ID.
+func (a *App) withLogger(logger log.Logger) *App {
+	app := *a
+	app.Logger = logger
+
+	return &app
+}
+
+func (a *App) Start() {
+	a.startJobs()
+
 	a.server.Start(a.Logger)
 }
 
@@ -18,10 +18,11 @@ func NewContext(r *Request, w http.ResponseWriter, app *App) *Context {
 	return &Context{Request: r, w: w, App: app}
 }
 
-func (c *Context) reset(r *Request, w http.ResponseWriter) {
+func (c *Context) reset(r *Request, w http.ResponseWriter, app *App) {
 	c.Context = nil
 	c.Request = r
 	c.w = w
+	c.App = app
 }
 
 func (c *Context) SetAuthHeader(token string) {
@@ -34,3 +35,23 @@ func (c *Context) SetStatus(status int) error {
 
 	return err.New("do not set header")
 }
+
+// WithCancel returns a copy of the context that is done when cancel is called, or when the context is done.
+func (c *Context) WithCancel() (*Context, context.CancelFunc) {
+	child := *c
+
+	var cancel context.CancelFunc
+
+	child.Context, cancel = context.WithCancel(c.Context)
+
+	return &child, cancel
+}
+
+// Detach returns a copy of the context that is not cancelled along with the request, for work that has to be
+// completed even if the client goes away, such as undoing a failed write. The values of the request are not carried over.
+func (c *Context) Detach() *Context {
+	detached := *c
+	detached.Context = context.Background()
+
+	return &detached
+}
@@ -8,8 +8,20 @@ import (
 
 type Handler func(c *Context) (interface{}, error)
 
+// Guard checks a request before it is handled. A request it returns an error for is refused with the error.
+type Guard func(c *Context) error
+
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, _ := r.Context().Value(appContextKey).(*Context)
+
+	for _, guard := range ctx.guards {
+		if err := guard(ctx); err != nil {
+			response2.WriteResponse(w, err, nil, ctx.Logger)
+
+			return
+		}
+	}
+
 	data, err := h(ctx)
 
 	response2.WriteResponse(w, err, data, ctx.Logger)
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// Job is a task that runs in the background, outside of any request.
+type Job func(c *Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// Every adds a Job that runs at every interval, once the app is started.
+// A failed run is logged, and the job runs again at the next interval.
+func (a *App) Every(name string, interval time.Duration, run Job) {
+	a.jobs = append(a.jobs, job{name: name, interval: interval, run: run})
+}
+
+func (a *App) startJobs() {
+	for _, j := range a.jobs {
+		go a.schedule(j)
+	}
+}
+
+func (a *App) schedule(j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := NewContext(nil, nil, a)
+		ctx.Context = context.Background()
+
+		if err := j.run(ctx); err != nil {
+			a.Errorf("job %s failed: %s", j.name, err.Error())
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 
@@ -48,21 +50,133 @@ func (r *Request) PathParam(key string) string {
 	return r.pathParams[key]
 }
 
-const maxSize = int64(32 << 20) // max 32 MB size
+// maxMemory bounds the part of a multipart form kept in memory, files beyond it are spilled to temporary files
+// that are removed once the request is served.
+const maxMemory = int64(1 << 20)
 
-func (r *Request) ParseImages() []*multipart.FileHeader {
-	err := r.req.ParseMultipartForm(maxSize)
-	if err != nil {
-		return nil
+// ParseImages reads the "image" files of a multipart form, whose body may be of limit bytes at most.
+// A request without a multipart form has no images.
+func (r *Request) ParseImages(limit int64) ([]*multipart.FileHeader, error) {
+	if r.req.ContentLength > limit {
+		return nil, errors.UploadTooLarge{Limit: limit}
+	}
+
+	body := &limitedBody{ReadCloser: r.req.Body, limit: limit, remaining: limit}
+	r.req.Body = body
+
+	err := r.req.ParseMultipartForm(maxMemory)
+
+	switch {
+	case body.exceeded:
+		return nil, errors.UploadTooLarge{Limit: limit}
+	case err == http.ErrNotMultipart:
+		return nil, nil
+	case err != nil:
+		return nil, errors.BodyRead{Err: err}
+	}
+
+	return r.req.MultipartForm.File["image"], nil
+}
+
+// limitedBody fails the reads of a body beyond its limit, and records that it did.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// a body of exactly the limit is fine, one more byte is not
+		n, _ := b.ReadCloser.Read(make([]byte, 1))
+		if n > 0 {
+			b.exceeded = true
+			return 0, errors.UploadTooLarge{Limit: b.limit}
+		}
+
+		return 0, io.EOF
 	}
 
-	return r.req.MultipartForm.File["image"]
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err
+}
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+	maxPageSkip      = 100_000 // items before a page, deeper pages are not served
+)
+
+// Page reads the pagination params of a list request.
+// A page is asked for by an opaque cursor, or by a page number for older clients.
+// The limit defaults to 20 items and is capped at 100. A page that starts before the first item,
+// or more than 100000 items after it, is invalid.
+func (r *Request) Page() (*models.Page, error) {
+	page := &models.Page{Limit: defaultPageLimit}
+
+	if l := r.QueryParam("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return nil, errors.InvalidParam{Param: "limit"}
+		}
+
+		if limit < maxPageLimit {
+			page.Limit = int64(limit)
+		} else {
+			page.Limit = maxPageLimit
+		}
+	}
+
+	if c := r.QueryParam("cursor"); c != "" {
+		cursor, err := models.DecodeCursor(c)
+		if err != nil {
+			return nil, errors.InvalidParam{Param: "cursor"}
+		}
+
+		// the offset of a cursor is read from the client, which may have tampered with it
+		if cursor.Offset < 0 || cursor.Offset > maxPageSkip {
+			return nil, errors.InvalidParam{Param: "cursor"}
+		}
+
+		page.Cursor = cursor
+
+		return page, nil
+	}
+
+	if p := r.QueryParam("pageno"); p != "" {
+		pageNo, err := strconv.Atoi(p)
+		if err != nil || pageNo <= 0 || int64(pageNo-1) > maxPageSkip/page.Limit {
+			return nil, errors.InvalidParam{Param: "pageno"}
+		}
+
+		page.PageNo = int64(pageNo)
+	}
+
+	return page, nil
 }
 
 func (r *Request) FormValue(key string) string {
 	return r.req.FormValue(key)
 }
 
+// FormValues returns all the values of a form field, and whether the field was sent at all.
+func (r *Request) FormValues(key string) ([]string, bool) {
+	if r.req.Form == nil {
+		_ = r.req.ParseMultipartForm(maxMemory)
+	}
+
+	values, ok := r.req.Form[key]
+
+	return values, ok
+}
+
 func (r *Request) Unmarshal(i interface{}) error {
 	body, err := r.body()
 	if err != nil {
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+//nolint:lll // test cases need to be readable
+func TestRequest_Page(t *testing.T) {
+	tests := []struct {
+		description string
+		query       string
+		output      *models.Page
+		err         error
+	}{
+		{description: "default page", query: "", output: &models.Page{Limit: 20}},
+		{description: "limit and page number", query: "?limit=10&pageno=3", output: &models.Page{Limit: 10, PageNo: 3}},
+		{description: "limit over the cap", query: "?limit=1000", output: &models.Page{Limit: 100}},
+		{description: "offset cursor", query: "?cursor=" + models.Cursor{Offset: 40}.Encode(), output: &models.Page{Limit: 20, Cursor: &models.Cursor{Offset: 40}}},
+		{description: "last page served", query: "?limit=100&pageno=1001", output: &models.Page{Limit: 100, PageNo: 1001}},
+		{description: "negative limit", query: "?limit=-1", err: errors.InvalidParam{Param: "limit"}},
+		{description: "limit not a number", query: "?limit=ten", err: errors.InvalidParam{Param: "limit"}},
+		{description: "page number zero", query: "?pageno=0", err: errors.InvalidParam{Param: "pageno"}},
+		{description: "negative page number", query: "?pageno=-2", err: errors.InvalidParam{Param: "pageno"}},
+		{description: "page number too deep", query: "?limit=100&pageno=1002", err: errors.InvalidParam{Param: "pageno"}},
+		{description: "page number that overflows", query: "?pageno=9223372036854775807", err: errors.InvalidParam{Param: "pageno"}},
+		{description: "cursor not encoded", query: "?cursor=not-a-cursor!", err: errors.InvalidParam{Param: "cursor"}},
+		{description: "negative offset", query: "?cursor=" + models.Cursor{Offset: -20}.Encode(), err: errors.InvalidParam{Param: "cursor"}},
+		{description: "offset too deep", query: "?cursor=" + models.Cursor{Offset: 100_001}.Encode(), err: errors.InvalidParam{Param: "cursor"}},
+	}
+
+	for i, tc := range tests {
+		output, err := NewRequest(httptest.NewRequest("GET", "/blogs"+tc.query, nil)).Page()
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -1,6 +1,9 @@
 package app
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/gorilla/mux"
 )
 
@@ -16,3 +19,17 @@ func NewRouter() *Router {
 func (r *Router) Add(method, pattern string, handler Handler) {
 	r.Router.NewRoute().Methods(method).Path(pattern).Handler(handler)
 }
+
+// Files serves the files of a directory under a path prefix. Directories are not listed.
+func (r *Router) Files(prefix, dir string) {
+	fileServer := http.StripPrefix(prefix, http.FileServer(http.Dir(dir)))
+
+	r.Router.NewRoute().Methods(http.MethodGet).PathPrefix(prefix).Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/") {
+			http.NotFound(w, req)
+			return
+		}
+
+		fileServer.ServeHTTP(w, req)
+	}))
+}
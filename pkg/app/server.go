@@ -15,20 +15,30 @@ import (
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/configs"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/ratelimit"
 )
 
 type server struct {
+	app         *App
 	contextPool sync.Pool
 	Router      *Router
+	rateLimits  map[string]ratelimit.Policy
 }
 
 func NewServer(app *App) *server {
 	s := &server{
-		Router: NewRouter(),
+		app:        app,
+		Router:     NewRouter(),
+		rateLimits: make(map[string]ratelimit.Policy),
 	}
 
+	s.Router.Use(middlewares.Route)
+
 	s.setUpAuth(app.Config, app.Logger)
 
+	// rate limits come after auth, so that requests can be counted by their claims
+	s.Router.Use(middlewares.RateLimit(app.Logger, ratelimit.NewMemory(), s.rateLimits, app.Get("TRUST_PROXY") == "true"))
+
 	s.Router.Use(s.contextInjector())
 
 	s.contextPool.New = func() interface{} {
@@ -40,7 +50,7 @@ func NewServer(app *App) *server {
 
 func (s *server) Start(logger log.Logger) {
 	server := &http.Server{
-		Handler: s.Router,
+		Handler: middlewares.AccessLog(logger)(s.Router),
 		Addr:    fmt.Sprintf("localhost:%s", os.Getenv("HTTP_PORT")),
 	}
 
@@ -85,9 +95,14 @@ func (s *server) contextInjector() func(inner http.Handler) http.Handler {
 	return func(inner http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			c := s.contextPool.Get().(*Context)
-			c.reset(NewRequest(r), w)
+			c.reset(NewRequest(r), w, s.app)
 			c.Context = r.Context()
 
+			// the request is logged with its ID
+			if info := middlewares.RequestInfoFrom(r.Context()); info != nil {
+				c.App = s.app.withLogger(info.Logger)
+			}
+
 			appContext := context.WithValue(c, appContextKey, c)
 			inner.ServeHTTP(w, r.WithContext(appContext))
 
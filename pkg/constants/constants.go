@@ -7,6 +7,10 @@ const (
 	RetryDuration       = 10
 	DefaultMongoTimeout = 20
 
-	Add    Operation = "$push"
+	// ImagesPath is where the app serves the images of the local image store.
+	ImagesPath = "/images/"
+
+	// a blog is added to a tag at most once, so that adding it again is harmless
+	Add    Operation = "$addToSet"
 	Remove Operation = "$pull"
 )
@@ -17,6 +17,11 @@ type AWSS3 struct {
 func GetNewS3(logger log.Logger, config configs.Config) (AWSS3, error) {
 	awsConfigs := &aws.Config{Region: aws.String(config.GetOrDefault("AWS_REGION", "ap-south-1")), Logger: logger}
 
+	// S3 compatible stores, such as MinIO, are reached at an endpoint of their own
+	if endpoint := config.Get("AWS_ENDPOINT"); endpoint != "" {
+		awsConfigs.WithEndpoint(endpoint).WithS3ForcePathStyle(config.Get("AWS_S3_FORCE_PATH_STYLE") == "true")
+	}
+
 	if config.Get("AWS_LOG_LEVEL") == log.DEBUG.String() {
 		awsConfigs.WithLogLevel(aws.LogDebug)
 	}
@@ -0,0 +1,41 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoIndexes is a set of indexes on a collection that is created the first time it is needed.
+// Creation is retried on the next call if it fails.
+type MongoIndexes struct {
+	collection string
+	models     []mongo.IndexModel
+
+	mu      sync.Mutex
+	created bool
+}
+
+func NewMongoIndexes(collection string, models ...mongo.IndexModel) *MongoIndexes {
+	return &MongoIndexes{collection: collection, models: models}
+}
+
+// Ensure creates the indexes if they have not been created by this process yet.
+// Creating an index that already exists with the same options is a no-op in mongo.
+func (i *MongoIndexes) Ensure(ctx context.Context, db MongoDB) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.created {
+		return nil
+	}
+
+	if _, err := db.Collection(i.collection).Indexes().CreateMany(ctx, i.models); err != nil {
+		return err
+	}
+
+	i.created = true
+
+	return nil
+}
@@ -29,6 +29,11 @@ type MongoDB struct {
 	config *MongoConfig
 }
 
+// DB returns the mongo database of the connection.
+func (m MongoDB) DB() *mongo.Database {
+	return m.Database
+}
+
 func getMongoConnectionString(config *MongoConfig) string {
 	if config.Username == "" || config.Password == "" {
 		return fmt.Sprintf("mongodb://%s:%v/", config.HostName, config.Port)
@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// ContentRejected is returned when the content filter refuses the text of a field.
+type ContentRejected struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+func (e ContentRejected) Error() string {
+	return fmt.Sprintf("%s is not allowed by the content filter (%s)", e.Field, e.Rule)
+}
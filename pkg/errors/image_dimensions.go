@@ -0,0 +1,14 @@
+package errors
+
+import "fmt"
+
+// ImageDimensions is returned for a picture wider, taller or with more pixels than allowed.
+type ImageDimensions struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+func (e ImageDimensions) Error() string {
+	return fmt.Sprintf("image %s of %dx%d pixels exceeds the allowed dimensions", e.Name, e.Width, e.Height)
+}
@@ -0,0 +1,14 @@
+package errors
+
+import "fmt"
+
+// ImageTooLarge is returned for an uploaded file larger than the size allowed per image.
+type ImageTooLarge struct {
+	Name  string `json:"name"`
+	Size  int64  `json:"size"`
+	Limit int64  `json:"limit"`
+}
+
+func (e ImageTooLarge) Error() string {
+	return fmt.Sprintf("image %s of %d bytes exceeds the limit of %d bytes", e.Name, e.Size, e.Limit)
+}
@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// TooManyImages is returned when a blog would have more images than allowed.
+type TooManyImages struct {
+	Count int `json:"count"`
+	Limit int `json:"limit"`
+}
+
+func (e TooManyImages) Error() string {
+	return fmt.Sprintf("%d images exceed the limit of %d images per blog", e.Count, e.Limit)
+}
@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TooManyRequests is returned when a client has used up the rate limit of a route.
+type TooManyRequests struct {
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func (e TooManyRequests) Error() string {
+	return fmt.Sprintf("too many requests, retry in %v seconds", math.Ceil(e.RetryAfter.Seconds()))
+}
@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// Unsupported is returned for an operation that is not available in the current setup.
+type Unsupported struct {
+	Operation string `json:"operation"`
+}
+
+func (e Unsupported) Error() string {
+	return fmt.Sprintf("%s is not supported", e.Operation)
+}
@@ -0,0 +1,17 @@
+package errors
+
+import "fmt"
+
+// UnsupportedImage is returned for an uploaded file that is not a JPEG, PNG, GIF or WebP picture.
+type UnsupportedImage struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+}
+
+func (e UnsupportedImage) Error() string {
+	if e.Format != "" {
+		return fmt.Sprintf("image %s has unsupported format: %s", e.Name, e.Format)
+	}
+
+	return fmt.Sprintf("image %s is not a supported picture", e.Name)
+}
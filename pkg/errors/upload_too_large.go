@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// UploadTooLarge is returned when the uploads of a request together are larger than allowed.
+type UploadTooLarge struct {
+	Limit int64 `json:"limit"`
+}
+
+func (e UploadTooLarge) Error() string {
+	return fmt.Sprintf("upload exceeds the limit of %d bytes per request", e.Limit)
+}
@@ -0,0 +1,120 @@
+package fulltext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+const fragmentSize = 160 // max number of characters in a highlighted fragment
+
+//nolint:gochecknoglobals // compiled once, used for every tokenization
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+//nolint:gochecknoglobals // read-only lookup table
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "to": true, "was": true, "with": true,
+}
+
+// Tokenize splits text into lower case terms, dropping punctuation and stop words.
+func Tokenize(text string) []string {
+	words := wordPattern.FindAllString(strings.ToLower(text), -1)
+	terms := make([]string, 0, len(words))
+
+	for _, word := range words {
+		if !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+
+	return terms
+}
+
+// Highlight returns a fragment of text in which every word that starts with one of the
+// terms is wrapped in <em> tags. The rest of the fragment is HTML escaped.
+// An empty string is returned if none of the terms appear in the text.
+func Highlight(text string, terms []string) string {
+	matches := make([][]int, 0)
+
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		if matchesAny(strings.ToLower(text[loc[0]:loc[1]]), terms) {
+			matches = append(matches, loc)
+		}
+	}
+
+	if len(matches) == 0 {
+		return ""
+	}
+
+	start, end := fragmentBounds(text, matches[0][0])
+
+	var sb strings.Builder
+
+	if start > 0 {
+		sb.WriteString("...")
+	}
+
+	pos := start
+
+	for _, loc := range matches {
+		if loc[0] < start || loc[1] > end {
+			continue
+		}
+
+		sb.WriteString(html.EscapeString(text[pos:loc[0]]))
+		sb.WriteString("<em>")
+		sb.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		sb.WriteString("</em>")
+
+		pos = loc[1]
+	}
+
+	sb.WriteString(html.EscapeString(text[pos:end]))
+
+	if end < len(text) {
+		sb.WriteString("...")
+	}
+
+	return sb.String()
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fragmentBounds returns a window of text of at most fragmentSize bytes around the
+// first match, aligned to word boundaries.
+func fragmentBounds(text string, first int) (start, end int) {
+	if len(text) <= fragmentSize {
+		return 0, len(text)
+	}
+
+	start = first - fragmentSize/4
+	if start < 0 {
+		start = 0
+	}
+
+	end = start + fragmentSize
+	if end > len(text) {
+		end = len(text)
+		start = end - fragmentSize
+	}
+
+	if i := strings.IndexByte(text[start:first], ' '); start > 0 && i >= 0 {
+		start += i + 1
+	}
+
+	if i := strings.LastIndexByte(text[first:end], ' '); end < len(text) && i > 0 {
+		end = first + i
+	}
+
+	return start, end
+}
@@ -0,0 +1,153 @@
+// Package imaging turns an uploaded picture into the renditions that are served to clients.
+package imaging
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+
+	// decoders of the accepted formats are registered with the image package
+	_ "image/gif"
+
+	_ "golang.org/x/image/webp"
+
+	"golang.org/x/image/draw"
+)
+
+const jpegQuality = 85
+
+// Renditions are generated for every size, bounded by the longest side in pixels.
+// Pictures are never enlarged, so a small picture has renditions of its own size.
+const (
+	Thumbnail = "thumbnail"
+	Medium    = "medium"
+	Full      = "full"
+)
+
+// Size is the bound of the longest side of a rendition.
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Sizes lists the renditions generated for every picture, smallest first.
+//
+//nolint:gochecknoglobals // read-only list of sizes
+var Sizes = []Size{{Name: Thumbnail, MaxSide: 320}, {Name: Medium, MaxSide: 1024}, {Name: Full, MaxSide: 2048}}
+
+// Rendition is an encoded picture of one size.
+type Rendition struct {
+	Size        string
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// headerSize bounds the bytes read ahead of decoding, for the EXIF metadata, which is at the start of a JPEG file.
+const headerSize = 64 << 10
+
+// Process decodes a JPEG, PNG, GIF or WebP picture, turns it upright as per its EXIF orientation
+// and encodes a rendition for every size. The renditions are encoded afresh, so they carry no
+// EXIF (or GPS) metadata. Opaque pictures are encoded as JPEG, the others as PNG.
+// The picture is streamed from the reader, which is read twice: for its header, and then as a whole.
+func Process(r io.ReadSeeker) ([]Rendition, error) {
+	header, err := ioutil.ReadAll(io.LimitReader(r, headerSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+
+	img = orient(img, orientation(header))
+
+	renditions := make([]Rendition, 0, len(Sizes))
+
+	for _, size := range Sizes {
+		resized := resize(img, size.MaxSide)
+
+		rendition, err := encode(resized)
+		if err != nil {
+			return nil, err
+		}
+
+		rendition.Size = size.Name
+		renditions = append(renditions, rendition)
+	}
+
+	return renditions, nil
+}
+
+// resize scales the picture down so that its longest side fits within maxSide, keeping its aspect ratio.
+func resize(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		width, height = maxSide, max(1, height*maxSide/width)
+	} else {
+		width, height = max(1, width*maxSide/height), maxSide
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func encode(img image.Image) (Rendition, error) {
+	var buf bytes.Buffer
+
+	bounds := img.Bounds()
+	rendition := Rendition{Width: bounds.Dx(), Height: bounds.Dy()}
+
+	if isOpaque(img) {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return Rendition{}, err
+		}
+
+		rendition.ContentType, rendition.Extension = "image/jpeg", ".jpg"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return Rendition{}, err
+		}
+
+		rendition.ContentType, rendition.Extension = "image/png", ".png"
+	}
+
+	rendition.Data = buf.Bytes()
+
+	return rendition, nil
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,181 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// picture returns an opaque picture, or a translucent one, of the given size.
+func picture(width, height int, opaque bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	alpha := uint8(255)
+	if !opaque {
+		alpha = 128
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: alpha})
+		}
+	}
+
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodeGIF(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+
+	if err := gif.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// withOrientation adds an EXIF segment with the orientation tag, and a GPS marker, right after the start of a JPEG file.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, byte(orientation >> 8), byte(orientation), 0, 0, 0, 0, 0, 0}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	segment = append(segment, "GPS"...)
+
+	app1 := []byte{0xff, markerAPP1, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}
+
+	res := append([]byte{}, data[:2]...)
+	res = append(res, app1...)
+	res = append(res, segment...)
+
+	return append(res, data[2:]...)
+}
+
+type size struct {
+	name          string
+	width, height int
+}
+
+//nolint:lll // test cases need to be readable
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		description string
+		data        []byte
+		contentType string
+		sizes       []size
+	}{
+		{description: "landscape picture", data: encodeJPEG(t, picture(2400, 1200, true)), contentType: "image/jpeg", sizes: []size{{Thumbnail, 320, 160}, {Medium, 1024, 512}, {Full, 2048, 1024}}},
+		{description: "portrait picture", data: encodeGIF(t, picture(200, 800, true)), contentType: "image/jpeg", sizes: []size{{Thumbnail, 80, 320}, {Medium, 200, 800}, {Full, 200, 800}}},
+		{description: "small picture is never enlarged", data: encodePNG(t, picture(100, 50, true)), contentType: "image/jpeg", sizes: []size{{Thumbnail, 100, 50}, {Medium, 100, 50}, {Full, 100, 50}}},
+		{description: "translucent picture", data: encodePNG(t, picture(400, 400, false)), contentType: "image/png", sizes: []size{{Thumbnail, 320, 320}, {Medium, 400, 400}, {Full, 400, 400}}},
+		{description: "sideways picture is turned upright", data: withOrientation(encodeJPEG(t, picture(40, 20, true)), 6), contentType: "image/jpeg", sizes: []size{{Thumbnail, 20, 40}, {Medium, 20, 40}, {Full, 20, 40}}},
+	}
+
+	for i, tc := range tests {
+		renditions, err := Process(bytes.NewReader(tc.data))
+		if !assert.NoError(t, err, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			continue
+		}
+
+		sizes := make([]size, 0, len(renditions))
+
+		for _, rendition := range renditions {
+			sizes = append(sizes, size{rendition.Size, rendition.Width, rendition.Height})
+
+			assert.Equal(t, tc.contentType, rendition.ContentType, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+			// renditions are encoded afresh, and carry what they say they do, without the metadata of the upload
+			config, format, err := image.DecodeConfig(bytes.NewReader(rendition.Data))
+			if assert.NoError(t, err, "TEST [%v], failed.\n%s", i+1, tc.description) {
+				assert.Equal(t, "image/"+format, rendition.ContentType, "TEST [%v], failed.\n%s", i+1, tc.description)
+				assert.Equal(t, [2]int{rendition.Width, rendition.Height}, [2]int{config.Width, config.Height}, "TEST [%v], failed.\n%s", i+1, tc.description)
+			}
+
+			assert.False(t, bytes.Contains(rendition.Data, []byte("Exif")), "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+
+		assert.Equal(t, tc.sizes, sizes, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestProcess_Invalid(t *testing.T) {
+	_, err := Process(bytes.NewReader([]byte("not a picture")))
+
+	assert.Error(t, err, "TEST, failed.\nfile that is not a picture")
+}
+
+//nolint:lll // test cases need to be readable
+func TestOrientation(t *testing.T) {
+	data := encodeJPEG(t, picture(4, 2, true))
+	littleEndian := withOrientation(data, 0)
+	copy(littleEndian[12:], []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, 3, 0})
+
+	tests := []struct {
+		description string
+		data        []byte
+		output      int
+	}{
+		{description: "picture without EXIF", data: data, output: 1},
+		{description: "turned picture", data: withOrientation(data, 6), output: 6},
+		{description: "EXIF of little endian order", data: littleEndian, output: 3},
+		{description: "orientation out of range", data: withOrientation(data, 9), output: 1},
+		{description: "picture that is not a JPEG", data: encodePNG(t, picture(4, 2, true)), output: 1},
+		{description: "truncated header", data: withOrientation(data, 6)[:12], output: 1},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.output, orientation(tc.data), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	red, blue := color.NRGBA{R: 255, A: 255}, color.NRGBA{B: 255, A: 255}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	tests := []struct {
+		description string
+		orientation int
+		red, blue   image.Point
+	}{
+		{description: "upright", orientation: 1, red: image.Pt(0, 0), blue: image.Pt(1, 0)},
+		{description: "mirrored horizontally", orientation: 2, red: image.Pt(1, 0), blue: image.Pt(0, 0)},
+		{description: "rotated by 180°", orientation: 3, red: image.Pt(1, 0), blue: image.Pt(0, 0)},
+		{description: "turned clockwise", orientation: 6, red: image.Pt(0, 0), blue: image.Pt(0, 1)},
+		{description: "turned counter clockwise", orientation: 8, red: image.Pt(0, 1), blue: image.Pt(0, 0)},
+	}
+
+	for i, tc := range tests {
+		res := orient(img, tc.orientation)
+
+		assert.Equal(t, red, color.NRGBAModel.Convert(res.At(tc.red.X, tc.red.Y)), "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, blue, color.NRGBAModel.Convert(res.At(tc.blue.X, tc.blue.Y)), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
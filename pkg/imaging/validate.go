@@ -0,0 +1,109 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/configs"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+// Limits bound the pictures accepted for upload.
+type Limits struct {
+	MaxFileSize    int64 // bytes per picture
+	MaxRequestSize int64 // bytes of all the pictures of a request
+	MaxWidth       int
+	MaxHeight      int
+	MaxPixels      int // guards against decompression bombs, which are small files of huge pictures
+	MaxImages      int // pictures per blog
+}
+
+// default limits, which can be overridden by configs
+const (
+	defaultMaxFileSize    = 10 << 20
+	defaultMaxRequestSize = 32 << 20
+	defaultMaxWidth       = 8192
+	defaultMaxHeight      = 8192
+	defaultMaxPixels      = 40_000_000
+	defaultMaxImages      = 10
+)
+
+// sniffLen is the number of bytes the content type is sniffed from.
+const sniffLen = 512
+
+// formats lists the accepted formats, by the names of their decoders, and contentTypes by their sniffed types.
+//
+//nolint:gochecknoglobals // read-only sets of formats
+var (
+	formats      = map[string]bool{"jpeg": true, "png": true, "gif": true, "webp": true}
+	contentTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true, "image/webp": true}
+)
+
+// NewLimits reads the upload limits from the configs, falling back to the defaults.
+func NewLimits(c configs.Config) Limits {
+	return Limits{
+		MaxFileSize:    int64(configInt(c, "IMAGE_MAX_FILE_SIZE", defaultMaxFileSize)),
+		MaxRequestSize: int64(configInt(c, "IMAGE_MAX_REQUEST_SIZE", defaultMaxRequestSize)),
+		MaxWidth:       configInt(c, "IMAGE_MAX_WIDTH", defaultMaxWidth),
+		MaxHeight:      configInt(c, "IMAGE_MAX_HEIGHT", defaultMaxHeight),
+		MaxPixels:      configInt(c, "IMAGE_MAX_PIXELS", defaultMaxPixels),
+		MaxImages:      configInt(c, "IMAGE_MAX_COUNT", defaultMaxImages),
+	}
+}
+
+func configInt(c configs.Config, key string, defaultVal int) int {
+	if c == nil {
+		return defaultVal
+	}
+
+	val, err := strconv.Atoi(c.GetOrDefault(key, ""))
+	if err != nil || val <= 0 {
+		return defaultVal
+	}
+
+	return val
+}
+
+// Validate checks that a file of the given size is a picture of an accepted format within the limits.
+// The content type is sniffed from the first bytes, and only the header of the picture is decoded,
+// so a picture is rejected before its pixels are allocated.
+func (l Limits) Validate(name string, size int64, r io.Reader) error {
+	if size > l.MaxFileSize {
+		return errors.ImageTooLarge{Name: name, Size: size, Limit: l.MaxFileSize}
+	}
+
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return errors.UnsupportedImage{Name: name}
+	}
+
+	head = head[:n]
+
+	if contentType := http.DetectContentType(head); !contentTypes[contentType] {
+		return errors.UnsupportedImage{Name: name, Format: contentType}
+	}
+
+	config, format, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), r))
+	if err != nil {
+		return errors.UnsupportedImage{Name: name}
+	}
+
+	if !formats[format] {
+		return errors.UnsupportedImage{Name: name, Format: format}
+	}
+
+	if config.Width <= 0 || config.Height <= 0 {
+		return errors.UnsupportedImage{Name: name, Format: format}
+	}
+
+	if config.Width > l.MaxWidth || config.Height > l.MaxHeight || config.Width*config.Height > l.MaxPixels {
+		return errors.ImageDimensions{Name: name, Width: config.Width, Height: config.Height}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+//nolint:lll // test cases need to be readable
+func TestLimits_Validate(t *testing.T) {
+	limits := Limits{MaxFileSize: 1 << 20, MaxWidth: 1000, MaxHeight: 1000, MaxPixels: 500_000}
+
+	png := encodePNG(t, picture(100, 50, true))
+	bmp := append([]byte("BM"), make([]byte, 64)...)
+
+	tests := []struct {
+		description string
+		data        []byte
+		size        int64
+		err         error
+	}{
+		{description: "jpeg picture", data: encodeJPEG(t, picture(100, 50, true))},
+		{description: "png picture", data: png},
+		{description: "gif picture", data: encodeGIF(t, picture(100, 50, true))},
+		{description: "file over the size limit", data: png, size: 2 << 20, err: errors.ImageTooLarge{Name: "pic", Size: 2 << 20, Limit: 1 << 20}},
+		{description: "file that is not a picture", data: []byte("just some text"), err: errors.UnsupportedImage{Name: "pic", Format: "text/plain; charset=utf-8"}},
+		{description: "picture of a format that is not accepted", data: bmp, err: errors.UnsupportedImage{Name: "pic", Format: "image/bmp"}},
+		{description: "picture of a broken header", data: png[:16], err: errors.UnsupportedImage{Name: "pic"}},
+		{description: "picture over the width limit", data: encodePNG(t, picture(1001, 1, true)), err: errors.ImageDimensions{Name: "pic", Width: 1001, Height: 1}},
+		{description: "picture over the pixel limit", data: encodePNG(t, picture(1000, 501, true)), err: errors.ImageDimensions{Name: "pic", Width: 1000, Height: 501}},
+	}
+
+	for i, tc := range tests {
+		size := tc.size
+		if size == 0 {
+			size = int64(len(tc.data))
+		}
+
+		err := limits.Validate("pic", size, bytes.NewReader(tc.data))
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestNewLimits(t *testing.T) {
+	defaults := Limits{MaxFileSize: 10 << 20, MaxRequestSize: 32 << 20, MaxWidth: 8192, MaxHeight: 8192, MaxPixels: 40_000_000, MaxImages: 10}
+
+	overridden := defaults
+	overridden.MaxFileSize, overridden.MaxImages = 1024, 3
+
+	tests := []struct {
+		description string
+		config      testConfig
+		output      Limits
+	}{
+		{description: "no configs", output: defaults},
+		{description: "configs that override the defaults", config: testConfig{"IMAGE_MAX_FILE_SIZE": "1024", "IMAGE_MAX_COUNT": "3"}, output: overridden},
+		{description: "configs that are not positive numbers", config: testConfig{"IMAGE_MAX_FILE_SIZE": "ten", "IMAGE_MAX_COUNT": "-3", "IMAGE_MAX_WIDTH": "0"}, output: defaults},
+	}
+
+	for i, tc := range tests {
+		var limits Limits
+
+		if tc.config == nil {
+			limits = NewLimits(nil)
+		} else {
+			limits = NewLimits(tc.config)
+		}
+
+		assert.Equal(t, tc.output, limits, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+// testConfig is a config of fixed values.
+type testConfig map[string]string
+
+func (c testConfig) Get(key string) string {
+	return c[key]
+}
+
+func (c testConfig) GetOrDefault(key, defaultVal string) string {
+	if val, ok := c[key]; ok {
+		return val
+	}
+
+	return defaultVal
+}
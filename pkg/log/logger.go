@@ -33,12 +33,19 @@ type Logger interface {
 	Fatalf(format string, args ...interface{})
 
 	Log(args ...interface{})
+
+	// With returns a Logger that adds the fields to every log line, along with the fields of this Logger.
+	With(fields Fields) Logger
 }
 
+// Fields are the key-value pairs that a log line carries along with its message.
+type Fields map[string]interface{}
+
 type logger struct {
 	level      level
 	out        io.Writer
 	isTerminal bool
+	fields     Fields
 }
 
 // log does the actual logging. This function creates the log line and outputs it in color format
@@ -49,8 +56,9 @@ func (l *logger) log(level level, format string, args ...interface{}) {
 	}
 
 	ll := logLine{
-		Level: level,
-		Time:  time.Now(),
+		Level:  level,
+		Time:   time.Now(),
+		Fields: l.fields,
 	}
 
 	switch {
@@ -69,6 +77,23 @@ func (l *logger) log(level level, format string, args ...interface{}) {
 	}
 }
 
+func (l *logger) With(fields Fields) Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	child := *l
+	child.fields = merged
+
+	return &child
+}
+
 func (l *logger) Log(args ...interface{}) {
 	l.log(INFO, "", args...)
 }
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_With(t *testing.T) {
+	var out bytes.Buffer
+
+	parent := NewMockLogger(&out).With(Fields{"request_id": "a", "route": "/blogs"})
+	child := parent.With(Fields{"route": "/blogs/{blogid}", "status": 200})
+
+	tests := []struct {
+		description string
+		logger      Logger
+		fields      Fields
+	}{
+		{description: "fields of the logger", logger: parent, fields: Fields{"request_id": "a", "route": "/blogs"}},
+		{description: "fields added to those of the parent, overriding them", logger: child, fields: Fields{"request_id": "a", "route": "/blogs/{blogid}", "status": float64(200)}},
+		{description: "logger without fields", logger: NewMockLogger(&out)},
+	}
+
+	for i, tc := range tests {
+		out.Reset()
+
+		tc.logger.Infof("served %s", "request")
+
+		var line struct {
+			Message string
+			Fields  Fields
+		}
+
+		if assert.NoError(t, json.Unmarshal(out.Bytes(), &line), "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.Equal(t, "served request", line.Message, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+			assert.Equal(t, tc.fields, line.Fields, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+func TestLogLine_Fields(t *testing.T) {
+	line := logLine{Fields: Fields{"status": 200, "method": "GET", "route": "/blogs"}}
+
+	assert.Equal(t, " method=GET route=/blogs status=200", line.fields(), "TEST, failed.\nfields sorted by key")
+	assert.Equal(t, "", logLine{}.fields(), "TEST, failed.\nno fields")
+}
@@ -3,6 +3,8 @@ package log
 import (
 	"fmt"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -10,6 +12,7 @@ type logLine struct {
 	Level   level
 	Time    time.Time
 	Message interface{}
+	Fields  Fields `json:",omitempty"`
 }
 
 func (l logLine) TerminalOutput() string {
@@ -19,6 +22,24 @@ func (l logLine) TerminalOutput() string {
 
 	mem := float32(m.Alloc) / float32(1024*1024)
 
-	return fmt.Sprintf("\u001B[%dm%s\u001B[0m [%s] %v", l.Level.color(), l.Level.String()[0:4], l.Time.Format("15:04:05"), l.Message) +
+	return fmt.Sprintf("\u001B[%dm%s\u001B[0m [%s] %v%s", l.Level.color(), l.Level.String()[0:4], l.Time.Format("15:04:05"), l.Message, l.fields()) +
 		fmt.Sprintf("\n\t\u001B[%dm (Memory: %v MB GoRoutines: %v) \u001B[0m\n\n", 37, mem, runtime.NumGoroutine())
 }
+
+// fields writes the fields of a log line as key=value pairs, sorted by key.
+func (l logLine) fields() string {
+	keys := make([]string, 0, len(l.Fields))
+	for key := range l.Fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, l.Fields[key])
+	}
+
+	return b.String()
+}
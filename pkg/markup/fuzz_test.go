@@ -0,0 +1,104 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// seeds are inputs that exercise the parsers and the policy, the fuzzer mutates them to search for unsafe output.
+//
+//nolint:gochecknoglobals // read-only seed corpus
+var seeds = []string{
+	"# one\n### three ###\n\na  \nb\\\nc\n\n***",
+	"*em* _em_ **strong** __strong__ ***both*** ~~del~~ **a _b_ c** *a **b ~c",
+	"`a` ``b ` c`` ```d\n~~~\n*a*\n~~~\n```\na",
+	"[a *b*](https://x.com \"t\") [a](https://x.com/(b)) <https://x.com> <mailto:a@x.com>",
+	"> a\n> > b\n- a\n- b\n  - c\n3. a\n4. b",
+	"<script>alert(1)</script><img src=x onerror=\"alert(1)\">",
+	"[a](javascript:alert(1)) [a](JaVaScRiPt:alert(1)) [a](&#106;avascript:alert(1)) [a](//evil.com)",
+	"[a](https://x.com \"t\\\" onmouseover=\\\"alert(1)\") [a](https://x.com/\"onclick=\"alert(1))",
+	"<p>a<b onclick=x>b</b><a href=\"javascript:x\">c</a><a href=https://x.com title='t'>d</a></p>",
+	"<svg><style><img src=x onerror=alert(1)></style></svg><math><mi><a href=x>",
+	"<ol start=\"-1\"><li><ol start=\"2\" type=\"a\"><noscript><p title=\"</noscript><img src=x onerror=alert(1)>\">",
+	"<textarea><script>x</script></textarea><template><a href=https://x.com></template><!-- <a> -->",
+}
+
+// checkSafe parses the output of the renderer as a browser does, and fails the test
+// if any element, attribute or link is left that the policy does not allow.
+func checkSafe(t *testing.T, input, output string) {
+	t.Helper()
+
+	body := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+
+	nodes, err := xhtml.ParseFragment(strings.NewReader(output), body)
+	if err != nil {
+		t.Fatalf("cannot parse the output of %q: %v", input, err)
+	}
+
+	var walk func(n *xhtml.Node)
+
+	walk = func(n *xhtml.Node) {
+		if n.Type == xhtml.ElementNode {
+			allowed, ok := policy[n.Data]
+			if !ok || n.Namespace != "" {
+				t.Fatalf("element %q is left in the output %q of %q", n.Data, output, input)
+			}
+
+			for _, attr := range n.Attr {
+				switch {
+				case attr.Namespace != "":
+					t.Fatalf("attribute %s:%s is left in the output %q of %q", attr.Namespace, attr.Key, output, input)
+				case n.Data == "a" && attr.Key == "rel" && attr.Val == linkRel:
+				case !contains(allowed, attr.Key):
+					t.Fatalf("attribute %q of %q is left in the output %q of %q", attr.Key, n.Data, output, input)
+				case attr.Key == "href" && !SafeURL(attr.Val):
+					t.Fatalf("link %q is left in the output %q of %q", attr.Val, output, input)
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	if Sanitize(output) != output {
+		t.Fatalf("output %q of %q is changed by sanitizing it again", output, input)
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if !utf8.ValidString(input) {
+			t.Skip()
+		}
+
+		checkSafe(t, input, Sanitize(input))
+	})
+}
+
+func FuzzMarkdown(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if !utf8.ValidString(input) {
+			t.Skip()
+		}
+
+		checkSafe(t, input, Markdown(input))
+	})
+}
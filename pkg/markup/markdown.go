@@ -0,0 +1,482 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxDepth bounds the nesting of block quotes, lists and inline markup, deeper markup is rendered as text.
+const maxDepth = 16
+
+//nolint:gochecknoglobals // compiled patterns of the markdown blocks
+var (
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*#*[ \t]*$`)
+	rulePattern    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fencePattern   = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	quotePattern   = regexp.MustCompile(`^ {0,3}> ?`)
+	itemPattern    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])(?:[ \t]+|$)`)
+)
+
+// emphasisTags are the tags that open and close emphasis, by the length of its delimiters.
+//
+//nolint:gochecknoglobals // read-only table of tags
+var emphasisTags = map[int][2]string{
+	1: {"<em>", "</em>"},
+	2: {"<strong>", "</strong>"},
+	3: {"<em><strong>", "</strong></em>"},
+}
+
+// renderBlocks renders the blocks of markdown: headings, rules, fenced code, block quotes, lists and paragraphs.
+func renderBlocks(lines []string, depth int) string {
+	var b strings.Builder
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fencePattern.MatchString(line):
+			i = renderFence(&b, lines, i)
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + renderInline(m[2], depth) + "</h" + level + ">\n")
+			i++
+		case rulePattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case depth < maxDepth && quotePattern.MatchString(line):
+			quoted := make([]string, 0)
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.ReplaceAllString(lines[i], ""))
+			}
+
+			b.WriteString("<blockquote>\n" + renderBlocks(quoted, depth+1) + "</blockquote>\n")
+		case depth < maxDepth && itemPattern.MatchString(line):
+			i = renderList(&b, lines, i, depth)
+		default:
+			i = renderParagraph(&b, lines, i, depth)
+		}
+	}
+
+	return b.String()
+}
+
+// renderFence renders a fenced code block starting at line i, and returns the line after it.
+// A fence that is never closed runs to the end of the text.
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	fence := strings.TrimLeft(fencePattern.FindString(lines[i]), " ")
+	code := make([]string, 0)
+
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+
+			break
+		}
+
+		code = append(code, lines[i])
+	}
+
+	b.WriteString("<pre><code>")
+
+	if len(code) != 0 {
+		b.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n")
+	}
+
+	b.WriteString("</code></pre>\n")
+
+	return i
+}
+
+// renderList renders the items of a list starting at line i, and returns the line after it.
+// The lines of an item are the ones indented past its marker, and the ones that continue its paragraph.
+func renderList(b *strings.Builder, lines []string, i, depth int) int {
+	m := itemPattern.FindStringSubmatch(lines[i])
+	ordered := !strings.ContainsAny(m[2], "-*+")
+	delimiter := m[2][len(m[2])-1:]
+
+	if ordered {
+		start, _ := strconv.Atoi(m[2][:len(m[2])-1])
+		if start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	for i < len(lines) {
+		m = itemPattern.FindStringSubmatch(lines[i])
+		if m == nil || m[2][len(m[2])-1:] != delimiter {
+			break
+		}
+
+		indent := len(m[0])
+		item := []string{lines[i][indent:]}
+		blank := false
+
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			trimmed := strings.TrimSpace(line)
+
+			if trimmed == "" {
+				blank = true
+				item = append(item, "")
+
+				continue
+			}
+
+			if len(line)-len(strings.TrimLeft(line, " ")) >= indent {
+				blank = false
+				item = append(item, line[indent:])
+
+				continue
+			}
+
+			if blank || startsBlock(line) {
+				break
+			}
+
+			item = append(item, trimmed)
+		}
+
+		content := renderBlocks(item, depth+1)
+
+		// a list item that is a single paragraph is tight, and goes without the paragraph
+		if strings.HasPrefix(content, "<p>") && strings.Count(content, "<p>") == 1 && strings.HasSuffix(content, "</p>\n") {
+			content = strings.TrimSuffix(strings.TrimPrefix(content, "<p>"), "</p>\n")
+		}
+
+		b.WriteString("<li>" + content + "</li>\n")
+
+		if blank {
+			break
+		}
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+
+	return i
+}
+
+// renderParagraph renders the paragraph starting at line i, and returns the line after it.
+// Lines that end in two spaces or a backslash break the line.
+func renderParagraph(b *strings.Builder, lines []string, i, depth int) int {
+	text := make([]string, 0)
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (len(text) != 0 && startsBlock(line)) {
+			break
+		}
+
+		text = append(text, line)
+	}
+
+	for j, line := range text {
+		last := j == len(text)-1
+		hardBreak := !last && (strings.HasSuffix(line, "  ") || strings.HasSuffix(line, `\`))
+		line = strings.TrimSpace(line)
+
+		if hardBreak {
+			line = strings.TrimSuffix(line, `\`)
+		}
+
+		text[j] = renderInline(line, depth)
+
+		if hardBreak {
+			text[j] += "<br>"
+		}
+	}
+
+	b.WriteString("<p>" + strings.Join(text, "\n") + "</p>\n")
+
+	return i
+}
+
+// startsBlock tells if a line interrupts a paragraph.
+func startsBlock(line string) bool {
+	return fencePattern.MatchString(line) || headingPattern.MatchString(line) ||
+		rulePattern.MatchString(line) || quotePattern.MatchString(line) || itemPattern.MatchString(line)
+}
+
+// renderInline renders the inline markup of text: escapes, code spans, links, autolinks, strong and emphasis.
+// Everything else, raw HTML included, is escaped.
+// The delimiters of the text are indexed up front, so that no delimiter is looked for by scanning the rest of the text,
+// and rendering stays linear in the length of the text at each depth.
+func renderInline(text string, depth int) string {
+	if depth >= maxDepth {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+
+	d := indexDelimiters(text)
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch c {
+		case '\\':
+			if i+1 < len(text) && (unicode.IsPunct(rune(text[i+1])) || unicode.IsSymbol(rune(text[i+1]))) {
+				b.WriteString(html.EscapeString(text[i+1 : i+2]))
+				i += 2
+
+				continue
+			}
+		case '`':
+			n := codeSpan(&b, text, i, d)
+			if n == 0 {
+				// a run of backticks that is not closed is text as a whole
+				n = d.runs[i]
+				b.WriteString(text[i : i+n])
+			}
+
+			i += n
+
+			continue
+		case '[':
+			if n := link(&b, text, i, d, depth); n != 0 {
+				i += n
+
+				continue
+			}
+		case '<':
+			if n := autolink(&b, text[i:]); n != 0 {
+				i += n
+
+				continue
+			}
+		case '*', '_', '~':
+			if n := emphasis(&b, text, i, d, depth); n != 0 {
+				i += n
+
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+
+	return b.String()
+}
+
+// closer is a run of delimiters that can close a code span or emphasis, by its character and length.
+type closer struct {
+	c byte
+	n int
+}
+
+// delimiters indexes the delimiters of a text.
+type delimiters struct {
+	runs    []int            // number of times the byte at each index repeats from there
+	pairs   map[int]int      // index of the bracket or parenthesis that closes the one at each index
+	closers map[closer][]int // starts of the runs that can close a code span or emphasis, in order
+}
+
+// indexDelimiters indexes the delimiters of a text in a single pass, and one more to count the runs.
+// Brackets and parentheses are paired by their nesting, escaped ones left out.
+func indexDelimiters(text string) delimiters {
+	d := delimiters{runs: make([]int, len(text)), pairs: make(map[int]int), closers: make(map[closer][]int)}
+
+	for i := len(text) - 1; i >= 0; i-- {
+		d.runs[i] = 1
+		if i+1 < len(text) && text[i+1] == text[i] {
+			d.runs[i] = d.runs[i+1] + 1
+		}
+	}
+
+	brackets, parens := make([]int, 0), make([]int, 0)
+
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			brackets = append(brackets, i)
+		case ']':
+			if len(brackets) != 0 {
+				d.pairs[brackets[len(brackets)-1]] = i
+				brackets = brackets[:len(brackets)-1]
+			}
+		case '(':
+			parens = append(parens, i)
+		case ')':
+			if len(parens) != 0 {
+				d.pairs[parens[len(parens)-1]] = i
+				parens = parens[:len(parens)-1]
+			}
+		}
+	}
+
+	for i := 0; i < len(text); i += d.runs[i] {
+		if c := text[i]; c == '`' || (canClose(text, i, d.runs[i]) && (c == '*' || c == '_' || c == '~')) {
+			key := closer{c: c, n: d.runs[i]}
+			d.closers[key] = append(d.closers[key], i)
+		}
+	}
+
+	return d
+}
+
+// canClose tells if the run of n delimiters at i can close emphasis: it follows text, and underscores do not go on into a word.
+func canClose(text string, i, n int) bool {
+	if i == 0 || text[i-1] == ' ' || text[i-1] == '\t' {
+		return false
+	}
+
+	return text[i] != '_' || i+n >= len(text) || !isWordByte(text[i+n])
+}
+
+// next returns the start of the first run of n delimiters c from index from on, or -1 when there is none.
+func (d delimiters) next(c byte, n, from int) int {
+	starts := d.closers[closer{c: c, n: n}]
+
+	k := sort.SearchInts(starts, from)
+	if k == len(starts) {
+		return -1
+	}
+
+	return starts[k]
+}
+
+// pair returns the index of the bracket or parenthesis that closes the one at i, or -1 when there is none.
+func (d delimiters) pair(i int) int {
+	if j, ok := d.pairs[i]; ok {
+		return j
+	}
+
+	return -1
+}
+
+// codeSpan renders the code span that starts at i, and returns its length, or 0 when it is not closed.
+// It is closed by the next run of backticks as long as the one it opens with.
+func codeSpan(b *strings.Builder, text string, i int, d delimiters) int {
+	ticks := d.runs[i]
+
+	j := d.next('`', ticks, i+ticks)
+	if j < 0 {
+		return 0
+	}
+
+	code := text[i+ticks : j]
+	if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+		code = code[1 : len(code)-1]
+	}
+
+	b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+
+	return j + ticks - i
+}
+
+// link renders the link that starts at i, in the form [label](url "title"), and returns its length, or 0 when it is not a link.
+// A link to a URL that is not safe is rendered as its label alone.
+func link(b *strings.Builder, text string, i int, d delimiters, depth int) int {
+	bracket := d.pair(i)
+	if bracket < 0 || bracket+1 >= len(text) || text[bracket+1] != '(' {
+		return 0
+	}
+
+	end := d.pair(bracket + 1)
+	if end < 0 {
+		return 0
+	}
+
+	target := strings.TrimSpace(text[bracket+2 : end])
+	title := ""
+
+	if k := strings.IndexAny(target, " \t"); k >= 0 {
+		quoted := strings.TrimSpace(target[k:])
+		if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+			return 0
+		}
+
+		target, title = target[:k], quoted[1:len(quoted)-1]
+	}
+
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	label := renderInline(text[i+1:bracket], depth+1)
+
+	if !SafeURL(target) {
+		b.WriteString(label)
+
+		return end + 1 - i
+	}
+
+	b.WriteString(`<a href="` + html.EscapeString(target) + `"`)
+
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+
+	b.WriteString(">" + label + "</a>")
+
+	return end + 1 - i
+}
+
+// autolink renders the autolink text starts with, in the form <url>, and returns its length, or 0 when it is not one.
+// The scan stops at the next '<', so that no text is scanned twice.
+func autolink(b *strings.Builder, text string) int {
+	end := strings.IndexAny(text[1:], "> \t\n<") + 1
+	if end <= 0 || text[end] != '>' {
+		return 0
+	}
+
+	target := text[1:end]
+	if !SafeURL(target) {
+		return 0
+	}
+
+	label := strings.TrimPrefix(target, "mailto:")
+	b.WriteString(`<a href="` + html.EscapeString(target) + `">` + html.EscapeString(label) + "</a>")
+
+	return end + 1
+}
+
+// emphasis renders the emphasis, strong emphasis or strikethrough that starts at i, and returns its length, or 0 when there is none.
+// A run of delimiters is closed by a run as long, and underscores within words are not emphasis,
+// so that names like snake_case stay as they are.
+func emphasis(b *strings.Builder, text string, i int, d delimiters, depth int) int {
+	c := text[i]
+	run := d.runs[i]
+
+	if run > 3 || (c == '~' && run != 2) || (c == '_' && i > 0 && isWordByte(text[i-1])) {
+		return 0
+	}
+
+	start := i + run
+	if start >= len(text) || text[start] == ' ' || text[start] == '\t' {
+		return 0
+	}
+
+	j := d.next(c, run, start+1)
+	if j < 0 {
+		return 0
+	}
+
+	tags := emphasisTags[run]
+	if c == '~' {
+		tags = [2]string{"<del>", "</del>"}
+	}
+
+	b.WriteString(tags[0] + renderInline(text[start:j], depth+1) + tags[1])
+
+	return j + run - i
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
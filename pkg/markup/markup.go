@@ -0,0 +1,50 @@
+// Package markup renders the content of blogs to HTML that is safe to serve.
+// Content is rendered from plain text or markdown, and the result is always passed through the sanitizer,
+// which keeps the tags and attributes of the policy alone.
+package markup
+
+import (
+	"html"
+	"strings"
+)
+
+// Version is the version of the rendering and of the sanitizer policy.
+// It is bumped whenever either changes, so that the content rendered by an older version is rendered again.
+const Version = 1
+
+// Plain renders plain text, as paragraphs separated by blank lines, that keep their line breaks.
+func Plain(text string) string {
+	var b strings.Builder
+
+	for _, paragraph := range strings.Split(normalize(text), "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+
+	return Sanitize(b.String())
+}
+
+// Markdown renders markdown text. Raw HTML in the text is escaped rather than passed through.
+func Markdown(text string) string {
+	return Sanitize(renderBlocks(strings.Split(normalize(text), "\n"), 0))
+}
+
+// normalize unifies line endings and drops the characters that have no business in text.
+func normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	return strings.Map(func(r rune) rune {
+		if r == 0 || (r < ' ' && r != '\n' && r != '\t') {
+			return -1
+		}
+
+		return r
+	}, text)
+}
@@ -0,0 +1,153 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:lll // test cases need to be readable
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		description string
+		input       string
+		output      string
+	}{
+		{description: "headings", input: "# one\n### three ###", output: "<h1>one</h1>\n<h3>three</h3>\n"},
+		{description: "paragraphs and breaks", input: "a  \nb\\\nc\nd\n\ne", output: "<p>a<br>\nb<br>\nc\nd</p>\n<p>e</p>\n"},
+		{description: "rule", input: "a\n\n***\n\nb", output: "<p>a</p>\n<hr>\n<p>b</p>\n"},
+		{description: "emphasis", input: "*em* _em_ **strong** __strong__ ***both*** ~~del~~", output: "<p><em>em</em> <em>em</em> <strong>strong</strong> <strong>strong</strong> <em><strong>both</strong></em> <del>del</del></p>\n"},
+		{description: "nested emphasis", input: "**a _b_ c**", output: "<p><strong>a <em>b</em> c</strong></p>\n"},
+		{description: "underscores within words", input: "snake_case_name", output: "<p>snake_case_name</p>\n"},
+		{description: "unclosed emphasis", input: "*a **b ~c", output: "<p>*a **b ~c</p>\n"},
+		{description: "emphasis after a space does not close", input: "*a *b*", output: "<p><em>a *b</em></p>\n"},
+		{description: "escapes", input: `\*a\* \[b\]`, output: "<p>*a* [b]</p>\n"},
+		{description: "code span", input: "`a` ``b ` c`` ```d", output: "<p><code>a</code> <code>b ` c</code> ```d</p>\n"},
+		{description: "fenced code", input: "~~~\n*a*\n~~~\nb", output: "<pre><code>*a*\n</code></pre>\n<p>b</p>\n"},
+		{description: "unclosed fence", input: "```\na", output: "<pre><code>a\n</code></pre>\n"},
+		{description: "link", input: "[a *b*](https://x.com)", output: "<p><a href=\"https://x.com\" rel=\"nofollow noopener noreferrer\">a <em>b</em></a></p>\n"},
+		{description: "link with a title", input: `[a](https://x.com "t")`, output: "<p><a href=\"https://x.com\" title=\"t\" rel=\"nofollow noopener noreferrer\">a</a></p>\n"},
+		{description: "link with parentheses", input: "[a](https://x.com/(b))", output: "<p><a href=\"https://x.com/(b)\" rel=\"nofollow noopener noreferrer\">a</a></p>\n"},
+		{description: "brackets that are no link", input: "[a] (b) [c](", output: "<p>[a] (b) [c](</p>\n"},
+		{description: "autolinks", input: "<https://x.com> <mailto:a@x.com> <b>", output: "<p><a href=\"https://x.com\" rel=\"nofollow noopener noreferrer\">https://x.com</a> <a href=\"mailto:a@x.com\" rel=\"nofollow noopener noreferrer\">a@x.com</a> &lt;b&gt;</p>\n"},
+		{description: "block quote", input: "> a\n> > b", output: "<blockquote>\n<p>a</p>\n<blockquote>\n<p>b</p>\n</blockquote>\n</blockquote>\n"},
+		{description: "unordered list", input: "- a\n- b\n  - c", output: "<ul>\n<li>a</li>\n<li><p>b</p>\n<ul>\n<li>c</li>\n</ul>\n</li>\n</ul>\n"},
+		{description: "ordered list", input: "3. a\n4. b", output: "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{description: "control characters", input: "a\x00b\r\nc", output: "<p>ab\nc</p>\n"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.output, Markdown(tc.input), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	assert.Equal(t, "<p>a &lt;b&gt;<br>c</p>\n<p>*d*</p>\n", Plain("a <b>\nc\n\n\n*d*"), "TEST, failed.\nplain text")
+}
+
+//nolint:lll // test cases need to be readable
+func TestMarkdown_XSS(t *testing.T) {
+	tests := []struct {
+		description string
+		input       string
+		output      string
+	}{
+		{description: "script", input: "<script>alert(1)</script>", output: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{description: "event attribute", input: `<img src=x onerror="alert(1)">`, output: "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{description: "javascript link", input: "[a](javascript:alert(1))", output: "<p>a</p>\n"},
+		{description: "javascript link in another case", input: "[a](JaVaScRiPt:alert(1))", output: "<p>a</p>\n"},
+		{description: "javascript link with an entity", input: "[a](&#106;avascript:alert(1))", output: "<p>a</p>\n"},
+		{description: "data link", input: "[a](data:text/html;base64,PHNjcmlwdD4=)", output: "<p>a</p>\n"},
+		{description: "protocol relative link", input: "[a](//evil.com)", output: "<p>a</p>\n"},
+		{description: "javascript autolink", input: "<javascript:alert(1)>", output: "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+		{description: "quote breaking out of a title", input: `[a](https://x.com "t\" onmouseover=\"alert(1)")`, output: "<p><a href=\"https://x.com\" title=\"t\\&#34; onmouseover=\\&#34;alert(1)\" rel=\"nofollow noopener noreferrer\">a</a></p>\n"},
+		{description: "quote breaking out of a link", input: `[a](https://x.com/"onclick="alert(1))`, output: "<p><a href=\"https://x.com/&#34;onclick=&#34;alert(1)\" rel=\"nofollow noopener noreferrer\">a</a></p>\n"},
+		{description: "html in a code span", input: "`<svg onload=alert(1)>`", output: "<p><code>&lt;svg onload=alert(1)&gt;</code></p>\n"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.output, Markdown(tc.input), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		description string
+		input       string
+		output      string
+	}{
+		{description: "allowed tags", input: "<p><strong>a</strong><br><em>b</em></p>", output: "<p><strong>a</strong><br><em>b</em></p>"},
+		{description: "scripts are dropped with their content", input: "<p>a<script>alert(1)</script>b</p>", output: "<p>ab</p>"},
+		{description: "styles and frames are dropped with their content", input: "<style>p{}</style><iframe src=x>c</iframe>d", output: "d"},
+		{description: "other tags keep their text", input: "<div><span>a</span></div>", output: "a"},
+		{description: "event attributes", input: `<p onclick="alert(1)" style="x">a</p>`, output: "<p>a</p>"},
+		{description: "img with onerror", input: `<img src=x onerror=alert(1)>`, output: ""},
+		{description: "svg with onload", input: `<svg onload=alert(1)><p>a</p></svg>`, output: "<p>a</p>"},
+		{description: "javascript href", input: `<a href="javascript:alert(1)">a</a>`, output: `<a rel="nofollow noopener noreferrer">a</a>`},
+		{description: "javascript href with entities and spaces", input: `<a href=" &#106;ava&#x73;cript:alert(1)">a</a>`, output: `<a rel="nofollow noopener noreferrer">a</a>`},
+		{description: "javascript href with a tab", input: "<a href=\"java\tscript:alert(1)\">a</a>", output: `<a rel="nofollow noopener noreferrer">a</a>`},
+		{description: "vbscript href", input: `<a href="vbscript:msgbox(1)">a</a>`, output: `<a rel="nofollow noopener noreferrer">a</a>`},
+		{description: "safe href", input: `<a href="https://x.com/?a=1&b=2" target="_blank">a</a>`, output: `<a href="https://x.com/?a=1&amp;b=2" rel="nofollow noopener noreferrer">a</a>`},
+		{description: "rel is set by the policy", input: `<a href="https://x.com" rel="opener">a</a>`, output: `<a href="https://x.com" rel="nofollow noopener noreferrer">a</a>`},
+		{description: "invalid start", input: `<ol start="-1"><li>a</li></ol><ol start="x"></ol>`, output: "<ol><li>a</li></ol><ol></ol>"},
+		{description: "comments", input: "a<!-- <script>alert(1)</script> -->b", output: "ab"},
+		{description: "tags left open are closed", input: "<blockquote><p><em>a", output: "<blockquote><p><em>a</em></p></blockquote>"},
+		{description: "end tag closes what it contains", input: "<p><em>a</p>b", output: "<p><em>a</em></p>b"},
+		{description: "stray end tags", input: "</p>a</em>", output: "a"},
+		{description: "text is escaped", input: "a &lt;b&gt; \"c\"", output: "a &lt;b&gt; &#34;c&#34;"},
+		{description: "unclosed script", input: "a<script>alert(1)", output: "a"},
+		{description: "self-closing tags that are not void are closed", input: `<a href="https://x.com"/>a<em/>`, output: `<a href="https://x.com" rel="nofollow noopener noreferrer"></a>a<em></em>`},
+		{description: "carriage returns of character references", input: "a&#13;b", output: "a&#13;b"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.output, Sanitize(tc.input), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		safe bool
+	}{
+		{url: "https://x.com", safe: true},
+		{url: "HTTP://x.com/a?b#c", safe: true},
+		{url: "mailto:a@x.com", safe: true},
+		{url: "javascript:alert(1)"},
+		{url: "JAVASCRIPT:alert(1)"},
+		{url: "data:text/html,x"},
+		{url: "//x.com"},
+		{url: "/a"},
+		{url: "https://"},
+		{url: "ftp://x.com"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.safe, SafeURL(tc.url), "TEST [%v], failed.\n%s", i+1, tc.url)
+	}
+}
+
+// TestMarkdown_Linear guards against inputs that rescan the text from every delimiter.
+func TestMarkdown_Linear(t *testing.T) {
+	inputs := map[string]string{
+		"brackets":           strings.Repeat("[", 160_000),
+		"unclosed links":     strings.Repeat("[a](", 40_000),
+		"emphasis":           strings.Repeat("*a ", 50_000),
+		"underscores":        strings.Repeat("_a ", 50_000),
+		"strikethrough":      strings.Repeat("~~a ", 40_000),
+		"long delimiter run": "a " + strings.Repeat("*", 160_000),
+		"backtick runs":      strings.Repeat("`a``b", 30_000),
+		"autolinks":          strings.Repeat("<a", 80_000),
+	}
+
+	for description, input := range inputs {
+		start := time.Now()
+
+		Markdown(input)
+
+		assert.Less(t, int64(time.Since(start)), int64(2*time.Second), "TEST, failed.\n%s", description)
+	}
+}
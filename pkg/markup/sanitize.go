@@ -0,0 +1,178 @@
+package markup
+
+import (
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// policy lists the tags that are allowed, with the attributes allowed on each.
+//
+//nolint:gochecknoglobals // read-only sanitizer policy
+var policy = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "em": nil, "del": nil, "code": nil, "pre": nil, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a": {"href", "title"},
+}
+
+// voids are the allowed tags that have no end tag, and dropped are the tags whose content is dropped along with them.
+//
+//nolint:gochecknoglobals // read-only sets of tags
+var (
+	voids   = map[string]bool{"br": true, "hr": true}
+	dropped = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true, "noscript": true, "textarea": true, "title": true}
+)
+
+// schemes lists the schemes that links can have. Relative links are not allowed, as content is served on other sites too.
+//
+//nolint:gochecknoglobals // read-only set of schemes
+var schemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// linkRel is set on every link, so that links neither pass on ranking nor get a handle on the page they are opened from.
+const linkRel = "nofollow noopener noreferrer"
+
+// Sanitize keeps the tags and attributes of the policy, and drops everything else.
+// The text of a dropped tag is kept, except for the tags that hold scripts, styles or embedded content.
+// Links are kept only with safe URLs, and tags left open are closed.
+func Sanitize(s string) string {
+	var b strings.Builder
+
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	open := make([]string, 0)
+	skip := 0
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case xhtml.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+
+			return b.String()
+		case xhtml.TextToken:
+			if skip == 0 {
+				b.WriteString(escape(string(z.Text())))
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			tok := z.Token()
+
+			if dropped[tok.Data] {
+				if tt == xhtml.StartTagToken {
+					skip++
+				}
+
+				continue
+			}
+
+			allowedAttrs, ok := policy[tok.Data]
+			if skip != 0 || !ok {
+				continue
+			}
+
+			b.WriteString("<" + tok.Data + attributes(tok, allowedAttrs) + ">")
+
+			switch {
+			case voids[tok.Data]:
+			case tt == xhtml.StartTagToken:
+				open = append(open, tok.Data)
+			default:
+				// browsers ignore the slash of a self-closing tag that is not void, and would leave it open
+				b.WriteString("</" + tok.Data + ">")
+			}
+		case xhtml.EndTagToken:
+			tok := z.Token()
+
+			if dropped[tok.Data] {
+				if skip != 0 {
+					skip--
+				}
+
+				continue
+			}
+
+			if skip != 0 {
+				continue
+			}
+
+			// the end tag closes the elements opened within the element it ends
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != tok.Data {
+					continue
+				}
+
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+
+				open = open[:i]
+
+				break
+			}
+		case xhtml.CommentToken, xhtml.DoctypeToken:
+		}
+	}
+}
+
+// attributes returns the allowed attributes of a tag, with safe values alone.
+func attributes(tok xhtml.Token, allowed []string) string {
+	var b strings.Builder
+
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || !contains(allowed, attr.Key) {
+			continue
+		}
+
+		switch attr.Key {
+		case "href":
+			attr.Val = strings.TrimSpace(attr.Val)
+			if !SafeURL(attr.Val) {
+				continue
+			}
+		case "start":
+			if n, err := strconv.Atoi(attr.Val); err != nil || n < 0 {
+				continue
+			}
+		}
+
+		b.WriteString(" " + attr.Key + `="` + escape(attr.Val) + `"`)
+	}
+
+	if tok.Data == "a" {
+		b.WriteString(` rel="` + linkRel + `"`)
+	}
+
+	return b.String()
+}
+
+// escape escapes text and attribute values. Carriage returns, which a character reference can decode to,
+// are escaped too, as a parser would read them as line feeds.
+func escape(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\r", "&#13;")
+}
+
+// SafeURL tells if a URL can be linked to, which takes an absolute URL of an allowed scheme.
+func SafeURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || !schemes[strings.ToLower(u.Scheme)] {
+		return false
+	}
+
+	return u.Scheme == "mailto" || u.Host != ""
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets that are full again are dropped, as they are the same as no bucket.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time // time the tokens were counted at
+	fullAt time.Time
+}
+
+type memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemory returns a Store that holds the buckets in memory.
+func NewMemory() Store {
+	return &memory{buckets: make(map[string]*bucket)}
+}
+
+func (m *memory) Take(key string, policy Policy, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	limit := float64(policy.Limit)
+	interval := policy.interval()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit, last: now}
+		m.buckets[key] = b
+	}
+
+	// refill the tokens for the time since they were last counted
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(interval)
+		if b.tokens > limit {
+			b.tokens = limit
+		}
+
+		b.last = now
+	}
+
+	result := Result{Allowed: b.tokens >= 1}
+
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((limit - b.tokens) * float64(interval))
+	b.fullAt = now.Add(result.Reset)
+
+	return result, nil
+}
+
+func (m *memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if !now.Before(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.lastSweep = now
+}
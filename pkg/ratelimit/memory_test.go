@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:lll // test cases need to be readable
+func TestMemory_Take(t *testing.T) {
+	store := NewMemory()
+	policy := Policy{Limit: 2, Period: time.Minute, Key: ByIP} // a token is refilled every 30s
+	start := time.Date(2021, 5, 23, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		description string
+		key         string
+		after       time.Duration
+		output      Result
+	}{
+		{description: "first request of a key finds a full bucket", key: "a", output: Result{Allowed: true, Remaining: 1, Reset: 30 * time.Second}},
+		{description: "last token", key: "a", output: Result{Allowed: true, Remaining: 0, Reset: time.Minute}},
+		{description: "empty bucket", key: "a", output: Result{Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second}},
+		{description: "half of a token refilled", key: "a", after: 15 * time.Second, output: Result{Remaining: 0, Reset: 45 * time.Second, RetryAfter: 15 * time.Second}},
+		{description: "a token refilled", key: "a", after: 30 * time.Second, output: Result{Allowed: true, Remaining: 0, Reset: time.Minute}},
+		{description: "buckets of other keys are apart", key: "b", after: 30 * time.Second, output: Result{Allowed: true, Remaining: 1, Reset: 30 * time.Second}},
+		{description: "refilled no more than the limit", key: "a", after: time.Hour, output: Result{Allowed: true, Remaining: 1, Reset: 30 * time.Second}},
+	}
+
+	for i, tc := range tests {
+		output, err := store.Take(tc.key, policy, start.Add(tc.after))
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	store := NewMemory().(*memory)
+	policy := Policy{Limit: 2, Period: time.Minute, Key: ByIP}
+	start := time.Date(2021, 5, 23, 15, 4, 5, 0, time.UTC)
+
+	_, _ = store.Take("a", policy, start) // full again 30s later
+	_, _ = store.Take("b", policy, start)
+	_, _ = store.Take("b", policy, start) // full again a minute later
+
+	// buckets are swept once a minute at most
+	_, _ = store.Take("c", policy, start.Add(45*time.Second)) // full again at 75s
+	assert.Len(t, store.buckets, 3, "TEST, failed.\nno sweep within a minute")
+
+	// the buckets that are full again are dropped, the rest are kept
+	_, _ = store.Take("d", policy, start.Add(70*time.Second))
+
+	assert.Contains(t, store.buckets, "c", "TEST, failed.\nbucket not yet full")
+	assert.Contains(t, store.buckets, "d", "TEST, failed.\nbucket of the request")
+	assert.Len(t, store.buckets, 2, "TEST, failed.\nfull buckets dropped")
+}
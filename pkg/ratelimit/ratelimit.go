@@ -0,0 +1,66 @@
+// Package ratelimit limits the rate of requests by token buckets.
+// A bucket holds as many tokens as the limit of its policy and is refilled evenly over its period.
+// Every request takes a token, and a request that finds the bucket empty is refused.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key is what the requests of a bucket have in common.
+type Key string
+
+const (
+	ByUser Key = "user" // the account of the claims, or the client IP for requests without claims
+	ByIP   Key = "ip"   // the client IP
+)
+
+// Policy is the size and refill period of the buckets of a route.
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Key    Key
+}
+
+// Parse reads a policy written as "<limit>/<period>", such as "5/1m".
+func Parse(value string, key Key) (Policy, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return Policy{}, fmt.Errorf("rate limit %q is not of the form <limit>/<period>", value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q has an invalid limit", value)
+	}
+
+	period, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || period <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q has an invalid period", value)
+	}
+
+	return Policy{Limit: limit, Period: period, Key: key}, nil
+}
+
+// interval is the time it takes to refill a single token.
+func (p Policy) interval() time.Duration {
+	return p.Period / time.Duration(p.Limit)
+}
+
+// Result is the state of a bucket once a request took its token.
+type Result struct {
+	Allowed    bool
+	Remaining  int           // tokens left in the bucket
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until a refused request can be made again
+}
+
+// Store holds the buckets. The in-memory store serves a single instance of the app,
+// instances that share their limits need a store they share.
+type Store interface {
+	// Take takes a token from the bucket of a key, which is refilled by the policy up to the given time.
+	Take(key string, policy Policy, now time.Time) (Result, error)
+}
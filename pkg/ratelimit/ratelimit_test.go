@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		description string
+		value       string
+		output      Policy
+		err         bool
+	}{
+		{description: "limit per minute", value: "5/1m", output: Policy{Limit: 5, Period: time.Minute, Key: ByIP}},
+		{description: "spaces around the parts", value: " 30 / 1h ", output: Policy{Limit: 30, Period: time.Hour, Key: ByIP}},
+		{description: "no period", value: "5", err: true},
+		{description: "limit that is not a number", value: "five/1m", err: true},
+		{description: "limit of zero", value: "0/1m", err: true},
+		{description: "period that is not a duration", value: "5/minute", err: true},
+		{description: "negative period", value: "5/-1m", err: true},
+	}
+
+	for i, tc := range tests {
+		output, err := Parse(tc.value, ByIP)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err != nil, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -0,0 +1,6 @@
+package response
+
+// Redirect is returned by a handler to send the client to the location where a resource has moved for good.
+type Redirect struct {
+	Location string `json:"location"`
+}
@@ -18,14 +18,33 @@ func WriteResponse(w http.ResponseWriter, err error, data interface{}, logger lo
 	var (
 		respData interface{}
 		resp     interface{}
+		meta     *pageMeta
 		errtype  string
 	)
 
+	// a redirect is written with the location it points to, the body is for clients that do not follow it
+	if redirect, ok := data.(Redirect); ok && err == nil {
+		w.Header().Set("Location", redirect.Location)
+		w.WriteHeader(http.StatusMovedPermanently)
+		setResponse(w, nil, struct {
+			Status string   `json:"status"`
+			Data   Redirect `json:"data"`
+		}{Status: "success", Data: redirect}, logger)
+
+		return
+	}
+
 	errtype = setHeader(w, err)
 	if errtype == "" {
 		err = nil
 	}
 
+	// lists are wrapped with the cursor of their next page
+	if page, ok := data.(models.Paginated); ok {
+		data = page.Data
+		meta = &pageMeta{NextCursor: page.NextCursor}
+	}
+
 	switch data := data.(type) {
 	case auth.JWKS:
 		setResponse(w, nil, data, logger)
@@ -34,6 +53,14 @@ func WriteResponse(w http.ResponseWriter, err error, data interface{}, logger lo
 		respData = getAccountResponse(data)
 	case *models.User:
 		respData = getUserResponse(data)
+	case []*models.User:
+		users := make([]userResp, 0, len(data))
+
+		for _, user := range data {
+			users = append(users, getUserResponse(user))
+		}
+
+		respData = users
 	default:
 		respData = data
 	}
@@ -43,9 +70,11 @@ func WriteResponse(w http.ResponseWriter, err error, data interface{}, logger lo
 		resp = struct {
 			Status string      `json:"status"`
 			Data   interface{} `json:"data,omitempty"`
+			Meta   *pageMeta   `json:"meta,omitempty"`
 		}{
 			Status: "success",
 			Data:   respData,
+			Meta:   meta,
 		}
 
 	default:
@@ -119,11 +148,39 @@ func setHeader(w http.ResponseWriter, err error) string {
 	case errors.BodyRead:
 		w.WriteHeader(http.StatusBadRequest)
 		return "body-read-error"
+	case errors.UnsupportedImage:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return "unsupported-image"
+	case errors.ImageTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return "image-too-large"
+	case errors.UploadTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return "upload-too-large"
+	case errors.ImageDimensions:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return "image-dimensions"
+	case errors.TooManyImages:
+		w.WriteHeader(http.StatusBadRequest)
+		return "too-many-images"
+	case errors.ContentRejected:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return "content-rejected"
+	case errors.TooManyRequests:
+		w.WriteHeader(http.StatusTooManyRequests)
+		return "too-many-requests"
+	case errors.Unsupported:
+		w.WriteHeader(http.StatusNotImplemented)
+		return "unsupported"
 	}
 
 	return ""
 }
 
+type pageMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 type userResp struct {
 	UserName string  `json:"user_name"`
 	FName    string  `json:"f_name"`
@@ -172,6 +229,7 @@ type accountResp struct {
 	CreatedAt  time.Time     `json:"created_at"`
 	DelRequest *time.Time    `json:"del_req,omitempty"`
 	Status     string        `json:"status"`
+	Role       string        `json:"role,omitempty"`
 }
 
 func getAccountResponse(data interface{}) accountResp {
@@ -188,6 +246,7 @@ func getAccountResponse(data interface{}) accountResp {
 		CreatedAt:  account.CreatedAt,
 		DelRequest: nil,
 		Status:     account.Status,
+		Role:       account.Role,
 	}
 
 	if account.Email.String != "" {
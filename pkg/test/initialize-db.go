@@ -90,6 +90,9 @@ func InitializeTestAccountsTable(sqlDB *sql.DB, logger log.Logger, directory str
 	_, _ = dot.Exec(sqlDB, "create")
 	_, _ = dot.Exec(sqlDB, "use")
 	_, _ = dot.Exec(sqlDB, "create-table")
+	_, _ = dot.Exec(sqlDB, "create-follows-table")
+	_, _ = dot.Exec(sqlDB, "create-blocks-table")
+	_, _ = dot.Exec(sqlDB, "create-mutes-table")
 	_, _ = dot.Exec(sqlDB, "insert-aakanksha")
 	_, _ = dot.Exec(sqlDB, "insert-mainak")
 	_, _ = dot.Exec(sqlDB, "insert-divij")
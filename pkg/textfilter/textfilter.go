@@ -0,0 +1,364 @@
+// Package textfilter screens the text written by users against a set of rules.
+// A rule matches words of a blocklist or regular expressions, and its policy masks the matched text,
+// flags the text for a moderator to review or rejects it. A spam heuristic flags or rejects text
+// dense with links or made of repeated content.
+// The rules are read from a JSON file, which is read again whenever it changes.
+package textfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policies of a rule, from the mildest.
+const (
+	Mask   = "mask"   // the matched text is masked
+	Flag   = "flag"   // the text is kept, and flagged for a moderator to review
+	Reject = "reject" // the text is refused
+)
+
+// Names of the rules of the spam heuristic.
+const (
+	SpamLinks   = "spam:links"
+	SpamRepeats = "spam:repeats"
+)
+
+// default thresholds of the spam heuristic, which can be overridden by the rule set
+const (
+	defaultMinLinks       = 3
+	defaultMaxLinkDensity = 0.1
+	defaultMaxRepeats     = 3
+	minRepeatedLine       = 10 // runes of a line that counts as repeated, shorter lines repeat in ordinary text
+)
+
+//nolint:gochecknoglobals // compiled once, used for every text
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+//nolint:gochecknoglobals // order of the policies, by how strongly they apply
+var severity = map[string]int{"": 0, Mask: 1, Flag: 2, Reject: 3}
+
+// Rule matches words of a blocklist, regardless of case, or regular expressions.
+type Rule struct {
+	Name     string   `json:"name"`
+	Words    []string `json:"words"`
+	Patterns []string `json:"patterns"`
+	Policy   string   `json:"policy"`
+}
+
+// Spam sets the thresholds of the spam heuristic. Its policy is flag or reject, and an empty policy disables it.
+type Spam struct {
+	Policy         string  `json:"policy"`
+	MinLinks       int     `json:"min_links"`        // links a text may have however short it is
+	MaxLinkDensity float64 `json:"max_link_density"` // links per word of a text with more links than MinLinks
+	MaxRepeats     int     `json:"max_repeats"`      // times a line, or a word in a row, may be repeated
+}
+
+// RuleSet is the content of a rule file.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+	Spam  Spam   `json:"spam"`
+}
+
+// Match is the rule a text matched and the policy it applies. It is zero for a text that matched no rule.
+type Match struct {
+	Rule   string
+	Policy string
+}
+
+// Strongest returns the match whose policy applies most strongly, the first one of equal matches.
+func Strongest(matches ...Match) Match {
+	var strongest Match
+
+	for _, m := range matches {
+		if severity[m.Policy] > severity[strongest.Policy] {
+			strongest = m
+		}
+	}
+
+	return strongest
+}
+
+type rule struct {
+	name    string
+	policy  string
+	pattern *regexp.Regexp
+}
+
+// Filter applies a rule set. It is safe for concurrent use, and its rules can be reloaded while it is in use.
+// A nil Filter matches nothing.
+type Filter struct {
+	path string
+
+	mu      sync.RWMutex
+	modTime time.Time
+	rules   []rule
+	spam    Spam
+}
+
+// New returns a Filter of a rule set.
+func New(set RuleSet) (*Filter, error) {
+	f := &Filter{}
+
+	if err := f.set(set); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Load returns a Filter of the rules in a file, which Reload reads again.
+// Without a file, the Filter matches nothing.
+func Load(path string) (*Filter, error) {
+	f := &Filter{path: path}
+
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Reload reads the rule file again if it changed since it was last read, and tells if the rules changed.
+// A file that cannot be read or holds invalid rules leaves the rules as they are, and is not read again until it changes.
+func (f *Filter) Reload() (bool, error) {
+	if f == nil || f.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.RLock()
+	unchanged := info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	f.mu.Lock()
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	var set RuleSet
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return false, fmt.Errorf("cannot parse rules of %s: %w", f.path, err)
+	}
+
+	if err := f.set(set); err != nil {
+		return false, fmt.Errorf("invalid rules in %s: %w", f.path, err)
+	}
+
+	return true, nil
+}
+
+// set compiles a rule set, and swaps it for the current rules once all of it is valid.
+func (f *Filter) set(set RuleSet) error {
+	rules := make([]rule, 0, len(set.Rules))
+
+	for i, r := range set.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		if _, ok := severity[r.Policy]; !ok || r.Policy == "" {
+			return fmt.Errorf("%s has an unknown policy %q", r.Name, r.Policy)
+		}
+
+		alternatives := make([]string, 0, len(r.Words)+len(r.Patterns))
+
+		if len(r.Words) != 0 {
+			words := make([]string, 0, len(r.Words))
+			for _, word := range r.Words {
+				words = append(words, regexp.QuoteMeta(word))
+			}
+
+			// the flag is scoped to the words, so that the patterns of the rule keep their own case
+			alternatives = append(alternatives, `(?i:\b(?:`+strings.Join(words, "|")+`)\b)`)
+		}
+
+		for _, p := range r.Patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("%s has an invalid pattern: %w", r.Name, err)
+			}
+
+			alternatives = append(alternatives, "(?:"+p+")")
+		}
+
+		if len(alternatives) == 0 {
+			return fmt.Errorf("%s has no words or patterns", r.Name)
+		}
+
+		rules = append(rules, rule{name: r.Name, policy: r.Policy, pattern: regexp.MustCompile(strings.Join(alternatives, "|"))})
+	}
+
+	spam, err := withDefaults(set.Spam)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.rules, f.spam = rules, spam
+	f.mu.Unlock()
+
+	return nil
+}
+
+func withDefaults(spam Spam) (Spam, error) {
+	switch spam.Policy {
+	case "", Flag, Reject:
+	default:
+		return spam, fmt.Errorf("spam has an unknown policy %q", spam.Policy)
+	}
+
+	if spam.MinLinks <= 0 {
+		spam.MinLinks = defaultMinLinks
+	}
+
+	if spam.MaxLinkDensity <= 0 {
+		spam.MaxLinkDensity = defaultMaxLinkDensity
+	}
+
+	if spam.MaxRepeats <= 0 {
+		spam.MaxRepeats = defaultMaxRepeats
+	}
+
+	return spam, nil
+}
+
+// Apply checks a text against the rules, masking the text matched by the rules that mask it.
+// It returns the text and the strongest match, and stops at the first rule that rejects the text.
+func (f *Filter) Apply(text string) (string, Match) {
+	if f == nil {
+		return text, Match{}
+	}
+
+	f.mu.RLock()
+	rules := f.rules
+	f.mu.RUnlock()
+
+	var match Match
+
+	for _, r := range rules {
+		if !r.pattern.MatchString(text) {
+			continue
+		}
+
+		switch r.policy {
+		case Reject:
+			return text, Match{Rule: r.name, Policy: Reject}
+		case Mask:
+			text = r.pattern.ReplaceAllStringFunc(text, mask)
+		}
+
+		match = Strongest(match, Match{Rule: r.name, Policy: r.policy})
+	}
+
+	return text, match
+}
+
+func mask(s string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(s))
+}
+
+// Spam checks a text by the spam heuristic.
+func (f *Filter) Spam(text string) Match {
+	if f == nil {
+		return Match{}
+	}
+
+	f.mu.RLock()
+	spam := f.spam
+	f.mu.RUnlock()
+
+	if spam.Policy == "" {
+		return Match{}
+	}
+
+	words := strings.Fields(text)
+
+	if links := len(linkPattern.FindAllString(text, -1)); links > spam.MinLinks &&
+		float64(links)/float64(len(words)) > spam.MaxLinkDensity {
+		return Match{Rule: SpamLinks, Policy: spam.Policy}
+	}
+
+	if repeats(text) > spam.MaxRepeats {
+		return Match{Rule: SpamRepeats, Policy: spam.Policy}
+	}
+
+	return Match{}
+}
+
+// repeats counts the times the most repeated line of a text is repeated, or a word in a row if that is more.
+// Lines are compared regardless of case and spacing. Code in fences, short lines and lines or words without
+// a letter or a digit, such as closing braces and rules, are repeated in ordinary text and are not counted.
+func repeats(text string) int {
+	most := 0
+	lines := make(map[string]int)
+
+	var (
+		fenced bool
+		last   string // previous word, of the run
+		run    int
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+
+		if fenced {
+			continue
+		}
+
+		words := strings.Fields(line)
+
+		for _, word := range words {
+			switch {
+			case !hasAlphanumeric(word):
+				run = 0
+			case run > 0 && strings.EqualFold(word, last):
+				run++
+			default:
+				run = 1
+			}
+
+			last = word
+
+			if run > most {
+				most = run
+			}
+		}
+
+		line = strings.ToLower(strings.Join(words, " "))
+		if utf8.RuneCountInString(line) < minRepeatedLine || !hasAlphanumeric(line) {
+			continue
+		}
+
+		if lines[line]++; lines[line] > most {
+			most = lines[line]
+		}
+	}
+
+	return most
+}
+
+func hasAlphanumeric(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0
+}
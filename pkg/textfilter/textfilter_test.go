@@ -0,0 +1,188 @@
+package textfilter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:lll // test cases need to be readable
+func TestFilter_Apply(t *testing.T) {
+	f, err := New(RuleSet{Rules: []Rule{
+		{Name: "slurs", Words: []string{"darn", "heck"}, Policy: Mask},
+		{Name: "shouting", Words: []string{"scam"}, Patterns: []string{`[A-Z]{12,}`}, Policy: Flag},
+		{Name: "phishing", Patterns: []string{`(?i)free\s+crypto`}, Policy: Reject},
+	}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		description string
+		input       string
+		output      string
+		match       Match
+	}{
+		{description: "clean text", input: "a blog on chocolate", output: "a blog on chocolate"},
+		{description: "masked words, regardless of case", input: "Darn it, what the HECK", output: "**** it, what the ****", match: Match{Rule: "slurs", Policy: Mask}},
+		{description: "words are matched whole", input: "the darning needle", output: "the darning needle"},
+		{description: "flagged word", input: "this is a Scam", output: "this is a Scam", match: Match{Rule: "shouting", Policy: Flag}},
+		{description: "flagged pattern, with its own case", input: "WHYISNOBODYREADING this", output: "WHYISNOBODYREADING this", match: Match{Rule: "shouting", Policy: Flag}},
+		{description: "case of the words does not leak into the patterns", input: "whyisnobodyreading this", output: "whyisnobodyreading this"},
+		{description: "strongest match of several rules", input: "darn, a scam", output: "****, a scam", match: Match{Rule: "shouting", Policy: Flag}},
+		{description: "rejected text", input: "darn, get FREE crypto", output: "****, get FREE crypto", match: Match{Rule: "phishing", Policy: Reject}},
+	}
+
+	for i, tc := range tests {
+		output, match := f.Apply(tc.input)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.match, match, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestFilter_Spam(t *testing.T) {
+	f, err := New(RuleSet{Spam: Spam{Policy: Flag}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	code := "```go\nif err != nil {\n\treturn err\n}\nif err != nil {\n\treturn err\n}\nif err != nil {\n\treturn err\n}\nif err != nil {\n\treturn err\n}\n```"
+
+	tests := []struct {
+		description string
+		input       string
+		match       Match
+	}{
+		{description: "clean text", input: "a blog on chocolate, and why bournville is the best"},
+		{description: "a few links", input: "see https://a.example and https://b.example and www.c.example for more"},
+		{description: "dense links", input: "https://a.example https://b.example https://c.example www.d.example", match: Match{Rule: SpamLinks, Policy: Flag}},
+		{description: "many links in a long text", input: "see https://a.example https://b.example https://c.example https://d.example " + strings.Repeat("and some words ", 20)},
+		{description: "repeated lines", input: "buy my book now\nbuy my book now\nBuy  my book NOW\nbuy my book now", match: Match{Rule: SpamRepeats, Policy: Flag}},
+		{description: "lines repeated up to the limit", input: "buy my book now\nbuy my book now\nbuy my book now"},
+		{description: "repeated word", input: "buy buy buy BUY my book", match: Match{Rule: SpamRepeats, Policy: Flag}},
+		{description: "repeated code in a fence", input: code},
+		{description: "closing braces", input: "}\n}\n}\n}\n}"},
+		{description: "rules and list markers", input: "---\n- a\n---\n- b\n---\n- c\n---\n* * * * *"},
+		{description: "short lines", input: "ok.\nthanks\nok.\nthanks\nok.\nthanks\nok.\nthanks"},
+	}
+
+	for i, tc := range tests {
+		match := f.Spam(tc.input)
+
+		assert.Equal(t, tc.match, match, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+
+	// the heuristic is off without a policy
+	off, err := New(RuleSet{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, Match{}, off.Spam("buy buy buy buy buy"), "TEST, failed.\nspam heuristic off")
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		description string
+		set         RuleSet
+	}{
+		{description: "unknown policy", set: RuleSet{Rules: []Rule{{Name: "slurs", Words: []string{"darn"}, Policy: "ban"}}}},
+		{description: "missing policy", set: RuleSet{Rules: []Rule{{Name: "slurs", Words: []string{"darn"}}}}},
+		{description: "invalid pattern", set: RuleSet{Rules: []Rule{{Name: "shouting", Patterns: []string{`[A-Z`}, Policy: Flag}}}},
+		{description: "no words or patterns", set: RuleSet{Rules: []Rule{{Name: "empty", Policy: Mask}}}},
+		{description: "unknown spam policy", set: RuleSet{Spam: Spam{Policy: Mask}}},
+	}
+
+	for i, tc := range tests {
+		f, err := New(tc.set)
+
+		assert.Nil(t, f, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Error(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestFilter_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content-filter.json")
+
+	write := func(content string, modTime time.Time) {
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+
+	write(`{"rules": [{"name": "slurs", "words": ["darn"], "policy": "mask"}]}`, start)
+
+	f, err := Load(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	text, _ := f.Apply("darn, heck")
+	assert.Equal(t, "****, heck", text, "TEST [1], failed.\nrules of the file")
+
+	changed, err := f.Reload()
+	assert.Equal(t, false, changed, "TEST [2], failed.\nfile unchanged")
+	assert.NoError(t, err, "TEST [2], failed.\nfile unchanged")
+
+	write(`{"rules": [{"name": "slurs", "words": ["darn", "heck"], "policy": "mask"}]}`, start.Add(time.Minute))
+
+	changed, err = f.Reload()
+	assert.Equal(t, true, changed, "TEST [3], failed.\nfile changed")
+	assert.NoError(t, err, "TEST [3], failed.\nfile changed")
+
+	text, _ = f.Apply("darn, heck")
+	assert.Equal(t, "****, ****", text, "TEST [3], failed.\nrules read again")
+
+	// invalid rules leave the rules as they are, and are not read again until the file changes
+	write(`{"rules": [{"name": "slurs", "words": ["darn"], "policy": "ban"}]}`, start.Add(2*time.Minute))
+
+	changed, err = f.Reload()
+	assert.Equal(t, false, changed, "TEST [4], failed.\ninvalid rules")
+	assert.Error(t, err, "TEST [4], failed.\ninvalid rules")
+
+	text, _ = f.Apply("darn, heck")
+	assert.Equal(t, "****, ****", text, "TEST [4], failed.\nrules kept")
+
+	changed, err = f.Reload()
+	assert.Equal(t, false, changed, "TEST [5], failed.\ninvalid rules not read again")
+	assert.NoError(t, err, "TEST [5], failed.\ninvalid rules not read again")
+
+	write(`{"rules": [`, start.Add(3*time.Minute))
+
+	_, err = f.Reload()
+	assert.Error(t, err, "TEST [6], failed.\nfile that cannot be parsed")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err, "TEST [7], failed.\nmissing file")
+}
+
+func TestFilter_Nil(t *testing.T) {
+	f, err := Load("")
+	if assert.NoError(t, err) {
+		text, match := f.Apply("darn")
+
+		assert.Equal(t, "darn", text, "TEST, failed.\nfilter without a file")
+		assert.Equal(t, Match{}, match, "TEST, failed.\nfilter without a file")
+	}
+
+	var none *Filter
+
+	text, match := none.Apply("darn")
+	assert.Equal(t, "darn", text, "TEST, failed.\nnil filter")
+	assert.Equal(t, Match{}, match, "TEST, failed.\nnil filter")
+	assert.Equal(t, Match{}, none.Spam("buy buy buy buy"), "TEST, failed.\nnil filter")
+}
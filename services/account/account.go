@@ -24,6 +24,9 @@ import (
 type account struct {
 	accountStore stores.Account
 	blogService  services.Blog
+	followStore  stores.Follow
+	blockStore   stores.Block
+	muteStore    stores.Mute
 }
 
 func (a account) SendOTP(ctx *app.Context, phone string) (*models.VerificationResponse, error) {
@@ -147,10 +150,14 @@ func (a account) VerifyPhone(ctx *app.Context, sid, otp, reqURL string) error {
 	return errors.InvalidParam{Param: "OTP"}
 }
 
-func New(accountStore stores.Account, blogService services.Blog) services.Account {
+func New(accountStore stores.Account, blogService services.Blog, followStore stores.Follow,
+	blockStore stores.Block, muteStore stores.Mute) services.Account {
 	return account{
 		accountStore: accountStore,
 		blogService:  blogService,
+		followStore:  followStore,
+		blockStore:   blockStore,
+		muteStore:    muteStore,
 	}
 }
 
@@ -190,7 +197,19 @@ func (a account) GetAccountWithBlogs(ctx *app.Context, username string) (*models
 		return nil, errors.EntityNotFound{Entity: "user"}
 	}
 
-	blogs, err := a.blogService.GetAll(ctx, &models.Blog{AccountID: account.ID}, nil)
+	// the profiles of blocked accounts and of the accounts that block the user do not exist for them
+	if claims, ok := ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims); ok && claims.UserID != account.ID {
+		blocked, err := a.blockStore.Exists(ctx, claims.UserID, account.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if blocked {
+			return nil, errors.EntityNotFound{Entity: "user"}
+		}
+	}
+
+	blogs, err := a.blogService.GetAll(ctx, &models.BlogFilter{Blog: models.Blog{AccountID: account.ID}}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -381,5 +400,39 @@ func (a account) Login(ctx *app.Context, user *models.User) (*models.Account, er
 		return nil, errors.AuthError{Err: err, Msg: "invalid password"}
 	}
 
+	// a suspended account is not reactivated by logging in
+	if account.Status == models.AccountSuspended {
+		return nil, errors.AuthError{Msg: "account suspended"}
+	}
+
 	return a.Update(ctx, &models.Account{DelRequest: sql.NullTime{}, Status: "ACTIVE"}, account.ID)
 }
+
+// CheckActive refuses the requests of a suspended account, whose token is valid until it expires.
+// Requests without claims are left to the handlers.
+func (a account) CheckActive(ctx *app.Context) error {
+	claims, ok := ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims)
+	if !ok || claims == nil {
+		return nil
+	}
+
+	account, err := a.accountStore.Get(ctx, &models.Account{User: models.User{ID: claims.UserID}})
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		return errors.AuthError{Msg: "account not found"}
+	default:
+		return err
+	}
+
+	if account == nil {
+		return errors.AuthError{Msg: "account not found"}
+	}
+
+	if account.Status == models.AccountSuspended {
+		return errors.AuthError{Msg: "account suspended"}
+	}
+
+	return nil
+}
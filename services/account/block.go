@@ -0,0 +1,67 @@
+package account
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+)
+
+// Block makes the logged in user block the account of a username. Either of them stops following the other,
+// and neither sees the blogs and the profile of the other. Blocking an account twice has no effect.
+func (a account) Block(ctx *app.Context, username string) error {
+	blockerID, blockedID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err = a.blockStore.Create(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	if err = a.followStore.Delete(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	return a.followStore.Delete(ctx, blockedID, blockerID)
+}
+
+// Unblock makes the logged in user stop blocking the account of a username. Follows removed by the block are not restored.
+func (a account) Unblock(ctx *app.Context, username string) error {
+	blockerID, blockedID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return a.blockStore.Delete(ctx, blockerID, blockedID)
+}
+
+// GetBlocked retrieves the accounts the logged in user blocks.
+func (a account) GetBlocked(ctx *app.Context) ([]*models.User, error) {
+	return a.blockStore.GetBlocked(ctx, ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims).UserID)
+}
+
+// Mute makes the logged in user mute the account of a username, whose blogs are then not listed to them.
+// The muted account is not told, and still sees the blogs of the user. Muting an account twice has no effect.
+func (a account) Mute(ctx *app.Context, username string) error {
+	muterID, mutedID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return a.muteStore.Create(ctx, muterID, mutedID)
+}
+
+// Unmute makes the logged in user stop muting the account of a username.
+func (a account) Unmute(ctx *app.Context, username string) error {
+	muterID, mutedID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return a.muteStore.Delete(ctx, muterID, mutedID)
+}
+
+// GetMuted retrieves the accounts the logged in user mutes.
+func (a account) GetMuted(ctx *app.Context) ([]*models.User, error) {
+	return a.muteStore.GetMuted(ctx, ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims).UserID)
+}
@@ -0,0 +1,163 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+type mocks struct {
+	accountStore *stores.MockAccount
+	blogService  *services.MockBlog
+	followStore  *stores.MockFollow
+	blockStore   *stores.MockBlock
+	muteStore    *stores.MockMute
+}
+
+// initializeTest returns the service with mocks, and a context of the account with ID 1.
+func initializeTest(t *testing.T) (mocks, *app.Context, services.Account) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		accountStore: stores.NewMockAccount(ctrl),
+		blogService:  services.NewMockBlog(ctrl),
+		followStore:  stores.NewMockFollow(ctrl),
+		blockStore:   stores.NewMockBlock(ctrl),
+		muteStore:    stores.NewMockMute(ctrl),
+	}
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.WithValue(context.TODO(), auth.JWTContextKey("claims"), &auth.Claims{UserID: 1})
+
+	return m, ctx, New(m.accountStore, m.blogService, m.followStore, m.blockStore, m.muteStore)
+}
+
+// expectAccount makes the account store find the account of a username.
+func (m mocks) expectAccount(username string, id int64) {
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: username}}).Return(&models.Account{User: models.User{ID: id, UserName: username}}, nil)
+}
+
+func TestAccount_Block(t *testing.T) {
+	m, ctx, service := initializeTest(t)
+
+	// either account stops following the other
+	m.expectAccount("mainak_pandit", 2)
+	m.blockStore.EXPECT().Create(gomock.Any(), int64(1), int64(2)).Return(nil)
+	m.followStore.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(nil)
+	m.followStore.EXPECT().Delete(gomock.Any(), int64(2), int64(1)).Return(nil)
+
+	m.expectAccount("divij_gupta", 3)
+	m.blockStore.EXPECT().Create(gomock.Any(), int64(1), int64(3)).Return(errors.DBError{})
+
+	m.expectAccount("aakanksha_jais", 1)
+
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "unknown_user"}}).Return(nil, nil)
+
+	tests := []struct {
+		description string
+		username    string
+		err         error
+	}{
+		{description: "block an account", username: "mainak_pandit"},
+		{description: "block that cannot be recorded leaves the follows", username: "divij_gupta", err: errors.DBError{}},
+		{description: "block oneself", username: "aakanksha_jais", err: errors.InvalidParam{Param: "username"}},
+		{description: "block an unknown account", username: "unknown_user", err: errors.EntityNotFound{Entity: "user", ID: "unknown_user"}},
+		{description: "invalid username", username: "a b", err: errors.InvalidParam{Param: "username"}},
+	}
+
+	for i, tc := range tests {
+		err := service.Block(ctx, tc.username)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestAccount_Follow(t *testing.T) {
+	m, ctx, service := initializeTest(t)
+
+	m.expectAccount("mainak_pandit", 2)
+	m.blockStore.EXPECT().Exists(gomock.Any(), int64(1), int64(2)).Return(false, nil)
+	m.followStore.EXPECT().Create(gomock.Any(), int64(1), int64(2)).Return(nil)
+
+	// an account blocked either way cannot be followed, and does not exist for the follower
+	m.expectAccount("divij_gupta", 3)
+	m.blockStore.EXPECT().Exists(gomock.Any(), int64(1), int64(3)).Return(true, nil)
+
+	tests := []struct {
+		description string
+		username    string
+		err         error
+	}{
+		{description: "follow an account", username: "mainak_pandit"},
+		{description: "follow a blocked account", username: "divij_gupta", err: errors.EntityNotFound{Entity: "user", ID: "divij_gupta"}},
+	}
+
+	for i, tc := range tests {
+		err := service.Follow(ctx, tc.username)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestAccount_GetAccountWithBlogsBlocked(t *testing.T) {
+	m, ctx, service := initializeTest(t)
+	anonymous := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	anonymous.Context = context.TODO()
+
+	blogs := []*models.Blog{{BlogID: "MSI8WKNSH9", AccountID: 2}}
+
+	m.expectAccount("mainak_pandit", 2)
+	m.blockStore.EXPECT().Exists(gomock.Any(), int64(1), int64(2)).Return(false, nil)
+	m.blogService.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{Blog: models.Blog{AccountID: 2}}, nil).Return(blogs, nil).Times(2)
+
+	// the profile of an account blocked either way does not exist for the user
+	m.expectAccount("mainak_pandit", 2)
+	m.blockStore.EXPECT().Exists(gomock.Any(), int64(1), int64(2)).Return(true, nil)
+
+	// requests without claims are not checked for blocks
+	m.expectAccount("mainak_pandit", 2)
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		output      *models.Account
+		err         error
+	}{
+		{description: "profile of an account", ctx: ctx, output: &models.Account{User: models.User{ID: 2, UserName: "mainak_pandit"}, Blogs: []models.Blog{*blogs[0]}}},
+		{description: "profile of a blocked account", ctx: ctx, err: errors.EntityNotFound{Entity: "user"}},
+		{description: "profile read without claims", ctx: anonymous, output: &models.Account{User: models.User{ID: 2, UserName: "mainak_pandit"}, Blogs: []models.Blog{*blogs[0]}}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.GetAccountWithBlogs(tc.ctx, "mainak_pandit")
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestAccount_Mute(t *testing.T) {
+	m, ctx, service := initializeTest(t)
+
+	// a mute leaves the follows, and is not told to the muted account
+	m.expectAccount("mainak_pandit", 2)
+	m.muteStore.EXPECT().Create(gomock.Any(), int64(1), int64(2)).Return(nil)
+
+	m.expectAccount("mainak_pandit", 2)
+	m.muteStore.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(nil)
+
+	assert.Equal(t, nil, service.Mute(ctx, "mainak_pandit"), "TEST, failed.\nmute an account")
+	assert.Equal(t, nil, service.Unmute(ctx, "mainak_pandit"), "TEST, failed.\nunmute an account")
+}
@@ -0,0 +1,64 @@
+package account
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+// Follow makes the logged in user follow the account of a username. Following an account twice has no effect.
+// An account that either of them blocks cannot be followed, and is not found.
+func (a account) Follow(ctx *app.Context, username string) error {
+	followerID, followeeID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := a.blockStore.Exists(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return errors.EntityNotFound{Entity: "user", ID: username}
+	}
+
+	return a.followStore.Create(ctx, followerID, followeeID)
+}
+
+// Unfollow makes the logged in user stop following the account of a username.
+func (a account) Unfollow(ctx *app.Context, username string) error {
+	followerID, followeeID, err := a.pair(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return a.followStore.Delete(ctx, followerID, followeeID)
+}
+
+// pair resolves the ids of the logged in user and of the account of a username they follow, block or mute.
+// Users cannot follow, block or mute themselves.
+func (a account) pair(ctx *app.Context, username string) (userID, otherID int64, err error) {
+	err = validateUsername(username)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	userID = ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims).UserID
+
+	account, err := a.accountStore.Get(ctx, &models.Account{User: models.User{UserName: username}})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if account == nil {
+		return 0, 0, errors.EntityNotFound{Entity: "user", ID: username}
+	}
+
+	if account.ID == userID {
+		return 0, 0, errors.InvalidParam{Param: "username"}
+	}
+
+	return userID, account.ID, nil
+}
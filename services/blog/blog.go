@@ -3,9 +3,11 @@ package blog
 import (
 	"fmt"
 	"mime/multipart"
-	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/services"
 
@@ -16,40 +18,189 @@ import (
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/imaging"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/textfilter"
 	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
 )
 
+const (
+	maxContentLength = 100_000 // characters of the content of a blog, which bounds the cost of rendering it
+	maxFilterTags    = 10
+	publishBatchSize = 100
+	reviseAttempts   = 3 // revision numbers taken by concurrent updates of the same blog are retried
+	uploadPrefix     = "upload:"
+)
+
+//nolint:gochecknoglobals // statuses a blog can move to from each status, "" being a new blog
+var transitions = map[string][]string{
+	"":                     {models.StatusDraft, models.StatusScheduled, models.StatusPublished},
+	models.StatusDraft:     {models.StatusScheduled, models.StatusPublished},
+	models.StatusScheduled: {models.StatusDraft, models.StatusPublished},
+	models.StatusPublished: {models.StatusArchived},
+	models.StatusArchived:  {models.StatusPublished},
+}
+
+//nolint:gochecknoglobals // compiled once, used for every filtered listing
+var tagPattern = regexp.MustCompile(`^#[0-9A-Za-z_]+$`)
+
 type blog struct {
-	blogStore  stores.Blog
-	tagService services.Tag
-	imageStore stores.Image
+	blogStore     stores.Blog
+	tagService    services.Tag
+	imageStore    stores.Image
+	searchIndex   stores.SearchIndex
+	accountStore  stores.Account
+	revisionStore stores.Revision
+	uploadStore   stores.Upload
+	blobStore     stores.Blob
+	followStore   stores.Follow
+	blockStore    stores.Block
+	muteStore     stores.Mute
+	reportStore   stores.Report
+	contentFilter *textfilter.Filter
 }
 
-func New(blogStore stores.Blog, tagService services.Tag, imageStore stores.Image) services.Blog {
+func New(blogStore stores.Blog, tagService services.Tag, imageStore stores.Image, searchIndex stores.SearchIndex,
+	accountStore stores.Account, revisionStore stores.Revision, uploadStore stores.Upload, blobStore stores.Blob,
+	followStore stores.Follow, blockStore stores.Block, muteStore stores.Mute, reportStore stores.Report,
+	contentFilter *textfilter.Filter) services.Blog {
 	return blog{
-		blogStore:  blogStore,
-		tagService: tagService,
-		imageStore: imageStore,
+		blogStore:     blogStore,
+		tagService:    tagService,
+		imageStore:    imageStore,
+		searchIndex:   searchIndex,
+		accountStore:  accountStore,
+		revisionStore: revisionStore,
+		uploadStore:   uploadStore,
+		blobStore:     blobStore,
+		followStore:   followStore,
+		blockStore:    blockStore,
+		muteStore:     muteStore,
+		reportStore:   reportStore,
+		contentFilter: contentFilter,
 	}
 }
 
 // GetAll is used to retrieve all blogs that match the filter.
-func (b blog) GetAll(ctx *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error) {
+// The author of the filter is resolved to its account, a listing by an unknown author is empty.
+// Unpublished, unlisted and private blogs are listed to their author alone,
+// and blogs for followers to the followers of their author as well.
+// Blogs of the accounts the viewer blocks, is blocked by or mutes are not listed.
+func (b blog) GetAll(ctx *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error) {
 	if filter == nil {
-		filter = &models.Blog{}
+		filter = &models.BlogFilter{}
+	}
+
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	filter.Viewer = viewerID(ctx)
+
+	if filter.Status != "" && filter.Status != models.StatusPublished {
+		if filter.Viewer == 0 || (filter.AccountID != 0 && filter.AccountID != filter.Viewer) {
+			return []*models.Blog{}, nil
+		}
+
+		filter.AccountID = filter.Viewer
+	}
+
+	if filter.Author != "" {
+		account, err := b.accountStore.Get(ctx, &models.Account{User: models.User{UserName: filter.Author}})
+
+		switch err.(type) {
+		case nil:
+		case errors.EntityNotFound:
+			return []*models.Blog{}, nil
+		default:
+			return nil, err
+		}
+
+		if filter.AccountID != 0 && filter.AccountID != account.ID {
+			return []*models.Blog{}, nil
+		}
+
+		filter.AccountID = account.ID
+	}
+
+	hidden, err := b.related(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if hidden {
+		return []*models.Blog{}, nil
 	}
 
-	return b.blogStore.GetAll(ctx, filter, page)
+	blogs, err := b.blogStore.GetAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered(blogs...)
+	noticed(blogs...)
+
+	return blogs, nil
 }
 
-// GetAllByTagName retrieves all blogs by tag input.
+// validateFilter checks the tags and sort order of a listing.
+func validateFilter(filter *models.BlogFilter) error {
+	if len(filter.Tags) > maxFilterTags {
+		return errors.InvalidParam{Param: "tags"}
+	}
+
+	for _, tag := range filter.Tags {
+		if !tagPattern.MatchString(tag) {
+			return errors.InvalidParam{Param: "tags"}
+		}
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return errors.InvalidParam{Param: "date range"}
+	}
+
+	if _, ok := transitions[filter.Status]; !ok {
+		return errors.InvalidParam{Param: "status"}
+	}
+
+	switch filter.Sort {
+	case "", models.SortNewest, models.SortOldest:
+	default:
+		return errors.InvalidParam{Param: "sort"}
+	}
+
+	return nil
+}
+
+// GetAllByTagName retrieves all blogs by tag input, listed by the same rules as GetAll.
 func (b blog) GetAllByTagName(ctx *app.Context, name string, page *models.Page) ([]*models.Blog, error) {
 	tag, err := b.tagService.Get(ctx, fmt.Sprintf("#%v", name))
 	if err != nil {
 		return nil, err
 	}
 
-	return b.blogStore.GetByIDs(ctx, tag.BlogIDList, page)
+	filter := &models.BlogFilter{IDs: tag.BlogIDList, Viewer: viewerID(ctx)}
+	if filter.IDs == nil {
+		filter.IDs = []string{}
+	}
+
+	hidden, err := b.related(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if hidden {
+		return []*models.Blog{}, nil
+	}
+
+	blogs, err := b.blogStore.GetAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered(blogs...)
+	noticed(blogs...)
+
+	return blogs, nil
 }
 
 // GetByID is used to retrieve a single blog by its id.
@@ -63,11 +214,29 @@ func (b blog) GetByID(ctx *app.Context, id string) (*models.Blog, error) {
 		return nil, errors.EntityNotFound{Entity: "blog", ID: id}
 	}
 
-	return blog, err
+	if err != nil {
+		return nil, err
+	}
+
+	// blogs that are not visible do not exist for the viewer
+	visible, err := b.visible(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	if !visible {
+		return nil, errors.EntityNotFound{Entity: "blog", ID: id}
+	}
+
+	rendered(blog)
+	noticed(blog)
+
+	return blog, nil
 }
 
 // Create is used to create a Blog.
 // Missing params check for fields should be done on the frontend as well.
+// Images of confirmed uploads are attached after the uploaded files.
 func (b blog) Create(ctx *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error) {
 	jwtIDKey := auth.JWTContextKey("claims")
 	model.AccountID = ctx.Value(jwtIDKey).(*auth.Claims).UserID
@@ -81,41 +250,95 @@ func (b blog) Create(ctx *app.Context, model *models.Blog, images []*multipart.F
 
 	model.CreatedOn = time.Now()
 
-	ctx.Debugf("images to be uploaded: %v", len(images))
+	if err = setStatus(model, nil, model.CreatedOn); err != nil {
+		return nil, err
+	}
 
-	n := len(images)
-	errs := make(chan error, n)
+	if err = setVisibility(model, nil); err != nil {
+		return nil, err
+	}
+
+	if err = setFormat(model, nil); err != nil {
+		return nil, err
+	}
 
-	for _, img := range images {
-		name := fmt.Sprintf("%v_%v%v", model.AccountID, generateNewID(), filepath.Ext(img.Filename))
+	match, err := b.screen(model)
+	if err != nil {
+		return nil, err
+	}
 
-		go func() {
-			errs <- b.imageStore.Upload(ctx, img, name)
-		}()
+	render(model)
 
-		model.Images = append(model.Images, fmt.Sprintf("https://%v.s3.ap-south-1.amazonaws.com/%s", ctx.Config.Get("AWS_BUCKET"), name))
+	if err = b.setSlug(ctx, model, nil); err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < n; i++ {
-		//nolint:govet // redeclare error
-		if err := <-errs; err != nil {
-			return nil, err
-		}
+	ctx.Debugf("images to be uploaded: %v", len(images))
+
+	uploads, err := b.checkImages(ctx, model.AccountID, images)
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := b.blogStore.Create(ctx, model)
+	attached, err := b.attachUploads(ctx, model.AccountID, model.Uploads)
 	if err != nil {
 		return nil, err
 	}
 
-	b.tagService.AddBlogID(ctx, res.BlogID, model.Tags)
+	if count, limit := len(uploads)+len(attached), imaging.NewLimits(ctx.Config).MaxImages; count > limit {
+		b.releaseUploads(ctx, attached)
+		return nil, errors.TooManyImages{Count: count, Limit: limit}
+	}
+
+	// from here on, a failure undoes what is written
+	undo := &rollback{}
+	undo.add("attaching uploads", b.releaseUploadsStep(attached))
+
+	stored, err := b.storeImages(ctx, uploads)
+	if err != nil {
+		return nil, undo.run(ctx, "create", model.BlogID, err)
+	}
+
+	undo.add("storing images", b.releaseImagesStep(stored))
+
+	model.Images = append(stored, uploadedImages(attached)...)
+
+	res, err := b.write(ctx, model, nil, b.blogStore.Create)
+	if err != nil {
+		return nil, undo.run(ctx, "create", model.BlogID, err)
+	}
+
+	undo.add("writing blog", func(c *app.Context) error {
+		return b.blogStore.Delete(c, res.BlogID)
+	})
+
+	// tags are attached when the blog is listed to everyone
+	if res.IsListed() {
+		undo.add("tagging blog", func(c *app.Context) error {
+			return b.tagService.RemoveBlogID(c, res.BlogID, model.Tags)
+		})
+
+		if err = b.tagService.AddBlogID(ctx, res.BlogID, model.Tags); err != nil {
+			return nil, undo.run(ctx, "create", model.BlogID, err)
+		}
+	}
+
+	if err = b.revise(ctx, nil, res); err != nil {
+		return nil, undo.run(ctx, "create", model.BlogID, err)
+	}
+
+	b.index(ctx, res)
+
+	b.flag(ctx, res, match)
 
 	return res, nil
 }
 
 // Update updates a blog based on its id.
-// Images can only be added, not deleted.
-// Image array will be empty if no new images are added
+// Images of the model are the final order of the images, listing images by their ID and "upload:<n>"
+// for the n-th uploaded image, counting the images of confirmed uploads after the uploaded files.
+// Uploads left out of the order are appended, and existing images left out of it are deleted from the storage.
+// Without an order (nil), existing images are kept and uploads appended.
 // Tags will be overwritten
 func (b blog) Update(ctx *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error) {
 	jwtIDKey := auth.JWTContextKey("claims")
@@ -131,44 +354,427 @@ func (b blog) Update(ctx *app.Context, model *models.Blog, images []*multipart.F
 		return nil, err
 	}
 
-	// check if the blog exists already
-	blog, err := b.GetByID(ctx, id)
+	// check if the blog exists already, and is written by the logged in user
+	blog, err := b.getAsAuthor(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	n := len(images)
-	errs := make(chan error, n)
+	if err = setStatus(model, blog, time.Now()); err != nil {
+		return nil, err
+	}
 
-	// upload new images to s3 bucket
-	for _, img := range images {
-		name := fmt.Sprintf("%v_%v%v", model.AccountID, generateNewID(), filepath.Ext(img.Filename))
+	if err = setVisibility(model, blog); err != nil {
+		return nil, err
+	}
 
-		go func() {
-			errs <- b.imageStore.Upload(ctx, img, name)
-		}()
+	if err = setFormat(model, blog); err != nil {
+		return nil, err
+	}
 
-		model.Images = append(model.Images, fmt.Sprintf("https://%v.s3.ap-south-1.amazonaws.com/%s", ctx.Config.Get("AWS_BUCKET"), name))
+	match, err := b.screen(model)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < n; i++ {
-		if err := <-errs; err != nil {
-			return nil, err
-		}
+	render(model)
+
+	if err = b.setSlug(ctx, model, blog); err != nil {
+		return nil, err
 	}
 
-	// update blog
-	res, err := b.blogStore.Update(ctx, model)
+	uploads, err := b.checkImages(ctx, model.AccountID, images)
 	if err != nil {
 		return nil, err
 	}
 
-	b.updateTags(ctx, model, blog)
+	attached, err := b.attachUploads(ctx, model.AccountID, model.Uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is validated before anything is uploaded
+	added := append(placeholders(uploads), uploadedImages(attached)...)
+
+	model.Images, err = arrangeImages(model.Images, blog.Images, added)
+	if err != nil {
+		b.releaseUploads(ctx, attached)
+		return nil, err
+	}
+
+	// a blog that is over the limit already can still have its images reordered or removed
+	if limit := imaging.NewLimits(ctx.Config).MaxImages; len(added) != 0 && len(model.Images) > limit {
+		b.releaseUploads(ctx, attached)
+		return nil, errors.TooManyImages{Count: len(model.Images), Limit: limit}
+	}
+
+	// from here on, a failure undoes what is written
+	undo := &rollback{}
+	undo.add("attaching uploads", b.releaseUploadsStep(attached))
+
+	// upload new images to the storage
+	stored, err := b.storeImages(ctx, uploads)
+	if err != nil {
+		return nil, undo.run(ctx, "update", id, err)
+	}
+
+	undo.add("storing images", b.releaseImagesStep(stored))
+
+	model.Images = withRenditions(model.Images, stored)
+
+	// update blog, with its images in their final order
+	res, err := b.write(ctx, model, blog, b.blogStore.Update)
+	if err != nil {
+		return nil, undo.run(ctx, "update", id, err)
+	}
+
+	undo.add("updating blog", func(c *app.Context) error {
+		return b.blogStore.Revert(c, previous(blog))
+	})
+
+	if err = b.retag(ctx, undo, blog, res); err != nil {
+		return nil, undo.run(ctx, "update", id, err)
+	}
+
+	if err = b.revise(ctx, blog, res); err != nil {
+		return nil, undo.run(ctx, "update", id, err)
+	}
+
+	// the removed images cannot be brought back, so they are released once nothing is to be undone.
+	// The blog no longer refers to them, a failure leaves them orphaned in the storage.
+	if removed := removedImages(blog.Images, model.Images); len(removed) != 0 {
+		if err := b.releaseImages(ctx, removed); err != nil {
+			ctx.Logger.Errorf("cannot delete removed images of blog %s: %s", id, err.Error())
+		}
+	}
+
+	b.index(ctx, res)
+
+	b.flag(ctx, res, match)
 
 	return res, nil
 }
 
-func (b blog) updateTags(ctx *app.Context, new, old *models.Blog) {
+// retag moves the tags of an updated blog from its old version to the new one, registering the step that moves them back.
+// Tags are attached while the blog is listed to everyone.
+func (b blog) retag(ctx *app.Context, undo *rollback, old, res *models.Blog) error {
+	switch {
+	case old.IsListed() && res.IsListed():
+		undo.add("retagging blog", func(c *app.Context) error {
+			return b.updateTags(c, old, res)
+		})
+
+		return b.updateTags(ctx, res, old)
+	case res.IsListed():
+		undo.add("tagging blog", func(c *app.Context) error {
+			return b.tagService.RemoveBlogID(c, res.BlogID, res.Tags)
+		})
+
+		return b.tagService.AddBlogID(ctx, res.BlogID, res.Tags)
+	case old.IsListed():
+		undo.add("untagging blog", func(c *app.Context) error {
+			return b.tagService.AddBlogID(c, old.BlogID, old.Tags)
+		})
+
+		return b.tagService.RemoveBlogID(ctx, old.BlogID, old.Tags)
+	}
+
+	return nil
+}
+
+// previous returns the version of a blog to write back over a failed update.
+// Its images are never nil, so that images added by the update are dropped even if the blog had none.
+// It is written back by Revert, which unsets the fields that are empty in it.
+func previous(blog *models.Blog) *models.Blog {
+	model := *blog
+	model.Images = append([]models.Image{}, blog.Images...)
+
+	return &model
+}
+
+// GetRevisions retrieves the revision history of a blog, latest first.
+func (b blog) GetRevisions(ctx *app.Context, id string, page *models.Page) ([]*models.Revision, error) {
+	if _, err := b.getAsAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return b.revisionStore.GetAll(ctx, id, page)
+}
+
+// Restore brings back the text and tags of a revision through an update, which records a revision of its own.
+// Revisions recorded before the format existed are plain text.
+func (b blog) Restore(ctx *app.Context, id string, number int64) (*models.Blog, error) {
+	if _, err := b.getAsAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rev, err := b.revisionStore.Get(ctx, id, number)
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		return nil, errors.EntityNotFound{Entity: "revision", ID: strconv.FormatInt(number, 10)}
+	default:
+		return nil, err
+	}
+
+	model := &models.Blog{BlogID: id, Title: rev.Title, Summary: rev.Summary, Content: rev.Content, ContentFormat: rev.ContentFormat, Tags: rev.Tags}
+	if model.ContentFormat == "" {
+		model.ContentFormat = models.FormatPlain
+	}
+
+	return b.Update(ctx, model, nil)
+}
+
+// getAsAuthor retrieves a blog for its author, a blog is changed, deleted or restored, and its history read, by its author alone.
+func (b blog) getAsAuthor(ctx *app.Context, id string) (*models.Blog, error) {
+	blog, err := b.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if blog.AccountID != viewerID(ctx) {
+		return nil, errors.AuthError{Msg: "a blog is open to its author alone"}
+	}
+
+	return blog, nil
+}
+
+// revise records a revision of a blog, old being the version before the update or nil for a new blog.
+// A blog written before revisions were recorded gets its previous version recorded as the first revision.
+// A number taken by a concurrent update is retried with the next one, any other failure fails the write.
+func (b blog) revise(ctx *app.Context, old, model *models.Blog) error {
+	changes := []string{"created"}
+
+	if old != nil {
+		changes = diff(old, model)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := b.record(ctx, old, model, changes)
+		if _, ok := err.(errors.EntityAlreadyExists); !ok || attempt == reviseAttempts {
+			return err
+		}
+	}
+}
+
+// record records a revision of a blog after its latest one.
+func (b blog) record(ctx *app.Context, old, model *models.Blog, changes []string) error {
+	number := int64(1)
+
+	latest, err := b.revisionStore.GetLatest(ctx, model.BlogID)
+
+	switch err.(type) {
+	case nil:
+		number = latest.Number + 1
+	case errors.EntityNotFound:
+		if old != nil {
+			if err = b.revisionStore.Create(ctx, newRevision(old, 1, old.AccountID, old.CreatedOn, []string{"created"})); err != nil {
+				return err
+			}
+
+			number = 2
+		}
+	default:
+		return err
+	}
+
+	return b.revisionStore.Create(ctx, newRevision(model, number, viewerID(ctx), time.Now(), changes))
+}
+
+func newRevision(model *models.Blog, number, accountID int64, createdOn time.Time, changes []string) *models.Revision {
+	return &models.Revision{
+		BlogID:        model.BlogID,
+		Number:        number,
+		AccountID:     accountID,
+		CreatedOn:     createdOn,
+		Title:         model.Title,
+		Summary:       model.Summary,
+		Content:       model.Content,
+		ContentFormat: model.Format(),
+		Tags:          model.Tags,
+		Changes:       changes,
+	}
+}
+
+// diff summarises the changes between two versions of a blog.
+func diff(old, model *models.Blog) []string {
+	changes := make([]string, 0)
+
+	if old.Title != model.Title {
+		changes = append(changes, "title changed")
+	}
+
+	if old.Summary != model.Summary {
+		changes = append(changes, "summary changed")
+	}
+
+	if old.Content != model.Content {
+		delta := utf8.RuneCountInString(model.Content) - utf8.RuneCountInString(old.Content)
+		changes = append(changes, fmt.Sprintf("content changed (%+d characters)", delta))
+	}
+
+	if old.Format() != model.Format() {
+		changes = append(changes, "content format changed to "+model.Format())
+	}
+
+	added, removed := difference(model.Tags, old.Tags), difference(old.Tags, model.Tags)
+
+	if len(added) != 0 {
+		changes = append(changes, "tags added: "+strings.Join(added, ", "))
+	}
+
+	if len(removed) != 0 {
+		changes = append(changes, "tags removed: "+strings.Join(removed, ", "))
+	}
+
+	oldImages, newImages := imageIDs(old.Images), imageIDs(model.Images)
+	addedImages, removedImages := difference(newImages, oldImages), difference(oldImages, newImages)
+
+	if len(addedImages) != 0 {
+		changes = append(changes, fmt.Sprintf("images added: %v", len(addedImages)))
+	}
+
+	if len(removedImages) != 0 {
+		changes = append(changes, fmt.Sprintf("images removed: %v", len(removedImages)))
+	}
+
+	if len(addedImages) == 0 && len(removedImages) == 0 && strings.Join(oldImages, ",") != strings.Join(newImages, ",") {
+		changes = append(changes, "images reordered")
+	}
+
+	if old.IsPublished() != model.IsPublished() || (old.Status != model.Status && old.Status != "") {
+		changes = append(changes, fmt.Sprintf("status changed to %s", model.Status))
+	}
+
+	if old.IsPublic() != model.IsPublic() || (old.Visibility != model.Visibility && old.Visibility != "") {
+		changes = append(changes, fmt.Sprintf("visibility changed to %s", model.Visibility))
+	}
+
+	return changes
+}
+
+// difference returns the items of a that are not in b, in the order of a.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, item := range b {
+		in[item] = true
+	}
+
+	res := make([]string, 0)
+
+	for _, item := range a {
+		if !in[item] {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+// PublishScheduled publishes the scheduled blogs that are due, and attaches the tags of those listed to everyone.
+// It is run periodically by the background publisher.
+func (b blog) PublishScheduled(ctx *app.Context) error {
+	due, err := b.blogStore.GetScheduled(ctx, time.Now(), publishBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, blog := range due {
+		res, err := b.blogStore.Publish(ctx, blog.BlogID, time.Now())
+
+		switch err.(type) {
+		case nil:
+		case errors.EntityNotFound:
+			continue // published by another publisher, or no longer scheduled
+		default:
+			return err
+		}
+
+		// the blog is published already, a tag that fails is logged by the tag service
+		if res.IsListed() {
+			_ = b.tagService.AddBlogID(ctx, res.BlogID, res.Tags)
+		}
+
+		b.index(ctx, res)
+
+		ctx.Logger.Infof("published scheduled blog %s", res.BlogID)
+	}
+
+	return nil
+}
+
+// setStatus validates the status of a new or updated blog against its current status.
+// A blog without a status is published, unless it has a publishing time. An unchanged status is allowed.
+// The creation time of a blog that gets published for the first time, new or from a draft or a schedule, is reset to the publishing time.
+// An archived blog published again keeps its creation time, and its place in the listings.
+func setStatus(model, old *models.Blog, now time.Time) error {
+	var from string
+
+	if old != nil {
+		from = old.Status
+		if from == "" {
+			from = models.StatusPublished
+		}
+	}
+
+	if model.Status == "" {
+		switch {
+		case model.PublishAt != nil:
+			model.Status = models.StatusScheduled
+		case old != nil:
+			model.Status = from
+		default:
+			model.Status = models.StatusPublished
+		}
+	}
+
+	if model.Status != from && !allowed(from, model.Status) {
+		return errors.InvalidParam{Param: "status"}
+	}
+
+	if model.Status == models.StatusScheduled {
+		if model.PublishAt == nil && old != nil {
+			model.PublishAt = old.PublishAt
+		}
+
+		if model.PublishAt == nil || !model.PublishAt.After(now) {
+			return errors.InvalidParam{Param: "publish_at"}
+		}
+	} else {
+		model.PublishAt = nil
+	}
+
+	if model.Status == models.StatusPublished && from != models.StatusPublished && from != models.StatusArchived {
+		model.CreatedOn = now
+	}
+
+	return nil
+}
+
+func allowed(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// viewerID returns the account id of the logged in user, or 0 if the request is anonymous.
+func viewerID(ctx *app.Context) int64 {
+	claims, ok := ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims)
+	if !ok {
+		return 0
+	}
+
+	return claims.UserID
+}
+
+// updateTags moves a blog from the tags of its old version to those of its new version.
+// Both tag changes are tried, and the first failure is returned.
+func (b blog) updateTags(ctx *app.Context, new, old *models.Blog) error {
 	var addTags, removeTags []string
 
 	switch {
@@ -183,7 +789,7 @@ func (b blog) updateTags(ctx *app.Context, new, old *models.Blog) {
 			m[tag] += 1
 		}
 
-		for _, tag := range new.Tags {
+		for _, tag := range old.Tags {
 			m[tag] -= 1
 		}
 
@@ -198,9 +804,13 @@ func (b blog) updateTags(ctx *app.Context, new, old *models.Blog) {
 		}
 	}
 
-	b.tagService.RemoveBlogID(ctx, old.BlogID, removeTags)
+	removeErr := b.tagService.RemoveBlogID(ctx, old.BlogID, removeTags)
 
-	b.tagService.AddBlogID(ctx, old.BlogID, addTags)
+	if err := b.tagService.AddBlogID(ctx, old.BlogID, addTags); err != nil && removeErr == nil {
+		return err
+	}
+
+	return removeErr
 }
 
 // Delete deletes a blog based on its id.
@@ -209,9 +819,9 @@ func (b blog) Delete(ctx *app.Context, id string) error {
 		return errors.MissingParam{Param: "blog_id"}
 	}
 
-	blog, err := b.blogStore.Get(ctx, &models.Blog{BlogID: id})
+	blog, err := b.getAsAuthor(ctx, id)
 	if err != nil {
-		return errors.EntityNotFound{Entity: "blog", ID: id}
+		return err
 	}
 
 	err = b.blogStore.Delete(ctx, id)
@@ -219,29 +829,39 @@ func (b blog) Delete(ctx *app.Context, id string) error {
 		return err
 	}
 
-	names := getNames(blog.Images)
-
-	err = b.imageStore.DeleteBulk(ctx, names)
+	// the files of the images are deleted once no other image shows them
+	err = b.releaseImages(ctx, blog.Images)
 	if err != nil {
 		return err
 	}
 
-	b.tagService.RemoveBlogID(ctx, id, blog.Tags)
+	// the blog is deleted already, a tag that fails is logged by the tag service
+	if blog.IsListed() {
+		_ = b.tagService.RemoveBlogID(ctx, id, blog.Tags)
+	}
+
+	if err := b.searchIndex.Remove(ctx, id); err != nil {
+		ctx.Logger.Errorf("cannot remove blog %s from search index: %s", id, err.Error())
+	}
 
 	return nil
 }
 
-func getNames(images []string) []string {
-	names := make([]string, 0)
-
-	for _, img := range images {
-		s := strings.Split(img, "/")
-		name := s[len(s)-1]
+// index updates the search index with the latest version of a blog. Search lists blogs to everyone,
+// so only the blogs that are published, public and not hidden by a moderator are kept in it.
+// A failure is logged and does not fail the write, the blog is already persisted.
+func (b blog) index(ctx *app.Context, model *models.Blog) {
+	if !model.IsListed() {
+		if err := b.searchIndex.Remove(ctx, model.BlogID); err != nil {
+			ctx.Logger.Errorf("cannot remove blog %s from search index: %s", model.BlogID, err.Error())
+		}
 
-		names = append(names, name)
+		return
 	}
 
-	return names
+	if err := b.searchIndex.Index(ctx, model); err != nil {
+		ctx.Logger.Errorf("cannot index blog %s: %s", model.BlogID, err.Error())
+	}
 }
 
 func generateNewID() string {
@@ -272,5 +892,9 @@ func checkMissingParams(model *models.Blog) error {
 		return errors.MissingParam{Param: "content"}
 	}
 
+	if utf8.RuneCountInString(model.Content) > maxContentLength {
+		return errors.InvalidParam{Param: "content"}
+	}
+
 	return nil
 }
@@ -1,6 +1,16 @@
 package blog
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,8 +19,10 @@ import (
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/markup"
 	"github.com/Aakanksha-jais/picshot-golang-backend/services"
 	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
 )
@@ -21,9 +33,10 @@ func initializeTest(t *testing.T) (*stores.MockBlog, *services.MockTag, *stores.
 	mockBlogStore := stores.NewMockBlog(ctrl)
 	mockImageStore := stores.NewMockImage(ctrl)
 	mockTagService := services.NewMockTag(ctrl)
-	mockBlogService := New(mockBlogStore, mockTagService, mockImageStore)
+	mockBlogService := New(mockBlogStore, mockTagService, mockImageStore, stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
 
 	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
 
 	return mockBlogStore, mockTagService, mockImageStore, ctx, mockBlogService
 }
@@ -31,19 +44,19 @@ func initializeTest(t *testing.T) (*stores.MockBlog, *services.MockTag, *stores.
 func TestBlog_GetAll(t *testing.T) {
 	mockBlogStore, _, _, ctx, mockBlogService := initializeTest(t)
 
-	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.Blog{}, &models.Page{Limit: 3, PageNo: 1}).Return(getAllOutput(), nil).AnyTimes()
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{}, &models.Page{Limit: 3, PageNo: 1}).Return(getAllOutput(), nil).AnyTimes()
 
-	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.Blog{}, &models.Page{Limit: 2, PageNo: 1}).Return(nil, errors.DBError{})
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{}, &models.Page{Limit: 2, PageNo: 1}).Return(nil, errors.DBError{})
 
 	tests := []struct {
 		description string
-		input       *models.Blog
+		input       *models.BlogFilter
 		page        *models.Page
 		output      []*models.Blog
 		err         error
 	}{
-		{description: "get all with empty filter", input: &models.Blog{}, output: getAllOutput(), page: &models.Page{Limit: 3, PageNo: 1}},
-		{description: "get all with nil filter", input: nil, output: getAllOutput(), page: &models.Page{Limit: 3, PageNo: 1}},
+		{description: "get all with empty filter", input: &models.BlogFilter{}, output: renderedOutput(), page: &models.Page{Limit: 3, PageNo: 1}},
+		{description: "get all with nil filter", input: nil, output: renderedOutput(), page: &models.Page{Limit: 3, PageNo: 1}},
 		{description: "database error", input: nil, page: &models.Page{Limit: 2, PageNo: 1}, err: errors.DBError{}},
 	}
 
@@ -60,18 +73,71 @@ func TestBlog_GetAll(t *testing.T) {
 	}
 }
 
+func TestBlog_GetAllFiltered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockAccountStore := stores.NewMockAccount(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), mockAccountStore, stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+	page := &models.Page{Limit: 3, PageNo: 1}
+
+	mockAccountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "jaiss"}}).Return(&models.Account{User: models.User{ID: 2, UserName: "jaiss"}}, nil)
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{Blog: models.Blog{AccountID: 2}, Author: "jaiss", Tags: []string{"#music"}, Sort: models.SortOldest}, page).Return(getAllOutput(), nil)
+
+	mockAccountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "nobody"}}).Return(nil, errors.EntityNotFound{Entity: "account"})
+	mockAccountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "broken"}}).Return(nil, errors.DBError{})
+
+	tooManyTags := make([]string, maxFilterTags+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = fmt.Sprintf("#tag%v", i)
+	}
+
+	tests := []struct {
+		description string
+		input       *models.BlogFilter
+		page        *models.Page
+		output      []*models.Blog
+		err         error
+	}{
+		{description: "filter by author and tag", input: &models.BlogFilter{Author: "jaiss", Tags: []string{"#music"}, Sort: models.SortOldest}, page: page, output: renderedOutput()},
+		{description: "unknown author", input: &models.BlogFilter{Author: "nobody"}, page: page, output: []*models.Blog{}},
+		{description: "error in fetching author", input: &models.BlogFilter{Author: "broken"}, page: page, err: errors.DBError{}},
+		{description: "invalid tag", input: &models.BlogFilter{Tags: []string{"#mu$ic"}}, page: page, err: errors.InvalidParam{Param: "tags"}},
+		{description: "too many tags", input: &models.BlogFilter{Tags: tooManyTags}, page: page, err: errors.InvalidParam{Param: "tags"}},
+		{description: "invalid sort", input: &models.BlogFilter{Sort: "random"}, page: page, err: errors.InvalidParam{Param: "sort"}},
+		{description: "empty date range", input: &models.BlogFilter{CreatedAfter: getTime("2021-05-23T15:04:05Z"), CreatedBefore: getTime("2021-05-22T15:04:05Z")}, page: page, err: errors.InvalidParam{Param: "date range"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetAll(ctx, tc.input, tc.page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
 func TestBlog_GetAllByTagName(t *testing.T) {
 	mockBlogStore, mockTagService, _, ctx, mockBlogService := initializeTest(t)
 
 	page := &models.Page{Limit: 3, PageNo: 1}
 
-	mockTagService.EXPECT().Get(gomock.Any(), "#tag1").Return(&models.Tag{Name: "#tag1", BlogIDList: []string{"MSI8WKNSH9", "9SNVSH8K2M", "ABK7SH2V37"}}, nil)
-	mockBlogStore.EXPECT().GetByIDs(gomock.Any(), []string{"MSI8WKNSH9", "9SNVSH8K2M", "ABK7SH2V37"}, page).Return(getAllOutput(), nil)
+	ids := []string{"MSI8WKNSH9", "9SNVSH8K2M", "ABK7SH2V37"}
+
+	// blogs of a tag are listed by the same visibility rules as any listing
+	mockTagService.EXPECT().Get(gomock.Any(), "#tag1").Return(&models.Tag{Name: "#tag1", BlogIDList: ids}, nil)
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{IDs: ids}, page).Return(getAllOutput(), nil)
 
 	mockTagService.EXPECT().Get(gomock.Any(), "#tag2").Return(nil, errors.DBError{})
 
-	mockTagService.EXPECT().Get(gomock.Any(), "#tag3").Return(&models.Tag{Name: "#tag3", BlogIDList: []string{"MSI8WKNSH9", "9SNVSH8K2M", "ABK7SH2V37"}}, nil)
-	mockBlogStore.EXPECT().GetByIDs(gomock.Any(), []string{"MSI8WKNSH9", "9SNVSH8K2M", "ABK7SH2V37"}, page).Return(nil, errors.DBError{})
+	mockTagService.EXPECT().Get(gomock.Any(), "#tag3").Return(&models.Tag{Name: "#tag3", BlogIDList: ids}, nil)
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{IDs: ids}, page).Return(nil, errors.DBError{})
+
+	mockTagService.EXPECT().Get(gomock.Any(), "#tag4").Return(&models.Tag{Name: "#tag4"}, nil)
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{IDs: []string{}}, page).Return([]*models.Blog{}, nil)
 
 	tests := []struct {
 		description string
@@ -79,9 +145,10 @@ func TestBlog_GetAllByTagName(t *testing.T) {
 		output      []*models.Blog
 		err         error
 	}{
-		{description: "success case", input: "tag1", output: getAllOutput(), err: nil},
+		{description: "success case", input: "tag1", output: renderedOutput(), err: nil},
 		{description: "db error in call to tagService.Get", input: "tag2", output: nil, err: errors.DBError{}},
-		{description: "db error in call to tagService.Get", input: "tag3", output: nil, err: errors.DBError{}},
+		{description: "db error in call to blogStore.GetAll", input: "tag3", output: nil, err: errors.DBError{}},
+		{description: "tag without blogs", input: "tag4", output: []*models.Blog{}, err: nil},
 	}
 
 	for i, tc := range tests {
@@ -108,24 +175,384 @@ func TestBlog_GetByID(t *testing.T) {
 		Content:   "avicii left :(",
 		Tags:      []string{},
 		CreatedOn: getTime("2021-05-23T15:04:05Z"),
-		Images:    []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"},
+		Images:    legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"),
 	}
 
+	draft := &models.Blog{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Status: models.StatusDraft}
+
 	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).
 		Return(res, nil)
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "9SNVSH8K2M"}).
+		Return(draft, nil).Times(2)
+
+	author := withViewer(ctx, 3)
 
 	tests := []struct {
 		description string
+		ctx         *app.Context
 		id          string
 		output      *models.Blog
 		err         error
 	}{
-		{id: "", output: nil, err: errors.MissingParam{Param: "blog_id"}},
-		{id: "MSI8WKNSH9", output: res, err: nil},
+		{ctx: ctx, id: "", output: nil, err: errors.MissingParam{Param: "blog_id"}},
+		{ctx: ctx, id: "MSI8WKNSH9", output: res, err: nil},
+		{description: "draft of another account", ctx: withViewer(ctx, 2), id: "9SNVSH8K2M", err: errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"}},
+		{description: "draft of the viewer", ctx: author, id: "9SNVSH8K2M", output: draft},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetByID(tc.ctx, tc.id)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBlog_PublishScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, stores.NewMockImage(ctrl), mockSearchIndex, stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+
+	publishAt := getTime("2021-05-23T15:04:05Z")
+	due := []*models.Blog{
+		{BlogID: "MSI8WKNSH9", Status: models.StatusScheduled, PublishAt: &publishAt, Tags: []string{"#music"}},
+		{BlogID: "9SNVSH8K2M", Status: models.StatusScheduled, PublishAt: &publishAt, Tags: []string{"#flowers"}},
+	}
+	published := &models.Blog{BlogID: "MSI8WKNSH9", Status: models.StatusPublished, PublishAt: &publishAt, Tags: []string{"#music"}}
+
+	mockBlogStore.EXPECT().GetScheduled(gomock.Any(), gomock.Any(), int64(publishBatchSize)).Return(due, nil)
+	mockBlogStore.EXPECT().Publish(gomock.Any(), "MSI8WKNSH9", gomock.Any()).Return(published, nil)
+	mockBlogStore.EXPECT().Publish(gomock.Any(), "9SNVSH8K2M", gomock.Any()).Return(nil, errors.EntityNotFound{Entity: "scheduled blog", ID: "9SNVSH8K2M"})
+
+	// tags of the blog are attached only once it is published
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"})
+	mockSearchIndex.EXPECT().Index(gomock.Any(), published).Return(nil)
+
+	err := mockBlogService.PublishScheduled(ctx)
+
+	assert.Equal(t, nil, err, "TEST, failed.\npublish scheduled blogs")
+
+	mockBlogStore.EXPECT().GetScheduled(gomock.Any(), gomock.Any(), int64(publishBatchSize)).Return(nil, errors.DBError{})
+
+	err = mockBlogService.PublishScheduled(ctx)
+
+	assert.Equal(t, errors.DBError{}, err, "TEST, failed.\ndatabase error")
+}
+
+//nolint:lll // test cases need to be readable
+func TestSetStatus(t *testing.T) {
+	now := getTime("2021-05-23T15:04:05Z")
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		old         *models.Blog
+		status      string
+		createdOn   time.Time
+		err         error
+	}{
+		{description: "new blog is published by default", model: &models.Blog{}, status: models.StatusPublished, createdOn: now},
+		{description: "new blog with a publishing time is scheduled", model: &models.Blog{PublishAt: &later}, status: models.StatusScheduled},
+		{description: "new draft", model: &models.Blog{Status: models.StatusDraft}, status: models.StatusDraft},
+		{description: "new blog cannot be archived", model: &models.Blog{Status: models.StatusArchived}, status: models.StatusArchived, err: errors.InvalidParam{Param: "status"}},
+		{description: "unknown status", model: &models.Blog{Status: "deleted"}, status: "deleted", err: errors.InvalidParam{Param: "status"}},
+		{description: "scheduled in the past", model: &models.Blog{PublishAt: &earlier}, status: models.StatusScheduled, err: errors.InvalidParam{Param: "publish_at"}},
+		{description: "unchanged status of a legacy blog", model: &models.Blog{}, old: &models.Blog{}, status: models.StatusPublished},
+		{description: "draft gets published", model: &models.Blog{Status: models.StatusPublished}, old: &models.Blog{Status: models.StatusDraft}, status: models.StatusPublished, createdOn: now},
+		{description: "rescheduled blog keeps its publishing time", model: &models.Blog{}, old: &models.Blog{Status: models.StatusScheduled, PublishAt: &later}, status: models.StatusScheduled},
+		{description: "published blog gets archived", model: &models.Blog{Status: models.StatusArchived}, old: &models.Blog{Status: models.StatusPublished}, status: models.StatusArchived},
+		{description: "archived blog published again keeps its creation time", model: &models.Blog{Status: models.StatusPublished}, old: &models.Blog{Status: models.StatusArchived}, status: models.StatusPublished},
+		{description: "scheduled blog gets published early", model: &models.Blog{Status: models.StatusPublished}, old: &models.Blog{Status: models.StatusScheduled, PublishAt: &later}, status: models.StatusPublished, createdOn: now},
+		{description: "published blog cannot become a draft", model: &models.Blog{Status: models.StatusDraft}, old: &models.Blog{}, status: models.StatusDraft, err: errors.InvalidParam{Param: "status"}},
+	}
+
+	for i, tc := range tests {
+		err := setStatus(tc.model, tc.old, now)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.status, tc.model.Status, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.createdOn, tc.model.CreatedOn, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_ChangeByAnotherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), mockBlockStore, stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+	ctx = withViewer(ctx, 3)
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(&models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Content: "avicii left :("}, nil).Times(2)
+	mockBlockStore.EXPECT().Exists(gomock.Any(), int64(3), int64(2)).Return(false, nil).AnyTimes()
+
+	// the blog is neither written nor deleted, as the mocks expect no write
+	output, err := mockBlogService.Update(ctx, &models.Blog{BlogID: "MSI8WKNSH9", Title: "stolen", Summary: "stolen", Content: "stolen"}, nil)
+
+	assert.Nil(t, output, "TEST [1], failed.\nupdate by another account")
+	assert.Equal(t, errors.AuthError{Msg: "a blog is open to its author alone"}, err, "TEST [1], failed.\nupdate by another account")
+
+	err = mockBlogService.Delete(ctx, "MSI8WKNSH9")
+
+	assert.Equal(t, errors.AuthError{Msg: "a blog is open to its author alone"}, err, "TEST [2], failed.\ndelete by another account")
+}
+
+func TestBlog_GetRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockRevisionStore := stores.NewMockRevision(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), mockRevisionStore, stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), mockBlockStore, stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+
+	page := &models.Page{Limit: 20, PageNo: 1}
+	revisions := []*models.Revision{{BlogID: "MSI8WKNSH9", Number: 2, Changes: []string{"title changed"}}, {BlogID: "MSI8WKNSH9", Number: 1, Changes: []string{"created"}}}
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(&models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2}, nil).Times(2)
+	mockBlockStore.EXPECT().Exists(gomock.Any(), int64(3), int64(2)).Return(false, nil).AnyTimes()
+	mockRevisionStore.EXPECT().GetAll(gomock.Any(), "MSI8WKNSH9", page).Return(revisions, nil)
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		output      []*models.Revision
+		err         error
+	}{
+		{description: "revisions for the author", ctx: withViewer(ctx, 2), output: revisions},
+		{description: "revisions for another account", ctx: withViewer(ctx, 3), err: errors.AuthError{Msg: "a blog is open to its author alone"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetRevisions(tc.ctx, "MSI8WKNSH9", page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockRevisionStore := stores.NewMockRevision(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, stores.NewMockImage(ctrl), mockSearchIndex, stores.NewMockAccount(ctrl), mockRevisionStore, stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), mockBlockStore, stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+	author := withViewer(ctx, 2)
+
+	current := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{"#music", "#edm"}, CreatedOn: getTime("2021-05-23T15:04:05Z")}
+	rev := &models.Revision{BlogID: "MSI8WKNSH9", Number: 1, AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, Changes: []string{"created"}}
+	restored := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Status: models.StatusPublished}
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(current, nil).AnyTimes()
+	mockBlockStore.EXPECT().Exists(gomock.Any(), int64(3), int64(2)).Return(false, nil).AnyTimes()
+	mockRevisionStore.EXPECT().Get(gomock.Any(), "MSI8WKNSH9", int64(1)).Return(rev, nil)
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "music").Return(map[string]string{}, nil)
+	mockRevisionStore.EXPECT().Get(gomock.Any(), "MSI8WKNSH9", int64(5)).Return(nil, errors.EntityNotFound{Entity: "revision"})
+
+	// the restore goes through the update path
+	mockBlogStore.EXPECT().Update(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii", ContentFormat: models.FormatPlain, ContentHTML: "<p>avicii</p>\n", RenderVersion: markup.Version, Slug: "music", OldSlugs: []string{}, Tags: []string{"#music"}, Images: []models.Image{}, Status: models.StatusPublished, Visibility: models.VisibilityPublic}).Return(restored, nil)
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#edm"})
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "MSI8WKNSH9", gomock.Nil())
+	mockSearchIndex.EXPECT().Index(gomock.Any(), restored).Return(nil)
+	mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "MSI8WKNSH9").Return(&models.Revision{BlogID: "MSI8WKNSH9", Number: 2}, nil)
+
+	var recorded *models.Revision
+
+	mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ *app.Context, r *models.Revision) { recorded = r }).Return(nil)
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		number      int64
+		output      *models.Blog
+		err         error
+	}{
+		{description: "restore a revision", ctx: author, number: 1, output: restored},
+		{description: "restore a missing revision", ctx: author, number: 5, err: errors.EntityNotFound{Entity: "revision", ID: "5"}},
+		{description: "restore by another account", ctx: withViewer(ctx, 3), number: 1, err: errors.AuthError{Msg: "a blog is open to its author alone"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.Restore(tc.ctx, "MSI8WKNSH9", tc.number)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+
+	if assert.NotNil(t, recorded, "TEST, failed.\nrevision of the restore") {
+		assert.Equal(t, int64(3), recorded.Number, "TEST, failed.\nrevision of the restore")
+		assert.Equal(t, []string{"content changed (-8 characters)", "tags removed: #edm"}, recorded.Changes, "TEST, failed.\nrevision of the restore")
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_Revise(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRevisionStore := stores.NewMockRevision(ctrl)
+	service := blog{revisionStore: mockRevisionStore}
+
+	_, _, _, ctx, _ := initializeTest(t)
+	author := withViewer(ctx, 2)
+
+	old := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog", Content: "childhood"}
+	model := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "days", Summary: "a blog", Content: "childhood"}
+	numbers := make([]int64, 0)
+	record := func(_ *app.Context, r *models.Revision) { numbers = append(numbers, r.Number) }
+
+	calls := []*gomock.Call{
+		// a number taken by a concurrent update is retried with the next one
+		mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(&models.Revision{Number: 2}, nil),
+		mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(errors.EntityAlreadyExists{Entity: "revision"}),
+		mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(&models.Revision{Number: 3}, nil),
+		mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(nil),
+
+		// the version before the first recorded update is recorded first
+		mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(nil, errors.EntityNotFound{Entity: "revision"}),
+		mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(nil),
+		mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(nil),
+	}
+
+	// the retries run out
+	for i := 0; i < reviseAttempts; i++ {
+		calls = append(calls,
+			mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(&models.Revision{Number: 4}, nil),
+			mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(errors.EntityAlreadyExists{Entity: "revision"}),
+		)
+	}
+
+	calls = append(calls,
+		mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(nil, errors.DBError{}),
+		mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(&models.Revision{Number: 4}, nil),
+		mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Do(record).Return(errors.DBError{}),
+	)
+
+	gomock.InOrder(calls...)
+
+	tests := []struct {
+		description string
+		numbers     []int64
+		err         error
+	}{
+		{description: "number taken by a concurrent update", numbers: []int64{3, 4}},
+		{description: "first recorded update", numbers: []int64{1, 2}},
+		{description: "numbers taken on every attempt", numbers: []int64{5, 5, 5}, err: errors.EntityAlreadyExists{Entity: "revision"}},
+		{description: "revisions that cannot be read", numbers: []int64{}, err: errors.DBError{}},
+		{description: "revision that cannot be recorded", numbers: []int64{5}, err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		numbers = numbers[:0]
+
+		err := service.revise(author, old, model)
+
+		assert.Equal(t, tc.numbers, numbers, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_UpdateImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockRevisionStore := stores.NewMockRevision(ctrl)
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, mockImageStore, mockSearchIndex, stores.NewMockAccount(ctrl), mockRevisionStore, stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+	author := withViewer(ctx, 2)
+
+	images := legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/park.jpg")
+	current := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Images: images}
+	updated := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", ContentFormat: models.FormatPlain, ContentHTML: "<p>the best of childhood days!</p>\n", RenderVersion: markup.Version, Slug: "memories", OldSlugs: []string{}, Images: []models.Image{images[2], images[0]}, Status: models.StatusPublished, Visibility: models.VisibilityPublic}
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "9SH7SH2V37"}).Return(current, nil).Times(2)
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "memories").Return(map[string]string{}, nil).Times(2)
+
+	// images are reordered in the same write, and the removed image is deleted from the storage
+	mockBlogStore.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"kid.jpg"}).Return(errors.DBError{})
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "9SH7SH2V37", gomock.Nil())
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "9SH7SH2V37", gomock.Nil())
+	mockSearchIndex.EXPECT().Index(gomock.Any(), updated).Return(nil)
+	mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(&models.Revision{Number: 1}, nil)
+	mockRevisionStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	tests := []struct {
+		description string
+		images      []models.Image
+		output      *models.Blog
+		err         error
+	}{
+		{description: "remove and reorder images", images: []models.Image{{ID: "park.jpg"}, {ID: "girl.jpeg"}}, output: updated},
+		{description: "image that is not part of the blog", images: []models.Image{{ID: "a.jpg"}}, err: errors.InvalidParam{Param: "images"}},
+	}
+
+	for i, tc := range tests {
+		model := &models.Blog{BlogID: "9SH7SH2V37", Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Images: tc.images}
+
+		output, err := mockBlogService.Update(author, model, nil)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestArrangeImages(t *testing.T) {
+	a, b, c, d := models.Image{ID: "a"}, models.Image{ID: "b"}, models.Image{ID: "c"}, models.Image{ID: "d"}
+	existing := []models.Image{a, b}
+	uploaded := []models.Image{c, d}
+
+	tests := []struct {
+		description string
+		order       []models.Image
+		output      []models.Image
+		err         error
+	}{
+		{description: "no order keeps the existing images", order: nil, output: []models.Image{a, b, c, d}},
+		{description: "empty order removes the existing images", order: []models.Image{}, output: []models.Image{c, d}},
+		{description: "uploads placed within the order", order: []models.Image{{ID: "upload:1"}, {ID: "b"}, {ID: "upload:0"}}, output: []models.Image{d, b, c}},
+		{description: "uploads left out of the order are appended", order: []models.Image{{ID: "b"}, {ID: "a"}}, output: []models.Image{b, a, c, d}},
+		{description: "unknown image", order: []models.Image{{ID: "e"}}, err: errors.InvalidParam{Param: "images"}},
+		{description: "upload out of range", order: []models.Image{{ID: "upload:2"}}, err: errors.InvalidParam{Param: "images"}},
+		{description: "image listed twice", order: []models.Image{{ID: "a"}, {ID: "a"}}, err: errors.InvalidParam{Param: "images"}},
 	}
 
 	for i, tc := range tests {
-		output, err := mockBlogService.GetByID(ctx, tc.id)
+		output, err := arrangeImages(tc.order, existing, uploaded)
 
 		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
 
@@ -133,6 +560,327 @@ func TestBlog_GetByID(t *testing.T) {
 	}
 }
 
+func TestBlog_CheckImages(t *testing.T) {
+	_, _, mockImageStore, ctx, _ := initializeTest(t)
+	service := blog{imageStore: mockImageStore}
+
+	tests := []struct {
+		description string
+		files       []*multipart.FileHeader
+		err         error
+	}{
+		{description: "too many images", files: make([]*multipart.FileHeader, 11), err: errors.TooManyImages{Count: 11, Limit: 10}},
+		{description: "image too large", files: []*multipart.FileHeader{{Filename: "a.png", Size: 11 << 20}}, err: errors.ImageTooLarge{Name: "a.png", Size: 11 << 20, Limit: 10 << 20}},
+		{description: "upload too large", files: []*multipart.FileHeader{{Filename: "a.png", Size: 9 << 20}, {Filename: "b.png", Size: 9 << 20}, {Filename: "c.png", Size: 9 << 20}, {Filename: "d.png", Size: 9 << 20}}, err: errors.UploadTooLarge{Limit: 32 << 20}},
+		{description: "file that is not a picture", files: fileHeaders(t, "a.txt", []byte("hello world")), err: errors.UnsupportedImage{Name: "a.txt", Format: "text/plain; charset=utf-8"}},
+		{description: "picture named as another format", files: fileHeaders(t, "a.png", []byte("GIF89a")), err: errors.UnsupportedImage{Name: "a.png"}},
+		{description: "picture too wide", files: fileHeaders(t, "wide.png", encodePNG(t, 9000, 1)), err: errors.ImageDimensions{Name: "wide.png", Width: 9000, Height: 1}},
+		{description: "picture with too many pixels", files: fileHeaders(t, "big.png", encodePNG(t, 8000, 8000)), err: errors.ImageDimensions{Name: "big.png", Width: 8000, Height: 8000}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.checkImages(ctx, 2, tc.files)
+
+		assert.Nil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_StoreImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockBlobStore := stores.NewMockBlob(ctrl)
+	service := blog{imageStore: mockImageStore, blobStore: mockBlobStore}
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	data := encodePNG(t, 40, 30)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	uploads, err := service.checkImages(ctx, 2, fileHeaders(t, "a.png", data))
+	if !assert.NoError(t, err) || !assert.Len(t, uploads, 1) {
+		return
+	}
+
+	id := uploads[0].image.ID
+
+	// a new content is processed, and its renditions are named after its hash
+	var added *models.Blob
+
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), hash).Return(nil, errors.EntityNotFound{Entity: "blob", ID: hash})
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).Return(nil).Times(3)
+	mockImageStore.EXPECT().URL(gomock.Any()).DoAndReturn(func(name string) string { return "http://localhost:8000/images/" + name }).Times(3)
+	mockBlobStore.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, b *models.Blob) (*models.Blob, error) {
+		added = &models.Blob{Hash: b.Hash, Image: b.Image, Refs: 1}
+		return added, nil
+	})
+
+	images, err := service.storeImages(ctx, uploads)
+	if assert.NoError(t, err) && assert.Len(t, images, 1) {
+		assert.Equal(t, id, images[0].ID)
+		assert.Equal(t, hash, images[0].Hash)
+		assert.Regexp(t, "^http://localhost:8000/images/"+hash+"_[0-9a-f]{12}_thumbnail.jpg$", images[0].Thumbnail.URL)
+		assert.Equal(t, 40, images[0].Thumbnail.Width)
+	}
+
+	// a content stored already takes the renditions of its blob, without anything being stored
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), hash).DoAndReturn(func(*app.Context, string) (*models.Blob, error) { return added, nil })
+
+	again, err := service.storeImages(ctx, uploads)
+	if assert.NoError(t, err) && assert.Len(t, again, 1) {
+		assert.Equal(t, images, again)
+	}
+
+	// the same content stored at once by another request is named apart, and its blob is kept
+	var stored []string
+
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), hash).Return(nil, errors.EntityNotFound{Entity: "blob", ID: hash})
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).DoAndReturn(func(_ *app.Context, name, _ string, _ io.Reader) error {
+		stored = append(stored, name)
+		return nil
+	}).Times(3)
+	mockImageStore.EXPECT().URL(gomock.Any()).DoAndReturn(func(name string) string { return "http://localhost:8000/images/" + name }).Times(3)
+	mockBlobStore.EXPECT().Add(gomock.Any(), gomock.Any()).Return(added, nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, names []string) error {
+		assert.Equal(t, stored, names)
+		return nil
+	})
+
+	again, err = service.storeImages(ctx, uploads)
+	if assert.NoError(t, err) && assert.Len(t, again, 1) {
+		assert.Equal(t, images, again)
+		assert.NotContains(t, added.Image.FileNames(), stored[0])
+	}
+
+	// the renditions stored before a failure are deleted
+	stored = nil
+
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), hash).Return(nil, errors.EntityNotFound{Entity: "blob", ID: hash})
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).DoAndReturn(func(_ *app.Context, name, _ string, _ io.Reader) error {
+		stored = append(stored, name)
+		return nil
+	})
+	mockImageStore.EXPECT().URL(gomock.Any()).DoAndReturn(func(name string) string { return "http://localhost:8000/images/" + name })
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).Return(errors.DBError{})
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, names []string) error {
+		assert.Equal(t, stored, names)
+		return nil
+	})
+
+	images, err = service.storeImages(ctx, uploads)
+
+	assert.Nil(t, images)
+	assert.Equal(t, errors.DBError{}, err)
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_ReleaseImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockBlobStore := stores.NewMockBlob(ctrl)
+	service := blog{imageStore: mockImageStore, blobStore: mockBlobStore}
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	shared := models.Image{ID: "2_a", Hash: "aaa", Full: models.Rendition{URL: "http://localhost:8000/images/aaa_full.jpg"}}
+	last := models.Image{ID: "2_b", Hash: "bbb", Full: models.Rendition{URL: "http://localhost:8000/images/bbb_full.jpg"}}
+	reacquired := models.Image{ID: "2_c", Hash: "ccc", Full: models.Rendition{URL: "http://localhost:8000/images/ccc_full.jpg"}}
+	legacy := models.ImageFromURL("https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")
+
+	// a blob shown by other images is kept, one that is not is deleted along with its renditions
+	mockBlobStore.EXPECT().Release(gomock.Any(), "aaa").Return(&models.Blob{Hash: "aaa", Image: shared, Refs: 1}, nil)
+	mockBlobStore.EXPECT().Release(gomock.Any(), "bbb").Return(&models.Blob{Hash: "bbb", Image: last}, nil)
+	mockBlobStore.EXPECT().Delete(gomock.Any(), "bbb").Return(nil)
+	mockBlobStore.EXPECT().Release(gomock.Any(), "ccc").Return(&models.Blob{Hash: "ccc", Image: reacquired}, nil)
+	mockBlobStore.EXPECT().Delete(gomock.Any(), "ccc").Return(errors.EntityNotFound{Entity: "blob", ID: "ccc"})
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"bbb_full.jpg", "kid.jpg"}).Return(nil)
+
+	assert.NoError(t, service.releaseImages(ctx, []models.Image{shared, last, reacquired, legacy}))
+
+	// the images that can be released are, and the first failure is returned
+	mockBlobStore.EXPECT().Release(gomock.Any(), "aaa").Return(nil, errors.DBError{})
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"kid.jpg"}).Return(nil)
+
+	assert.Equal(t, errors.DBError{}, service.releaseImages(ctx, []models.Image{shared, legacy}))
+}
+
+func TestBlog_StoreImagesCancelled(t *testing.T) {
+	_, _, mockImageStore, ctx, _ := initializeTest(t)
+	service := blog{imageStore: mockImageStore}
+
+	uploads, err := service.checkImages(ctx, 2, fileHeaders(t, "a.png", encodePNG(t, 40, 30)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// nothing is stored for a request that is given up on
+	cancelled, cancel := ctx.WithCancel()
+	cancel()
+
+	images, err := service.storeImages(cancelled, uploads)
+
+	assert.Nil(t, images)
+	assert.Equal(t, context.Canceled, err)
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_CreateRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockBlobStore := stores.NewMockBlob(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, mockImageStore, stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), mockBlobStore, stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+	author := withViewer(ctx, 2)
+
+	var stored models.Blob
+
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "music").Return(map[string]string{}, nil).Times(2)
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(nil, errors.EntityNotFound{Entity: "blob"}).Times(2)
+	mockBlobStore.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, b *models.Blob) (*models.Blob, error) {
+		stored = *b
+		return &models.Blob{Hash: b.Hash, Image: b.Image, Refs: 1}, nil
+	}).Times(2)
+
+	// the image is released, and its renditions deleted as no other image shows them
+	mockBlobStore.EXPECT().Release(gomock.Any(), gomock.Any()).DoAndReturn(func(*app.Context, string) (*models.Blob, error) { return &stored, nil }).Times(2)
+	mockBlobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).Return(nil).Times(6)
+	mockImageStore.EXPECT().URL(gomock.Any()).DoAndReturn(func(name string) string { return "http://localhost:8000/images/" + name }).Times(6)
+
+	// the stored renditions are deleted when the blog cannot be written
+	mockBlogStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.DBError{})
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), gomock.Len(3)).Return(nil)
+
+	// the blog is deleted and untagged when its tags cannot be attached
+	res := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Tags: []string{"#music"}, Status: models.StatusPublished}
+
+	mockBlogStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(res, nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(errors.DBError{})
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(nil)
+	mockBlogStore.EXPECT().Delete(gomock.Any(), "MSI8WKNSH9").Return(nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), gomock.Len(3)).Return(errors.DBError{})
+
+	tests := []struct {
+		description string
+		err         error
+	}{
+		{description: "blog that cannot be written", err: errors.DBError{}},
+		{description: "tags that cannot be attached", err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		model := &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}}
+
+		output, err := mockBlogService.Create(author, model, fileHeaders(t, "a.png", encodePNG(t, 40, 30)))
+
+		assert.Nil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_UpdateRollback(t *testing.T) {
+	mockBlogStore, mockTagService, _, ctx, mockBlogService := initializeTest(t)
+	author := withViewer(ctx, 2)
+
+	images := legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")
+	current := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#kids"}, Images: images, Status: models.StatusPublished}
+	updated := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", ContentFormat: models.FormatPlain, ContentHTML: "<p>the best of childhood days!</p>\n", RenderVersion: markup.Version, Slug: "memories", OldSlugs: []string{}, Tags: []string{"#park"}, Images: images[:1], Status: models.StatusPublished, Visibility: models.VisibilityPublic}
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "9SH7SH2V37"}).Return(current, nil)
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "memories").Return(map[string]string{}, nil)
+	mockBlogStore.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "9SH7SH2V37", []string{"#kids"}).Return(nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "9SH7SH2V37", []string{"#park"}).Return(errors.DBError{})
+
+	// the tags are moved back and the previous version is written back, the removed image is kept
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "9SH7SH2V37", []string{"#park"}).Return(nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "9SH7SH2V37", []string{"#kids"}).Return(nil)
+	mockBlogStore.EXPECT().Revert(gomock.Any(), current).Return(nil)
+
+	model := &models.Blog{BlogID: "9SH7SH2V37", Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#park"}, Images: []models.Image{{ID: "girl.jpeg"}}}
+
+	output, err := mockBlogService.Update(author, model, nil)
+
+	assert.Nil(t, output)
+	assert.Equal(t, errors.DBError{}, err)
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_UpdateUnrevised(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockRevisionStore := stores.NewMockRevision(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), mockRevisionStore, stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+	author := withViewer(ctx, 2)
+
+	images := legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg")
+	current := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#kids"}, Images: images, Status: models.StatusPublished}
+	updated := &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", ContentFormat: models.FormatPlain, ContentHTML: "<p>the best of childhood days!</p>\n", RenderVersion: markup.Version, Slug: "memories", OldSlugs: []string{}, Tags: []string{"#park"}, Images: images, Status: models.StatusPublished, Visibility: models.VisibilityPublic}
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "9SH7SH2V37"}).Return(current, nil)
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "memories").Return(map[string]string{}, nil)
+	mockBlogStore.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "9SH7SH2V37", []string{"#kids"}).Return(nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "9SH7SH2V37", []string{"#park"}).Return(nil)
+	mockRevisionStore.EXPECT().GetLatest(gomock.Any(), "9SH7SH2V37").Return(nil, errors.DBError{})
+
+	// an update without its revision is undone, the blog is neither indexed nor flagged
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "9SH7SH2V37", []string{"#park"}).Return(nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "9SH7SH2V37", []string{"#kids"}).Return(nil)
+	mockBlogStore.EXPECT().Revert(gomock.Any(), current).Return(nil)
+
+	model := &models.Blog{BlogID: "9SH7SH2V37", Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#park"}, Images: []models.Image{{ID: "girl.jpeg"}}}
+
+	output, err := mockBlogService.Update(author, model, nil)
+
+	assert.Nil(t, output)
+	assert.Equal(t, errors.DBError{}, err)
+}
+
+//nolint:lll // test cases need to be readable
+func TestDiff(t *testing.T) {
+	old := &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, Images: legacyImages("a.jpg")}
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		changes     []string
+	}{
+		{description: "no changes", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, Images: legacyImages("a.jpg"), Status: models.StatusPublished}, changes: []string{}},
+		{description: "text changes", model: &models.Blog{Title: "songs", Summary: "a blog on songs", Content: "avicii ♥", Tags: []string{"#music"}, Images: legacyImages("a.jpg")}, changes: []string{"title changed", "summary changed", "content changed (+2 characters)"}},
+		{description: "tag and image changes", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#edm", "#house"}, Images: legacyImages("a.jpg", "b.jpg")}, changes: []string{"tags added: #edm, #house", "tags removed: #music", "images added: 1"}},
+		{description: "images removed", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, Images: []models.Image{}}, changes: []string{"images removed: 1"}},
+		{description: "status change", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{"#music"}, Images: legacyImages("a.jpg"), Status: models.StatusArchived}, changes: []string{"status changed to archived"}},
+		{description: "format change", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii", ContentFormat: models.FormatMarkdown, Tags: []string{"#music"}, Images: legacyImages("a.jpg"), Status: models.StatusPublished}, changes: []string{"content format changed to markdown"}},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.changes, diff(old, tc.model), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func withViewer(ctx *app.Context, id int64) *app.Context {
+	c := *ctx
+	c.Context = context.WithValue(ctx.Context, auth.JWTContextKey("claims"), &auth.Claims{UserID: id})
+
+	return &c
+}
+
 //nolint:lll // hampers readability
 func getAllOutput() []*models.Blog {
 	return []*models.Blog{
@@ -144,7 +892,7 @@ func getAllOutput() []*models.Blog {
 			Content:   "avicii left :(",
 			Tags:      []string{},
 			CreatedOn: getTime("2021-05-23T15:04:05Z"),
-			Images:    []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"},
+			Images:    legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"),
 		},
 		{
 			BlogID:    "9SNVSH8K2M",
@@ -154,7 +902,7 @@ func getAllOutput() []*models.Blog {
 			Content:   "blue orchids are the most beautiful",
 			Tags:      []string{"#love", "#nature", "#life"},
 			CreatedOn: getTime("2021-04-16T15:04:05Z"),
-			Images:    []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg"},
+			Images:    legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg"),
 		},
 		{
 			BlogID:    "ABK7SH2V37",
@@ -164,12 +912,88 @@ func getAllOutput() []*models.Blog {
 			Content:   "bournville is the best chocolate!",
 			Tags:      []string{"#cocoa", "#sweet", "#chocolate", "#trending"},
 			CreatedOn: getTime("2021-04-10T15:04:05Z"),
-			Images:    []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg"},
+			Images:    legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg"),
 		},
 	}
 }
 
+// renderedOutput is the output of getAllOutput, as it is served with its content rendered.
+func renderedOutput() []*models.Blog {
+	blogs := getAllOutput()
+
+	for _, blog := range blogs {
+		blog.ContentHTML = "<p>" + blog.Content + "</p>\n"
+		blog.RenderVersion = markup.Version
+	}
+
+	return blogs
+}
+
+// legacyImages describes images stored as bare URLs, before renditions were generated.
+func legacyImages(urls ...string) []models.Image {
+	images := make([]models.Image, 0, len(urls))
+
+	for _, url := range urls {
+		images = append(images, models.ImageFromURL(url))
+	}
+
+	return images
+}
+
+// fileHeaders returns the header of a file, as uploaded in a multipart form.
+func fileHeaders(t *testing.T, name string, data []byte) []*multipart.FileHeader {
+	var body bytes.Buffer
+
+	w := multipart.NewWriter(&body)
+
+	part, err := w.CreateFormFile("image", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = part.Write(data)
+	_ = w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return form.File["image"]
+}
+
+// encodePNG returns a blank picture of the given size, which compresses to a small file.
+func encodePNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
 func getTime(date string) time.Time {
 	t, _ := time.Parse("2006-01-02T15:04:05Z07:00", date)
 	return t
 }
+
+func TestCheckMissingParams(t *testing.T) {
+	tests := []struct {
+		description string
+		model       *models.Blog
+		err         error
+	}{
+		{description: "complete blog", model: &models.Blog{AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :("}},
+		{description: "missing account", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii left :("}, err: errors.MissingParam{Param: "account_id"}},
+		{description: "missing content", model: &models.Blog{AccountID: 2, Title: "music", Summary: "a blog on music"}, err: errors.MissingParam{Param: "content"}},
+		{description: "content at the limit", model: &models.Blog{AccountID: 2, Title: "music", Summary: "a blog on music", Content: strings.Repeat("é", maxContentLength)}},
+		{description: "content over the limit", model: &models.Blog{AccountID: 2, Title: "music", Summary: "a blog on music", Content: strings.Repeat("a", maxContentLength+1)}, err: errors.InvalidParam{Param: "content"}},
+	}
+
+	for i, tc := range tests {
+		err := checkMissingParams(tc.model)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -0,0 +1,490 @@
+package blog
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/imaging"
+)
+
+// maxConcurrentImages bounds the pictures of a request that are decoded and stored at a time,
+// as a decoded picture takes memory in proportion to its pixels.
+const maxConcurrentImages = 2
+
+// upload is an uploaded picture that is checked and named. Its renditions are generated as it is stored.
+type upload struct {
+	name  string // name of the uploaded file, for errors
+	image models.Image
+	open  func() (io.ReadSeekCloser, error)
+}
+
+// checkImages checks the uploaded pictures of an account, and names their images.
+// Only the headers of the files are read, so that a picture that is rejected rejects the whole request
+// before anything is decoded or stored.
+func (b blog) checkImages(ctx *app.Context, accountID int64, files []*multipart.FileHeader) ([]upload, error) {
+	if len(files) == 0 {
+		return []upload{}, nil
+	}
+
+	limits := imaging.NewLimits(ctx.Config)
+
+	if err := checkUploads(limits, files); err != nil {
+		return nil, err
+	}
+
+	uploads := make([]upload, 0, len(files))
+
+	for _, file := range files {
+		u := newUpload(accountID, file.Filename, openFile(file))
+
+		if err := u.validate(limits, file.Size); err != nil {
+			return nil, err
+		}
+
+		uploads = append(uploads, u)
+	}
+
+	return uploads, nil
+}
+
+func newUpload(accountID int64, name string, open func() (io.ReadSeekCloser, error)) upload {
+	return upload{name: name, image: models.Image{ID: fmt.Sprintf("%v_%v", accountID, generateNewID())}, open: open}
+}
+
+func (u upload) validate(limits imaging.Limits, size int64) error {
+	f, err := u.open()
+	if err != nil {
+		return errors.BodyRead{Err: err}
+	}
+
+	defer f.Close()
+
+	return limits.Validate(u.name, size, f)
+}
+
+// checkUploads checks the count and sizes of the uploaded files, before any of them is read.
+func checkUploads(limits imaging.Limits, files []*multipart.FileHeader) error {
+	if len(files) > limits.MaxImages {
+		return errors.TooManyImages{Count: len(files), Limit: limits.MaxImages}
+	}
+
+	var total int64
+
+	for _, file := range files {
+		if file.Size > limits.MaxFileSize {
+			return errors.ImageTooLarge{Name: file.Filename, Size: file.Size, Limit: limits.MaxFileSize}
+		}
+
+		total += file.Size
+	}
+
+	if total > limits.MaxRequestSize {
+		return errors.UploadTooLarge{Limit: limits.MaxRequestSize}
+	}
+
+	return nil
+}
+
+// storeImages stores the checked pictures, a few pictures at a time, so that the memory taken by a request
+// is bounded whatever the number and size of its files.
+// The first picture that fails cancels the pictures being stored and those not started yet. Once every picture
+// has stopped, the pictures stored already are released, and the renditions of those that failed are deleted.
+// The images are returned in the order of the uploads.
+func (b blog) storeImages(ctx *app.Context, uploads []upload) ([]models.Image, error) {
+	images := make([]models.Image, len(uploads))
+	names := make([][]string, len(uploads))
+	done := make([]bool, len(uploads))
+
+	storeCtx, cancel := ctx.WithCancel()
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		failure error
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	sem := make(chan struct{}, maxConcurrentImages)
+
+	for i := range uploads {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// a picture is not started once the request is given up on
+			if err := storeCtx.Err(); err != nil {
+				fail(err)
+				return
+			}
+
+			var err error
+
+			images[i], names[i], err = b.storeImage(storeCtx, uploads[i])
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			done[i] = true
+		}(i)
+	}
+
+	wg.Wait()
+
+	if failure == nil {
+		return images, nil
+	}
+
+	b.unstoreImages(ctx.Detach(), images, names, done)
+
+	return nil, failure
+}
+
+// unstoreImages undoes the pictures of a request that failed. The pictures stored are released,
+// and the renditions stored of the pictures that failed are deleted.
+func (b blog) unstoreImages(ctx *app.Context, images []models.Image, names [][]string, done []bool) {
+	stored := make([]models.Image, 0)
+	partial := make([]string, 0)
+
+	for i := range images {
+		if done[i] {
+			stored = append(stored, images[i])
+		} else {
+			partial = append(partial, names[i]...)
+		}
+	}
+
+	if err := b.releaseImages(ctx, stored); err != nil {
+		ctx.Logger.Errorf("cannot release images of a failed upload: %s", err.Error())
+	}
+
+	if len(partial) != 0 {
+		if err := b.imageStore.DeleteBulk(ctx, partial); err != nil {
+			ctx.Logger.Errorf("cannot delete renditions of a failed upload, left behind: %s: %s",
+				strings.Join(partial, ", "), err.Error())
+		}
+	}
+}
+
+// storeImage stores a picture as a reference to the blob of its content. A picture whose content is stored already
+// takes the renditions of its blob, else it is decoded and each of its renditions is streamed to the storage.
+// It returns the names of the renditions it stored, even if it fails.
+func (b blog) storeImage(ctx *app.Context, u upload) (models.Image, []string, error) {
+	f, err := u.open()
+	if err != nil {
+		return models.Image{}, nil, errors.BodyRead{Err: err}
+	}
+
+	defer f.Close()
+
+	hash, err := contentHash(f)
+	if err != nil {
+		return models.Image{}, nil, errors.BodyRead{Err: err}
+	}
+
+	blob, err := b.blobStore.Acquire(ctx, hash)
+
+	switch err.(type) {
+	case nil:
+		return showing(u.image, blob), nil, nil
+	case errors.EntityNotFound:
+	default:
+		return models.Image{}, nil, err
+	}
+
+	renditions, err := imaging.Process(f)
+	if err != nil {
+		ctx.Logger.Errorf("cannot process image %s: %s", u.name, err.Error())
+		return models.Image{}, nil, errors.InvalidParam{Param: "image"}
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return models.Image{}, nil, err
+	}
+
+	image := models.Image{Hash: hash}
+	names := make([]string, 0, len(renditions))
+
+	for _, r := range renditions {
+		if err = ctx.Err(); err != nil {
+			return models.Image{}, names, err
+		}
+
+		// the renditions of a blob are named after it, and apart from those of a blob of the same content that is
+		// deleted meanwhile, or added at once by another request, so that deleting either never deletes these
+		name := fmt.Sprintf("%s_%s_%s%s", hash, nonce, r.Size, r.Extension)
+
+		if err = b.imageStore.Upload(ctx, name, r.ContentType, bytes.NewReader(r.Data)); err != nil {
+			return models.Image{}, names, err
+		}
+
+		names = append(names, name)
+		rendition := models.Rendition{URL: b.imageStore.URL(name), Width: r.Width, Height: r.Height}
+
+		switch r.Size {
+		case imaging.Thumbnail:
+			image.Thumbnail = rendition
+		case imaging.Medium:
+			image.Medium = rendition
+		case imaging.Full:
+			image.Full = rendition
+		}
+	}
+
+	blob, err = b.blobStore.Add(ctx, &models.Blob{Hash: hash, Image: image, CreatedOn: time.Now()})
+	if err != nil {
+		return models.Image{}, names, err
+	}
+
+	// a blob of the same content added at once by another request is kept, and shows its own renditions
+	if blob.Image.Full.URL != image.Full.URL {
+		if err := b.imageStore.DeleteBulk(ctx, names); err != nil {
+			ctx.Logger.Errorf("cannot delete renditions of blob %s stored twice, left behind: %s: %s",
+				hash, strings.Join(names, ", "), err.Error())
+		}
+	}
+
+	return showing(u.image, blob), nil, nil
+}
+
+// newNonce returns a random string that sets the renditions of a blob apart from those of other blobs of the same content.
+func newNonce() (string, error) {
+	b := make([]byte, 6)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// contentHash returns the hex encoded SHA-256 of a file, which is read from its start and rewound.
+func contentHash(f io.ReadSeeker) (string, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// showing returns the image with the renditions of the blob it shows.
+func showing(image models.Image, blob *models.Blob) models.Image {
+	image.Thumbnail, image.Medium, image.Full = blob.Image.Thumbnail, blob.Image.Medium, blob.Image.Full
+	image.Hash = blob.Hash
+
+	return image
+}
+
+// releaseImages drops the references of images to their blobs, and deletes the renditions of the blobs
+// that no image shows anymore. The renditions of images stored before blobs are deleted outright.
+// A blob is deleted before its renditions, which a blob of the same content added meanwhile does not share.
+// Every image is tried, and the first failure is returned.
+func (b blog) releaseImages(ctx *app.Context, images []models.Image) error {
+	var failure error
+
+	names := make([]string, 0)
+
+	for _, image := range images {
+		if image.Hash == "" {
+			names = append(names, image.FileNames()...)
+			continue
+		}
+
+		blob, err := b.blobStore.Release(ctx, image.Hash)
+		if err != nil {
+			failure = first(failure, err)
+			continue
+		}
+
+		if blob.Refs > 0 {
+			continue
+		}
+
+		err = b.blobStore.Delete(ctx, blob.Hash)
+
+		switch err.(type) {
+		case nil:
+			names = append(names, blob.Image.FileNames()...)
+		case errors.EntityNotFound:
+			// shown by another image meanwhile
+		default:
+			failure = first(failure, err)
+		}
+	}
+
+	if len(names) != 0 {
+		if err := b.imageStore.DeleteBulk(ctx, names); err != nil {
+			failure = first(failure, err)
+		}
+	}
+
+	return failure
+}
+
+// openFile opens an uploaded file, which is kept in memory or in a temporary file.
+func openFile(file *multipart.FileHeader) func() (io.ReadSeekCloser, error) {
+	return func() (io.ReadSeekCloser, error) {
+		return file.Open()
+	}
+}
+
+// openData opens a file that is read into memory.
+func openData(data []byte) func() (io.ReadSeekCloser, error) {
+	return func() (io.ReadSeekCloser, error) {
+		return nopCloser{bytes.NewReader(data)}, nil
+	}
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// placeholders returns the images the uploads are to be stored as, known by their IDs alone.
+func placeholders(uploads []upload) []models.Image {
+	images := make([]models.Image, 0, len(uploads))
+
+	for i := range uploads {
+		images = append(images, uploads[i].image)
+	}
+
+	return images
+}
+
+// withRenditions replaces the images of the stored uploads, which are known by their IDs alone, with the stored images.
+func withRenditions(images, stored []models.Image) []models.Image {
+	byID := make(map[string]models.Image, len(stored))
+	for _, image := range stored {
+		byID[image.ID] = image
+	}
+
+	for i, image := range images {
+		if s, ok := byID[image.ID]; ok {
+			images[i] = s
+		}
+	}
+
+	return images
+}
+
+// arrangeImages returns the final list of images of an updated blog.
+// The order lists images by their ID, or "upload:<n>" for the n-th uploaded image, each at most once.
+// Uploads left out of the order are appended. Without an order (nil), existing images are kept.
+func arrangeImages(order, existing, uploaded []models.Image) ([]models.Image, error) {
+	if order == nil {
+		return append(append([]models.Image{}, existing...), uploaded...), nil
+	}
+
+	byID := make(map[string]models.Image, len(existing)+len(uploaded))
+	for _, image := range append(append([]models.Image{}, existing...), uploaded...) {
+		byID[image.ID] = image
+	}
+
+	images := make([]models.Image, 0, len(order)+len(uploaded))
+	seen := make(map[string]bool)
+
+	for _, item := range order {
+		image, ok := byID[item.ID]
+
+		if strings.HasPrefix(item.ID, uploadPrefix) {
+			i, err := strconv.Atoi(strings.TrimPrefix(item.ID, uploadPrefix))
+			if err != nil || i < 0 || i >= len(uploaded) {
+				return nil, errors.InvalidParam{Param: "images"}
+			}
+
+			image, ok = uploaded[i], true
+		}
+
+		if !ok || seen[image.ID] {
+			return nil, errors.InvalidParam{Param: "images"}
+		}
+
+		seen[image.ID] = true
+		images = append(images, image)
+	}
+
+	for _, image := range uploaded {
+		if !seen[image.ID] {
+			images = append(images, image)
+		}
+	}
+
+	return images, nil
+}
+
+// removedImages returns the images of a blog that are left out of its updated images.
+func removedImages(old, updated []models.Image) []models.Image {
+	kept := make(map[string]bool, len(updated))
+	for _, image := range updated {
+		kept[image.ID] = true
+	}
+
+	removed := make([]models.Image, 0)
+
+	for _, image := range old {
+		if !kept[image.ID] {
+			removed = append(removed, image)
+		}
+	}
+
+	return removed
+}
+
+// first returns the failure recorded already, if any, else err.
+func first(failure, err error) error {
+	if failure != nil {
+		return failure
+	}
+
+	return err
+}
+
+// releaseImagesStep returns the step that releases stored images.
+func (b blog) releaseImagesStep(images []models.Image) func(ctx *app.Context) error {
+	return func(ctx *app.Context) error {
+		return b.releaseImages(ctx, images)
+	}
+}
+
+func imageIDs(images []models.Image) []string {
+	ids := make([]string, 0, len(images))
+
+	for _, image := range images {
+		ids = append(ids, image.ID)
+	}
+
+	return ids
+}
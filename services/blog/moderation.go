@@ -0,0 +1,53 @@
+package blog
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+// HiddenNotice is shown to the author of a blog hidden by a moderator, the only one who still sees it.
+const HiddenNotice = "this blog has been hidden by a moderator and is visible to you alone"
+
+// noticed sets the notice of the blogs hidden by a moderator, which are served to their author alone.
+func noticed(blogs ...*models.Blog) {
+	for _, blog := range blogs {
+		if blog != nil && blog.Hidden {
+			blog.Notice = HiddenNotice
+		}
+	}
+}
+
+// SetHidden hides a blog from everyone but its author, or lists it again.
+// It is meant for moderators, the caller checks the role of the account.
+func (b blog) SetHidden(ctx *app.Context, id string, hidden bool) (*models.Blog, error) {
+	if id == "" {
+		return nil, errors.MissingParam{Param: "blog_id"}
+	}
+
+	old, err := b.blogStore.Get(ctx, &models.Blog{BlogID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	blog, err := b.blogStore.SetHidden(ctx, id, hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	b.index(ctx, blog)
+
+	// tags list the blogs listed to everyone alone, a failure is logged as the blog is already hidden or listed
+	switch {
+	case blog.IsListed() && !old.IsListed():
+		err = b.tagService.AddBlogID(ctx, blog.BlogID, blog.Tags)
+	case old.IsListed() && !blog.IsListed():
+		err = b.tagService.RemoveBlogID(ctx, blog.BlogID, blog.Tags)
+	}
+
+	if err != nil {
+		ctx.Logger.Errorf("cannot retag blog %s: %s", blog.BlogID, err.Error())
+	}
+
+	return blog, nil
+}
@@ -0,0 +1,105 @@
+package blog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+//nolint:lll // test cases need to be readable
+func TestBlog_SetHidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockTagService := services.NewMockTag(ctrl)
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockBlogService := New(mockBlogStore, mockTagService, stores.NewMockImage(ctrl), mockSearchIndex, stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	hidden := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Tags: []string{"#music"}, Visibility: models.VisibilityPublic, Hidden: true}
+	listed := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Tags: []string{"#music"}, Visibility: models.VisibilityPublic}
+
+	// a hidden blog is taken out of search and off its tags, and put back once listed again
+	gomock.InOrder(
+		mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(listed, nil),
+		mockBlogStore.EXPECT().SetHidden(gomock.Any(), "MSI8WKNSH9", true).Return(hidden, nil),
+		mockSearchIndex.EXPECT().Remove(gomock.Any(), "MSI8WKNSH9").Return(nil),
+		mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(nil),
+		mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(hidden, nil),
+		mockBlogStore.EXPECT().SetHidden(gomock.Any(), "MSI8WKNSH9", false).Return(listed, nil),
+		mockSearchIndex.EXPECT().Index(gomock.Any(), listed).Return(nil),
+		mockTagService.EXPECT().AddBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(nil),
+		// hiding a blog that is already hidden leaves its tags alone
+		mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).Return(hidden, nil),
+		mockBlogStore.EXPECT().SetHidden(gomock.Any(), "MSI8WKNSH9", true).Return(hidden, nil),
+		mockSearchIndex.EXPECT().Remove(gomock.Any(), "MSI8WKNSH9").Return(nil),
+	)
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "9SNVSH8K2M"}).Return(nil, errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"})
+
+	tests := []struct {
+		description string
+		id          string
+		hidden      bool
+		output      *models.Blog
+		err         error
+	}{
+		{description: "hide a blog", id: "MSI8WKNSH9", hidden: true, output: hidden},
+		{description: "list a blog again", id: "MSI8WKNSH9", output: listed},
+		{description: "hide a hidden blog", id: "MSI8WKNSH9", hidden: true, output: hidden},
+		{description: "unknown blog", id: "9SNVSH8K2M", hidden: true, err: errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"}},
+		{description: "missing blog id", hidden: true, err: errors.MissingParam{Param: "blog_id"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.SetHidden(ctx, tc.id, tc.hidden)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetByIDHidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), mockBlockStore, stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9"}).DoAndReturn(func(*app.Context, *models.Blog) (*models.Blog, error) {
+		return &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Visibility: models.VisibilityPublic, Hidden: true}, nil
+	}).AnyTimes()
+	mockBlockStore.EXPECT().Exists(gomock.Any(), gomock.Any(), int64(2)).Return(false, nil).AnyTimes()
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		err         error
+	}{
+		{description: "hidden blog to its author", ctx: withViewer(ctx, 2)},
+		{description: "hidden blog to another account", ctx: withViewer(ctx, 3), err: errors.EntityNotFound{Entity: "blog", ID: "MSI8WKNSH9"}},
+		{description: "hidden blog to a logged out viewer", ctx: ctx, err: errors.EntityNotFound{Entity: "blog", ID: "MSI8WKNSH9"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetByID(tc.ctx, "MSI8WKNSH9")
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil && assert.NotNil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.Equal(t, HiddenNotice, output.Notice, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
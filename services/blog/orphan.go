@@ -0,0 +1,109 @@
+package blog
+
+import (
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/configs"
+)
+
+const (
+	orphanBatchSize = 1000 // files listed and deleted at a time, the most an S3 bulk delete takes
+
+	// defaultGracePeriod is the age a file no blog refers to has to reach to be collected, unless IMAGE_GC_GRACE_PERIOD is set.
+	defaultGracePeriod = 48 * time.Hour
+
+	// minGracePeriod outlives an upload, so that the files of an upload that is being attached are never collected.
+	minGracePeriod = uploadExpiry + attachExpiry
+)
+
+// CollectOrphans deletes the files of the image storage that no blog or blob refers to, and that are older than the grace period.
+// The files of uploads and of blogs being written are not referred to yet, the grace period keeps them.
+// A dry run counts the orphans without deleting them.
+func (b blog) CollectOrphans(ctx *app.Context, dryRun bool) (*models.OrphanReport, error) {
+	// the names of the referenced files are all that is kept of the blogs and blobs, as they are read
+	referenced := make(map[string]bool)
+
+	refer := func(image models.Image) {
+		for _, name := range image.FileNames() {
+			referenced[name] = true
+		}
+	}
+
+	if err := b.blogStore.EachImage(ctx, refer); err != nil {
+		return nil, err
+	}
+
+	// a blob that is referenced keeps its renditions, even if the images that refer to it are gone
+	if err := b.blobStore.Each(ctx, func(blob *models.Blob) { refer(blob.Image) }); err != nil {
+		return nil, err
+	}
+
+	report := &models.OrphanReport{DryRun: dryRun}
+	cutoff := time.Now().Add(-gracePeriod(ctx.Config))
+	token := ""
+
+	for {
+		files, next, err := b.imageStore.List(ctx, token, orphanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		orphans := make([]string, 0)
+
+		for _, file := range files {
+			report.Scanned++
+
+			switch {
+			case referenced[file.Name]:
+				report.Referenced++
+			case file.ModifiedOn.After(cutoff):
+				report.Recent++
+			default:
+				report.Orphaned++
+				report.OrphanedBytes += file.Size
+				orphans = append(orphans, file.Name)
+			}
+		}
+
+		if len(orphans) != 0 && !dryRun {
+			if err := b.imageStore.DeleteBulk(ctx, orphans); err != nil {
+				ctx.Logger.Errorf("cannot delete %v orphaned images: %s", len(orphans), err.Error())
+				report.Failed += len(orphans)
+			} else {
+				report.Deleted += len(orphans)
+			}
+		}
+
+		// a page can be short of the batch and still not be the last one
+		if next == "" {
+			break
+		}
+
+		token = next
+	}
+
+	ctx.Logger.Infof("collected orphaned images (dry run: %v): scanned %v, referenced %v, recent %v, orphaned %v (%v bytes), deleted %v, failed %v",
+		report.DryRun, report.Scanned, report.Referenced, report.Recent, report.Orphaned, report.OrphanedBytes, report.Deleted, report.Failed)
+
+	return report, nil
+}
+
+// gracePeriod reads the grace period of orphaned files from the configs, which cannot be shorter than an upload lives.
+func gracePeriod(c configs.Config) time.Duration {
+	if c == nil {
+		return defaultGracePeriod
+	}
+
+	grace, err := time.ParseDuration(c.GetOrDefault("IMAGE_GC_GRACE_PERIOD", ""))
+	if err != nil {
+		return defaultGracePeriod
+	}
+
+	if grace < minGracePeriod {
+		return minGracePeriod
+	}
+
+	return grace
+}
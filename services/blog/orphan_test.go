@@ -0,0 +1,121 @@
+package blog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+//nolint:lll // test cases need to be readable
+func TestBlog_CollectOrphans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockBlobStore := stores.NewMockBlob(ctrl)
+	service := blog{blogStore: mockBlogStore, imageStore: mockImageStore, blobStore: mockBlobStore}
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	old, recent := time.Now().Add(-72*time.Hour), time.Now().Add(-time.Hour)
+	images := []models.Image{
+		models.ImageFromURL("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg"),
+		{ID: "2_a", Thumbnail: models.Rendition{URL: "http://localhost:8000/images/2_a_thumbnail.jpg"}, Medium: models.Rendition{URL: "http://localhost:8000/images/2_a_medium.jpg"}, Full: models.Rendition{URL: "http://localhost:8000/images/2_a_full.jpg"}},
+	}
+	files := []models.StoredFile{
+		{Name: "2_a_full.jpg", Size: 30, ModifiedOn: old},
+		{Name: "2_a_medium.jpg", Size: 20, ModifiedOn: old},
+		{Name: "2_a_thumbnail.jpg", Size: 10, ModifiedOn: old},
+		{Name: "2_b_full.jpg", Size: 30, ModifiedOn: old},
+		{Name: "2_c_full.jpg", Size: 30, ModifiedOn: recent},
+		{Name: "ddd_full.jpg", Size: 30, ModifiedOn: old},
+		{Name: "girl.jpeg", Size: 40, ModifiedOn: old},
+		{Name: "kid.jpg", Size: 50, ModifiedOn: old},
+	}
+
+	// the renditions of a blob are referred to even if no blog shows them anymore
+	blobs := []*models.Blob{{Hash: "ddd", Image: models.Image{Full: models.Rendition{URL: "http://localhost:8000/images/ddd_full.jpg"}}, Refs: 1}}
+
+	mockBlogStore.EXPECT().EachImage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, fn func(models.Image)) error {
+		for _, image := range images {
+			fn(image)
+		}
+
+		return nil
+	}).Times(3)
+	mockBlobStore.EXPECT().Each(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, fn func(*models.Blob)) error {
+		for _, blob := range blobs {
+			fn(blob)
+		}
+
+		return nil
+	}).Times(3)
+
+	// the files are listed by pages until there is no next page, even after a page short of the batch
+	mockImageStore.EXPECT().List(gomock.Any(), "", orphanBatchSize).Return(files[:3], "t1", nil).Times(3)
+	mockImageStore.EXPECT().List(gomock.Any(), "t1", orphanBatchSize).Return(files[3:], "", nil).Times(3)
+
+	// orphans are deleted unless the run is dry
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_b_full.jpg", "kid.jpg"}).Return(nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_b_full.jpg", "kid.jpg"}).Return(errors.DBError{})
+
+	mockBlogStore.EXPECT().EachImage(gomock.Any(), gomock.Any()).Return(errors.DBError{})
+
+	tests := []struct {
+		description string
+		dryRun      bool
+		output      *models.OrphanReport
+		err         error
+	}{
+		{description: "dry run", dryRun: true, output: &models.OrphanReport{DryRun: true, Scanned: 8, Referenced: 5, Recent: 1, Orphaned: 2, OrphanedBytes: 80}},
+		{description: "orphans deleted", output: &models.OrphanReport{Scanned: 8, Referenced: 5, Recent: 1, Orphaned: 2, OrphanedBytes: 80, Deleted: 2}},
+		{description: "orphans that cannot be deleted", output: &models.OrphanReport{Scanned: 8, Referenced: 5, Recent: 1, Orphaned: 2, OrphanedBytes: 80, Failed: 2}},
+		{description: "blogs that cannot be read", err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.CollectOrphans(ctx, tc.dryRun)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestGracePeriod(t *testing.T) {
+	tests := []struct {
+		description string
+		config      testConfig
+		output      time.Duration
+	}{
+		{description: "default", config: testConfig{}, output: defaultGracePeriod},
+		{description: "configured", config: testConfig{"IMAGE_GC_GRACE_PERIOD": "72h"}, output: 72 * time.Hour},
+		{description: "shorter than an upload lives", config: testConfig{"IMAGE_GC_GRACE_PERIOD": "1h"}, output: minGracePeriod},
+		{description: "invalid", config: testConfig{"IMAGE_GC_GRACE_PERIOD": "two days"}, output: defaultGracePeriod},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.output, gracePeriod(tc.config), "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+// testConfig is a config of fixed values.
+type testConfig map[string]string
+
+func (c testConfig) Get(key string) string {
+	return c[key]
+}
+
+func (c testConfig) GetOrDefault(key, defaultVal string) string {
+	if val, ok := c[key]; ok {
+		return val
+	}
+
+	return defaultVal
+}
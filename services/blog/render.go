@@ -0,0 +1,91 @@
+package blog
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/markup"
+)
+
+const renderBatchSize = 100
+
+// setFormat validates the format of the content of a new or updated blog.
+// A blog without a format keeps the format of its old version, and a new blog is plain text.
+func setFormat(model, old *models.Blog) error {
+	switch model.ContentFormat {
+	case models.FormatPlain, models.FormatMarkdown:
+		return nil
+	case "":
+		model.ContentFormat = models.FormatPlain
+		if old != nil {
+			model.ContentFormat = old.Format()
+		}
+
+		return nil
+	default:
+		return errors.InvalidParam{Param: "content_format"}
+	}
+}
+
+// render renders the content of a blog to sanitized HTML, by the current version of the markup policy.
+func render(model *models.Blog) {
+	if model.Format() == models.FormatMarkdown {
+		model.ContentHTML = markup.Markdown(model.Content)
+	} else {
+		model.ContentHTML = markup.Plain(model.Content)
+	}
+
+	model.RenderVersion = markup.Version
+}
+
+// rendered renders the blogs whose stored rendering is stale, so that they are served by the current policy
+// before the renderer gets to store it.
+func rendered(blogs ...*models.Blog) {
+	for _, blog := range blogs {
+		if blog != nil && blog.RenderVersion < markup.Version {
+			render(blog)
+		}
+	}
+}
+
+// RenderStale renders again the content of the blogs rendered by an older version of the markup policy, batch by batch.
+// It is run periodically by the background renderer, and runs until no stale blog is left or a batch makes no progress.
+func (b blog) RenderStale(ctx *app.Context) error {
+	total := 0
+
+	for {
+		stale, err := b.blogStore.GetStale(ctx, markup.Version, renderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		count := 0
+
+		for _, blog := range stale {
+			render(blog)
+
+			err := b.blogStore.SetRendered(ctx, blog)
+
+			switch err.(type) {
+			case nil:
+				count++
+			case errors.EntityNotFound:
+				continue // updated since it was read, and rendered by the update
+			default:
+				return err
+			}
+		}
+
+		total += count
+
+		if len(stale) < renderBatchSize || count == 0 {
+			break
+		}
+	}
+
+	if total != 0 {
+		ctx.Logger.Infof("rendered %v blogs by version %v of the markup policy", total, markup.Version)
+	}
+
+	return nil
+}
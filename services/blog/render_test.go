@@ -0,0 +1,82 @@
+package blog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/markup"
+)
+
+func TestSetFormat(t *testing.T) {
+	markdown := &models.Blog{ContentFormat: models.FormatMarkdown}
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		old         *models.Blog
+		format      string
+		err         error
+	}{
+		{description: "new blog without a format", model: &models.Blog{}, format: models.FormatPlain},
+		{description: "new blog in markdown", model: &models.Blog{ContentFormat: models.FormatMarkdown}, format: models.FormatMarkdown},
+		{description: "update keeps the format", model: &models.Blog{}, old: markdown, format: models.FormatMarkdown},
+		{description: "update of a blog without a format", model: &models.Blog{}, old: &models.Blog{}, format: models.FormatPlain},
+		{description: "update changes the format", model: &models.Blog{ContentFormat: models.FormatPlain}, old: markdown, format: models.FormatPlain},
+		{description: "unknown format", model: &models.Blog{ContentFormat: "html"}, format: "html", err: errors.InvalidParam{Param: "content_format"}},
+	}
+
+	for i, tc := range tests {
+		err := setFormat(tc.model, tc.old)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+		assert.Equal(t, tc.format, tc.model.ContentFormat, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestRender(t *testing.T) {
+	tests := []struct {
+		description string
+		model       *models.Blog
+		html        string
+	}{
+		{description: "plain text", model: &models.Blog{Content: "a <b>bold</b> claim\nover lines\n\nand paragraphs"}, html: "<p>a &lt;b&gt;bold&lt;/b&gt; claim<br>over lines</p>\n<p>and paragraphs</p>\n"},
+		{description: "markdown", model: &models.Blog{ContentFormat: models.FormatMarkdown, Content: "# Avicii\n\n**levels** by _avicii_, see [here](https://avicii.com \"home\")\n\n- wake me up\n- the nights"},
+			html: "<h1>Avicii</h1>\n<p><strong>levels</strong> by <em>avicii</em>, see <a href=\"https://avicii.com\" title=\"home\" rel=\"nofollow noopener noreferrer\">here</a></p>\n<ul>\n<li>wake me up</li>\n<li>the nights</li>\n</ul>\n"},
+		{description: "raw html in markdown", model: &models.Blog{ContentFormat: models.FormatMarkdown, Content: "<script>alert(1)</script><img src=x onerror=alert(1)>"}, html: "<p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;img src=x onerror=alert(1)&gt;</p>\n"},
+		{description: "unsafe links", model: &models.Blog{ContentFormat: models.FormatMarkdown, Content: "[a](javascript:alert(1)) [b](data:text/html,x) [c](//evil.com) <javascript:alert(1)>"}, html: "<p>a b c &lt;javascript:alert(1)&gt;</p>\n"},
+		{description: "code", model: &models.Blog{ContentFormat: models.FormatMarkdown, Content: "`<b>` and\n\n```\n<i>\"quoted\"</i>\n```"}, html: "<p><code>&lt;b&gt;</code> and</p>\n<pre><code>&lt;i&gt;&#34;quoted&#34;&lt;/i&gt;\n</code></pre>\n"},
+	}
+
+	for i, tc := range tests {
+		render(tc.model)
+
+		assert.Equal(t, tc.html, tc.model.ContentHTML, "TEST [%v], failed.\n%s", i+1, tc.description)
+		assert.Equal(t, markup.Version, tc.model.RenderVersion, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_RenderStale(t *testing.T) {
+	mockBlogStore, _, _, ctx, mockBlogService := initializeTest(t)
+
+	stale := []*models.Blog{
+		{BlogID: "MSI8WKNSH9", Content: "avicii *left*", ContentFormat: models.FormatMarkdown},
+		{BlogID: "9SNVSH8K2M", Content: "blue orchids"},
+	}
+
+	mockBlogStore.EXPECT().GetStale(gomock.Any(), markup.Version, int64(renderBatchSize)).Return(stale, nil)
+	mockBlogStore.EXPECT().SetRendered(gomock.Any(), &models.Blog{BlogID: "MSI8WKNSH9", Content: "avicii *left*", ContentFormat: models.FormatMarkdown, ContentHTML: "<p>avicii <em>left</em></p>\n", RenderVersion: markup.Version}).Return(nil)
+	mockBlogStore.EXPECT().SetRendered(gomock.Any(), &models.Blog{BlogID: "9SNVSH8K2M", Content: "blue orchids", ContentHTML: "<p>blue orchids</p>\n", RenderVersion: markup.Version}).Return(errors.EntityNotFound{Entity: "stale blog", ID: "9SNVSH8K2M"})
+
+	assert.Equal(t, nil, mockBlogService.RenderStale(ctx), "TEST [1], failed.\nstale blogs rendered, and one updated since it was read")
+
+	mockBlogStore.EXPECT().GetStale(gomock.Any(), markup.Version, int64(renderBatchSize)).Return([]*models.Blog{{BlogID: "MSI8WKNSH9", Content: "avicii"}}, nil)
+	mockBlogStore.EXPECT().SetRendered(gomock.Any(), gomock.Any()).Return(errors.DBError{})
+
+	assert.Equal(t, errors.DBError{}, mockBlogService.RenderStale(ctx), "TEST [2], failed.\ndatabase error")
+}
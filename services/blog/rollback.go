@@ -0,0 +1,54 @@
+package blog
+
+import (
+	"strings"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+)
+
+// rollback compensates the writes of a blog that fails part way, as a blog is written to several stores
+// that cannot share a transaction. Each write registers the step that undoes it once it is done,
+// and the steps are run latest first.
+type rollback struct {
+	steps []step
+}
+
+type step struct {
+	name string
+	undo func(ctx *app.Context) error
+}
+
+// add registers the step that undoes a write.
+func (r *rollback) add(name string, undo func(ctx *app.Context) error) {
+	r.steps = append(r.steps, step{name: name, undo: undo})
+}
+
+// run undoes the writes and records the failure, returning the error that caused it.
+// The steps run on a detached context, so that a client going away does not leave the writes half done.
+// A step that fails is logged and does not stop the rest, what it leaves behind is named in the record.
+func (r *rollback) run(ctx *app.Context, operation, blogID string, cause error) error {
+	if len(r.steps) == 0 {
+		return cause
+	}
+
+	detached := ctx.Detach()
+	failed := make([]string, 0)
+
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		if err := r.steps[i].undo(detached); err != nil {
+			ctx.Logger.Errorf("cannot undo %s of blog %s: %s", r.steps[i].name, blogID, err.Error())
+			failed = append(failed, r.steps[i].name)
+		}
+	}
+
+	if len(failed) != 0 {
+		ctx.Logger.Errorf("%s of blog %s failed: %s, rolled back partially, left behind: %s",
+			operation, blogID, cause.Error(), strings.Join(failed, ", "))
+
+		return cause
+	}
+
+	ctx.Logger.Warnf("%s of blog %s failed: %s, rolled back", operation, blogID, cause.Error())
+
+	return cause
+}
@@ -0,0 +1,97 @@
+package blog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/textfilter"
+)
+
+// screen applies the content filter to the title, summary, content and tags of a new or updated blog,
+// masking the text matched by the rules that mask it. A tag matched by such a rule is dropped, as a masked tag means nothing.
+// The text of the blog is also checked by the spam heuristic.
+// It returns the strongest match, for the blog to be flagged once it is written, or an error if the filter rejects the blog.
+func (b blog) screen(model *models.Blog) (textfilter.Match, error) {
+	fields := []struct {
+		name string
+		text *string
+	}{
+		{name: "title", text: &model.Title},
+		{name: "summary", text: &model.Summary},
+		{name: "content", text: &model.Content},
+	}
+
+	var match textfilter.Match
+
+	for _, field := range fields {
+		text, m := b.contentFilter.Apply(*field.text)
+		if m.Policy == textfilter.Reject {
+			return m, errors.ContentRejected{Field: field.name, Rule: m.Rule}
+		}
+
+		*field.text = text
+		match = textfilter.Strongest(match, m)
+	}
+
+	tags := make([]string, 0, len(model.Tags))
+
+	for _, tag := range model.Tags {
+		text, m := b.contentFilter.Apply(tag)
+		if m.Policy == textfilter.Reject {
+			return m, errors.ContentRejected{Field: "tags", Rule: m.Rule}
+		}
+
+		match = textfilter.Strongest(match, m)
+
+		if text == tag {
+			tags = append(tags, tag)
+		}
+	}
+
+	if model.Tags != nil {
+		model.Tags = tags
+	}
+
+	m := b.contentFilter.Spam(strings.Join([]string{model.Title, model.Summary, model.Content}, "\n"))
+	if m.Policy == textfilter.Reject {
+		return m, errors.ContentRejected{Field: "content", Rule: m.Rule}
+	}
+
+	return textfilter.Strongest(match, m), nil
+}
+
+// flag files a report on a blog the content filter flagged, for a moderator to review.
+// The blog is written already, so a failure is logged alone. A blog with an open report of the filter is not reported again.
+func (b blog) flag(ctx *app.Context, blog *models.Blog, match textfilter.Match) {
+	if match.Policy != textfilter.Flag {
+		return
+	}
+
+	reason := models.ReasonOther
+	if strings.HasPrefix(match.Rule, "spam:") {
+		reason = models.ReasonSpam
+	}
+
+	err := b.reportStore.Create(ctx, &models.Report{
+		ID:         uuid.New().String(),
+		Kind:       models.ReportBlog,
+		BlogID:     blog.BlogID,
+		UserID:     blog.AccountID,
+		ReporterID: models.FilterReporter,
+		Reason:     reason,
+		Details:    "flagged by the content filter: " + match.Rule,
+		Status:     models.ReportOpen,
+		CreatedOn:  time.Now(),
+	})
+
+	switch err.(type) {
+	case nil, errors.EntityAlreadyExists:
+	default:
+		ctx.Logger.Errorf("cannot flag blog %s for review: %s", blog.BlogID, err.Error())
+	}
+}
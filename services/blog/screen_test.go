@@ -0,0 +1,110 @@
+package blog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/textfilter"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func newFilter(t *testing.T) *textfilter.Filter {
+	f, err := textfilter.New(textfilter.RuleSet{
+		Rules: []textfilter.Rule{
+			{Name: "scams", Patterns: []string{`(?i)free\s+bitcoin`}, Policy: textfilter.Reject},
+			{Name: "contact", Words: []string{"whatsapp"}, Policy: textfilter.Flag},
+			{Name: "profanity", Words: []string{"damn"}, Policy: textfilter.Mask},
+		},
+		Spam: textfilter.Spam{Policy: textfilter.Flag, MinLinks: 2, MaxLinkDensity: 0.2, MaxRepeats: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_Screen(t *testing.T) {
+	service := blog{contentFilter: newFilter(t)}
+
+	links := "see http://a.example http://b.example http://c.example now"
+	repeated := strings.Repeat("buy my book now\n", 4)
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		output      *models.Blog
+		match       textfilter.Match
+		err         error
+	}{
+		{description: "clean blog", model: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{"#music"}}, output: &models.Blog{Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{"#music"}}},
+		{description: "masked words", model: &models.Blog{Title: "Damn", Summary: "a blog", Content: "it is damn good"}, output: &models.Blog{Title: "****", Summary: "a blog", Content: "it is **** good"}, match: textfilter.Match{Rule: "profanity", Policy: textfilter.Mask}},
+		{description: "masked tags are dropped", model: &models.Blog{Title: "music", Summary: "a blog", Content: "songs", Tags: []string{"#damn", "#music"}}, output: &models.Blog{Title: "music", Summary: "a blog", Content: "songs", Tags: []string{"#music"}}, match: textfilter.Match{Rule: "profanity", Policy: textfilter.Mask}},
+		{description: "flagged blog", model: &models.Blog{Title: "music", Summary: "a blog", Content: "damn, text me on WhatsApp"}, output: &models.Blog{Title: "music", Summary: "a blog", Content: "****, text me on WhatsApp"}, match: textfilter.Match{Rule: "contact", Policy: textfilter.Flag}},
+		{description: "rejected content", model: &models.Blog{Title: "music", Summary: "a blog", Content: "get FREE  bitcoin"}, match: textfilter.Match{Rule: "scams", Policy: textfilter.Reject}, err: errors.ContentRejected{Field: "content", Rule: "scams"}},
+		{description: "rejected tag", model: &models.Blog{Title: "music", Summary: "a blog", Content: "songs", Tags: []string{"#free bitcoin"}}, match: textfilter.Match{Rule: "scams", Policy: textfilter.Reject}, err: errors.ContentRejected{Field: "tags", Rule: "scams"}},
+		{description: "dense links", model: &models.Blog{Title: "links", Summary: "a blog", Content: links}, output: &models.Blog{Title: "links", Summary: "a blog", Content: links}, match: textfilter.Match{Rule: textfilter.SpamLinks, Policy: textfilter.Flag}},
+		{description: "repeated lines", model: &models.Blog{Title: "offer", Summary: "a blog", Content: repeated}, output: &models.Blog{Title: "offer", Summary: "a blog", Content: repeated}, match: textfilter.Match{Rule: textfilter.SpamRepeats, Policy: textfilter.Flag}},
+		{description: "repeated words", model: &models.Blog{Title: "offer", Summary: "a blog", Content: "buy buy buy buy"}, output: &models.Blog{Title: "offer", Summary: "a blog", Content: "buy buy buy buy"}, match: textfilter.Match{Rule: textfilter.SpamRepeats, Policy: textfilter.Flag}},
+	}
+
+	for i, tc := range tests {
+		match, err := service.screen(tc.model)
+
+		assert.Equal(t, tc.match, match, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.output, tc.model, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+func TestBlog_ScreenWithoutFilter(t *testing.T) {
+	model := &models.Blog{Title: "free bitcoin", Summary: "damn", Content: "buy buy buy buy"}
+
+	match, err := blog{}.screen(model)
+
+	assert.Equal(t, textfilter.Match{}, match, "TEST, failed.\nno filter")
+	assert.Equal(t, nil, err, "TEST, failed.\nno filter")
+	assert.Equal(t, &models.Blog{Title: "free bitcoin", Summary: "damn", Content: "buy buy buy buy"}, model, "TEST, failed.\nno filter")
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_Flag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReportStore := stores.NewMockReport(ctrl)
+	service := blog{reportStore: mockReportStore}
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	var filed []*models.Report
+
+	mockReportStore.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Report) error {
+		filed = append(filed, r)
+		return nil
+	})
+	mockReportStore.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Report) error {
+		filed = append(filed, r)
+		return errors.EntityAlreadyExists{Entity: "report"}
+	})
+
+	blog := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2}
+
+	service.flag(ctx, blog, textfilter.Match{Rule: textfilter.SpamLinks, Policy: textfilter.Flag})
+	service.flag(ctx, blog, textfilter.Match{Rule: "contact", Policy: textfilter.Flag})
+	service.flag(ctx, blog, textfilter.Match{Rule: "profanity", Policy: textfilter.Mask})
+	service.flag(ctx, blog, textfilter.Match{})
+
+	if assert.Len(t, filed, 2, "TEST, failed.\nflagged blogs are reported") {
+		assert.Equal(t, models.Report{ID: filed[0].ID, Kind: models.ReportBlog, BlogID: "MSI8WKNSH9", UserID: 2, ReporterID: models.FilterReporter, Reason: models.ReasonSpam, Details: "flagged by the content filter: spam:links", Status: models.ReportOpen, CreatedOn: filed[0].CreatedOn}, *filed[0], "TEST, failed.\nspam report")
+		assert.Equal(t, models.ReasonOther, filed[1].Reason, "TEST, failed.\nreport of a rule")
+	}
+}
@@ -0,0 +1,181 @@
+package blog
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+const (
+	maxSlugLength   = 60
+	defaultSlug     = "blog"
+	maxSlugAttempts = 3 // slugs taken by concurrent writes of the same author are retried
+)
+
+// slugify derives the slug of a title: lower case ASCII letters and digits, words separated by hyphens.
+// Accents are dropped from letters, and other characters separate words. A long slug is cut after its last whole word.
+// A title without any letter or digit left has the default slug.
+func slugify(title string) string {
+	var b strings.Builder
+
+	hyphen := false
+
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() != 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+			hyphen = false
+		default:
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+
+	if len(slug) > maxSlugLength {
+		cut := slug[:maxSlugLength]
+		if i := strings.LastIndexByte(cut, '-'); slug[maxSlugLength] != '-' && i > 0 {
+			cut = cut[:i]
+		}
+
+		slug = strings.TrimSuffix(cut, "-")
+	}
+
+	if slug == "" {
+		return defaultSlug
+	}
+
+	return slug
+}
+
+// slugFor picks the slug of a blog from its title, unique among the current and old slugs of the blogs of its author.
+// A blog keeps its slug while its title gives the same slug, and can take back one of its old slugs.
+// A slug that is taken gets the lowest free numeric suffix, found among the taken slugs read in a single query.
+func (b blog) slugFor(ctx *app.Context, model, old *models.Blog) (string, error) {
+	base := slugify(model.Title)
+
+	if old != nil && old.Slug != "" && slugify(old.Title) == base {
+		return old.Slug, nil
+	}
+
+	taken, err := b.blogStore.GetSlugs(ctx, model.AccountID, base)
+	if err != nil {
+		return "", err
+	}
+
+	// one of the first len(taken)+1 slugs is free
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			slug = base + "-" + strconv.Itoa(n)
+		}
+
+		if owner, ok := taken[slug]; !ok || owner == model.BlogID {
+			return slug, nil
+		}
+	}
+}
+
+// setSlug sets the slug of a new or updated blog. The slug a blog leaves behind is kept as an old slug,
+// so that links to it are redirected to the current one.
+func (b blog) setSlug(ctx *app.Context, model, old *models.Blog) error {
+	slug, err := b.slugFor(ctx, model, old)
+	if err != nil {
+		return err
+	}
+
+	model.Slug = slug
+
+	if old == nil {
+		return nil
+	}
+
+	model.OldSlugs = make([]string, 0, len(old.OldSlugs)+1)
+
+	for _, s := range append(old.OldSlugs, old.Slug) {
+		if s != "" && s != slug && !contains(model.OldSlugs, s) {
+			model.OldSlugs = append(model.OldSlugs, s)
+		}
+	}
+
+	return nil
+}
+
+// write writes a blog through the store, picking its slug again when a concurrent write of the author takes it first.
+func (b blog) write(ctx *app.Context, model, old *models.Blog,
+	write func(c *app.Context, model *models.Blog) (*models.Blog, error)) (*models.Blog, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := write(ctx, model)
+		if _, taken := err.(errors.EntityAlreadyExists); !taken || attempt == maxSlugAttempts {
+			return res, err
+		}
+
+		if err = b.setSlug(ctx, model, old); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBySlug retrieves a blog of an author by its slug. A blog found by one of its old slugs is returned as well,
+// and the caller redirects to its current slug.
+func (b blog) GetBySlug(ctx *app.Context, username, slug string) (*models.Blog, error) {
+	if username == "" {
+		return nil, errors.MissingParam{Param: "username"}
+	}
+
+	if slug == "" {
+		return nil, errors.MissingParam{Param: "slug"}
+	}
+
+	account, err := b.accountStore.Get(ctx, &models.Account{User: models.User{UserName: username}})
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		return nil, errors.EntityNotFound{Entity: "blog", ID: slug}
+	default:
+		return nil, err
+	}
+
+	blog, err := b.blogStore.GetBySlug(ctx, account.ID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// blogs that are not visible do not exist for the viewer
+	visible, err := b.visible(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	if !visible {
+		return nil, errors.EntityNotFound{Entity: "blog", ID: slug}
+	}
+
+	rendered(blog)
+	noticed(blog)
+
+	return blog, nil
+}
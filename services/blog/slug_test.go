@@ -0,0 +1,142 @@
+package blog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		slug  string
+	}{
+		{title: "Music", slug: "music"},
+		{title: "  Avicii -- Wake Me Up!  ", slug: "avicii-wake-me-up"},
+		{title: "Crème Brûlée à la maison", slug: "creme-brulee-a-la-maison"},
+		{title: "Top 10 songs of 2021", slug: "top-10-songs-of-2021"},
+		{title: "音楽", slug: "blog"},
+		{title: "!!!", slug: "blog"},
+		{title: "a very long title that goes on and on about the many things a blog can be about", slug: "a-very-long-title-that-goes-on-and-on-about-the-many-things"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.slug, slugify(tc.title), "TEST [%v], failed.\n%s", i+1, tc.title)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_SetSlug(t *testing.T) {
+	mockBlogStore, _, _, ctx, _ := initializeTest(t)
+	service := blog{blogStore: mockBlogStore}
+
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "music").Return(map[string]string{"music": "MSI8WKNSH9", "music-2": "9SNVSH8K2M", "music-4": "KN78FH8K2M"}, nil).AnyTimes()
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "songs").Return(nil, errors.DBError{}).AnyTimes()
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		old         *models.Blog
+		slug        string
+		oldSlugs    []string
+		err         error
+	}{
+		{description: "new blog with a taken title", model: &models.Blog{AccountID: 2, Title: "Music"}, slug: "music-3"},
+		{description: "title that gives the same slug", model: &models.Blog{BlogID: "ABK7SH2V37", AccountID: 2, Title: "MUSIC!"}, old: &models.Blog{BlogID: "ABK7SH2V37", Title: "music", Slug: "music-3"}, slug: "music-3", oldSlugs: []string{}},
+		{description: "renamed blog keeps its slug as an old one", model: &models.Blog{BlogID: "ABK7SH2V37", AccountID: 2, Title: "Music"}, old: &models.Blog{BlogID: "ABK7SH2V37", Title: "edm", Slug: "edm", OldSlugs: []string{"house"}}, slug: "music-3", oldSlugs: []string{"house", "edm"}},
+		{description: "renamed blog takes back its old slug", model: &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "Music"}, old: &models.Blog{BlogID: "MSI8WKNSH9", Title: "edm", Slug: "edm", OldSlugs: []string{"music"}}, slug: "music", oldSlugs: []string{"edm"}},
+		{description: "error in finding the slug", model: &models.Blog{AccountID: 2, Title: "Songs"}, err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		err := service.setSlug(ctx, tc.model, tc.old)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+		assert.Equal(t, tc.slug, tc.model.Slug, "TEST [%v], failed.\n%s", i+1, tc.description)
+		assert.Equal(t, tc.oldSlugs, tc.model.OldSlugs, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBlog_WriteSlugTaken(t *testing.T) {
+	mockBlogStore, _, _, ctx, _ := initializeTest(t)
+	service := blog{blogStore: mockBlogStore}
+
+	model := &models.Blog{AccountID: 2, Title: "music", Slug: "music"}
+	taken := errors.EntityAlreadyExists{Entity: "blog", ValueType: "slug", Value: "music"}
+
+	// the slug is taken by a concurrent write after it was picked, and picked again
+	gomock.InOrder(
+		mockBlogStore.EXPECT().Create(gomock.Any(), model).Return(nil, taken),
+		mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "music").Return(map[string]string{"music": "MSI8WKNSH9"}, nil),
+		mockBlogStore.EXPECT().Create(gomock.Any(), model).DoAndReturn(func(_ *app.Context, m *models.Blog) (*models.Blog, error) {
+			return m, nil
+		}),
+	)
+
+	res, err := service.write(ctx, model, nil, mockBlogStore.Create)
+
+	assert.Equal(t, nil, err, "TEST [1], failed.\nslug taken by a concurrent write")
+
+	if assert.NotNil(t, res, "TEST [1], failed.\nslug taken by a concurrent write") {
+		assert.Equal(t, "music-2", res.Slug, "TEST [1], failed.\nslug taken by a concurrent write")
+	}
+
+	// the write gives up once every attempt found the slug taken
+	mockBlogStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, taken).Times(maxSlugAttempts)
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "music").Return(map[string]string{}, nil).Times(maxSlugAttempts - 1)
+
+	_, err = service.write(ctx, &models.Blog{AccountID: 2, Title: "music", Slug: "music"}, nil, mockBlogStore.Create)
+
+	assert.Equal(t, taken, err, "TEST [2], failed.\nslug taken at every attempt")
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetBySlug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockAccountStore := stores.NewMockAccount(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), mockAccountStore, stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), stores.NewMockFollow(ctrl), stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	res := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Content: "avicii", Slug: "music", OldSlugs: []string{"edm"}}
+	draft := &models.Blog{BlogID: "9SNVSH8K2M", AccountID: 2, Title: "flowers", Content: "orchids", Slug: "flowers", Status: models.StatusDraft}
+
+	mockAccountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "jaiss"}}).Return(&models.Account{User: models.User{ID: 2, UserName: "jaiss"}}, nil).AnyTimes()
+	mockAccountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "nobody"}}).Return(nil, errors.EntityNotFound{Entity: "account"})
+	mockBlogStore.EXPECT().GetBySlug(gomock.Any(), int64(2), "edm").Return(res, nil)
+	mockBlogStore.EXPECT().GetBySlug(gomock.Any(), int64(2), "flowers").Return(draft, nil).Times(2)
+	mockBlogStore.EXPECT().GetBySlug(gomock.Any(), int64(2), "house").Return(nil, errors.EntityNotFound{Entity: "blog", ID: "house"})
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		username    string
+		slug        string
+		output      *models.Blog
+		err         error
+	}{
+		{description: "blog by an old slug", ctx: ctx, username: "jaiss", slug: "edm", output: res},
+		{description: "unknown author", ctx: ctx, username: "nobody", slug: "edm", err: errors.EntityNotFound{Entity: "blog", ID: "edm"}},
+		{description: "unknown slug", ctx: ctx, username: "jaiss", slug: "house", err: errors.EntityNotFound{Entity: "blog", ID: "house"}},
+		{description: "draft of another account", ctx: withViewer(ctx, 3), username: "jaiss", slug: "flowers", err: errors.EntityNotFound{Entity: "blog", ID: "flowers"}},
+		{description: "draft of the viewer", ctx: withViewer(ctx, 2), username: "jaiss", slug: "flowers", output: draft},
+		{description: "missing slug", ctx: ctx, username: "jaiss", err: errors.MissingParam{Param: "slug"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetBySlug(tc.ctx, tc.username, tc.slug)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -0,0 +1,237 @@
+package blog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/imaging"
+)
+
+const (
+	uploadExpiry     = 15 * time.Minute // validity of a presigned URL, and the time an upload must be confirmed in
+	attachExpiry     = 24 * time.Hour   // time a confirmed upload must be attached to a blog in
+	expireBatchSize  = 100
+	uploadEntityName = "upload"
+)
+
+// uploadTypes maps the content types accepted for presigned uploads to the extensions of their files.
+//
+//nolint:gochecknoglobals // read-only set of content types
+var uploadTypes = map[string]string{"image/jpeg": ".jpg", "image/png": ".png", "image/gif": ".gif", "image/webp": ".webp"}
+
+// CreateUpload issues a presigned URL for the account to upload a picture of the content type and size to.
+// The file is named after the account, so that the URL cannot be used to overwrite the files of others.
+func (b blog) CreateUpload(ctx *app.Context, model *models.Upload) (*models.Upload, error) {
+	accountID := viewerID(ctx)
+	if accountID == 0 {
+		return nil, errors.AuthError{Msg: "login to upload images"}
+	}
+
+	ext, ok := uploadTypes[model.ContentType]
+	if !ok {
+		return nil, errors.UnsupportedImage{Name: uploadEntityName, Format: model.ContentType}
+	}
+
+	limits := imaging.NewLimits(ctx.Config)
+
+	switch {
+	case model.Size <= 0:
+		return nil, errors.InvalidParam{Param: "size"}
+	case model.Size > limits.MaxFileSize:
+		return nil, errors.ImageTooLarge{Name: uploadEntityName, Size: model.Size, Limit: limits.MaxFileSize}
+	}
+
+	now := time.Now()
+	id := generateNewID()
+
+	u := &models.Upload{
+		ID:          id,
+		AccountID:   accountID,
+		Name:        fmt.Sprintf("%v_upload_%v%s", accountID, id, ext),
+		ContentType: model.ContentType,
+		Size:        model.Size,
+		Status:      models.UploadPending,
+		CreatedOn:   now,
+		ExpiresAt:   now.Add(uploadExpiry),
+	}
+
+	url, headers, err := b.imageStore.PresignUpload(ctx, u.Name, u.ContentType, u.Size, uploadExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = b.uploadStore.Create(ctx, u); err != nil {
+		return nil, err
+	}
+
+	u.URL, u.Headers = url, headers
+
+	return u, nil
+}
+
+// ConfirmUpload checks the uploaded file of an upload against the rules of uploaded images, and processes it
+// into an image that can be attached to a blog. A file that is rejected can be uploaded again until the upload expires.
+// Confirming an upload again returns it as it is.
+func (b blog) ConfirmUpload(ctx *app.Context, id string) (*models.Upload, error) {
+	pending, err := b.uploadStore.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the uploads of others are as good as missing
+	if pending.AccountID != viewerID(ctx) || time.Now().After(pending.ExpiresAt) {
+		return nil, errors.EntityNotFound{Entity: uploadEntityName, ID: id}
+	}
+
+	if pending.Status != models.UploadPending {
+		return pending, nil
+	}
+
+	limits := imaging.NewLimits(ctx.Config)
+
+	data, err := b.imageStore.Download(ctx, pending.Name, limits.MaxFileSize)
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		return nil, errors.EntityNotFound{Entity: "uploaded file", ID: id}
+	default:
+		return nil, err
+	}
+
+	processed := newUpload(pending.AccountID, pending.Name, openData(data))
+
+	if err = processed.validate(limits, int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	images, err := b.storeImages(ctx, []upload{processed})
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := b.uploadStore.Confirm(ctx, id, &images[0], time.Now().Add(attachExpiry))
+	if err != nil {
+		// confirmed concurrently, or expired meanwhile
+		if err := b.releaseImages(ctx, images); err != nil {
+			ctx.Logger.Errorf("cannot delete renditions of upload %s: %s", id, err.Error())
+		}
+
+		return nil, err
+	}
+
+	// the renditions are all that is served
+	if err := b.imageStore.DeleteBulk(ctx, []string{pending.Name}); err != nil {
+		ctx.Logger.Errorf("cannot delete uploaded file of upload %s: %s", id, err.Error())
+	}
+
+	return confirmed, nil
+}
+
+// ExpireUploads deletes the uploads that were not confirmed or attached in time, along with their files.
+func (b blog) ExpireUploads(ctx *app.Context) error {
+	now := time.Now()
+
+	uploads, err := b.uploadStore.GetExpired(ctx, now, expireBatchSize)
+	if err != nil {
+		return err
+	}
+
+	expiredCount := 0
+
+	for _, u := range uploads {
+		// an upload attached meanwhile is gone already, and its files are left alone
+		expired, err := b.uploadStore.Expire(ctx, u.ID, now)
+		if err != nil {
+			if _, ok := err.(errors.EntityNotFound); !ok {
+				ctx.Logger.Errorf("cannot expire upload %s: %s", u.ID, err.Error())
+			}
+
+			continue
+		}
+
+		expiredCount++
+
+		if err := b.imageStore.DeleteBulk(ctx, []string{expired.Name}); err != nil {
+			ctx.Logger.Errorf("cannot delete files of expired upload %s: %s", u.ID, err.Error())
+		}
+
+		if expired.Image == nil {
+			continue
+		}
+
+		if err := b.releaseImages(ctx, []models.Image{*expired.Image}); err != nil {
+			ctx.Logger.Errorf("cannot delete image of expired upload %s: %s", u.ID, err.Error())
+		}
+	}
+
+	if expiredCount != 0 {
+		ctx.Logger.Infof("expired %v uploads", expiredCount)
+	}
+
+	return nil
+}
+
+// attachUploads takes the confirmed uploads of an account, for their images to be attached to a blog.
+// The uploads taken are given back if any of them cannot be taken.
+func (b blog) attachUploads(ctx *app.Context, accountID int64, ids []string) ([]*models.Upload, error) {
+	uploads := make([]*models.Upload, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			b.releaseUploads(ctx, uploads)
+			return nil, errors.InvalidParam{Param: "uploads"}
+		}
+
+		seen[id] = true
+
+		u, err := b.uploadStore.Attach(ctx, id, accountID)
+		if err != nil {
+			b.releaseUploads(ctx, uploads)
+
+			if _, ok := err.(errors.EntityNotFound); ok {
+				return nil, errors.EntityNotFound{Entity: "confirmed upload", ID: id}
+			}
+
+			return nil, err
+		}
+
+		uploads = append(uploads, u)
+	}
+
+	return uploads, nil
+}
+
+// releaseUploads gives back the uploads taken for a blog that could not be written.
+// An upload that cannot be given back leaves its files orphaned in the storage.
+func (b blog) releaseUploads(ctx *app.Context, uploads []*models.Upload) {
+	for _, u := range uploads {
+		if err := b.uploadStore.Create(ctx, u); err != nil {
+			ctx.Logger.Errorf("cannot release upload %s: %s", u.ID, err.Error())
+		}
+	}
+}
+
+// releaseUploadsStep returns the step that gives back the uploads taken for a blog.
+func (b blog) releaseUploadsStep(uploads []*models.Upload) func(ctx *app.Context) error {
+	return func(ctx *app.Context) error {
+		b.releaseUploads(ctx, uploads)
+		return nil
+	}
+}
+
+func uploadedImages(uploads []*models.Upload) []models.Image {
+	images := make([]models.Image, 0, len(uploads))
+
+	for _, u := range uploads {
+		if u.Image != nil {
+			images = append(images, *u.Image)
+		}
+	}
+
+	return images
+}
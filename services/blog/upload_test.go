@@ -0,0 +1,213 @@
+package blog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func initializeUploadTest(t *testing.T) (*stores.MockBlog, *stores.MockImage, *stores.MockUpload, *app.Context, blog) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockImageStore := stores.NewMockImage(ctrl)
+	mockUploadStore := stores.NewMockUpload(ctrl)
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	service := blog{
+		blogStore:     mockBlogStore,
+		tagService:    services.NewMockTag(ctrl),
+		imageStore:    mockImageStore,
+		searchIndex:   stores.NewMockSearchIndex(ctrl),
+		revisionStore: stores.NewMockRevision(ctrl),
+		uploadStore:   mockUploadStore,
+		blobStore:     stores.NewMockBlob(ctrl),
+	}
+
+	return mockBlogStore, mockImageStore, mockUploadStore, ctx, service
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_CreateUpload(t *testing.T) {
+	_, mockImageStore, mockUploadStore, ctx, service := initializeUploadTest(t)
+
+	mockImageStore.EXPECT().PresignUpload(gomock.Any(), gomock.Any(), "image/png", int64(1024), uploadExpiry).Return("https://picshot.s3.ap-south-1.amazonaws.com/2_upload.png?X-Amz-Signature=abc", map[string]string{"Content-Type": "image/png"}, nil)
+	mockUploadStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockImageStore.EXPECT().PresignUpload(gomock.Any(), gomock.Any(), "image/jpeg", int64(1024), uploadExpiry).Return("", nil, errors.Unsupported{Operation: "presigned upload to the local image storage"})
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		input       *models.Upload
+		err         error
+	}{
+		{description: "presigned url issued", ctx: withViewer(ctx, 2), input: &models.Upload{ContentType: "image/png", Size: 1024}},
+		{description: "storage that cannot presign urls", ctx: withViewer(ctx, 2), input: &models.Upload{ContentType: "image/jpeg", Size: 1024}, err: errors.Unsupported{Operation: "presigned upload to the local image storage"}},
+		{description: "anonymous upload", ctx: ctx, input: &models.Upload{ContentType: "image/png", Size: 1024}, err: errors.AuthError{Msg: "login to upload images"}},
+		{description: "unsupported content type", ctx: withViewer(ctx, 2), input: &models.Upload{ContentType: "image/svg+xml", Size: 1024}, err: errors.UnsupportedImage{Name: "upload", Format: "image/svg+xml"}},
+		{description: "missing size", ctx: withViewer(ctx, 2), input: &models.Upload{ContentType: "image/png"}, err: errors.InvalidParam{Param: "size"}},
+		{description: "file too large", ctx: withViewer(ctx, 2), input: &models.Upload{ContentType: "image/png", Size: 11 << 20}, err: errors.ImageTooLarge{Name: "upload", Size: 11 << 20, Limit: 10 << 20}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.CreateUpload(tc.ctx, tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if err == nil {
+			assert.Equal(t, models.UploadPending, output.Status, "TEST [%v], failed.\n%s", i+1, tc.description)
+			assert.Equal(t, int64(2), output.AccountID, "TEST [%v], failed.\n%s", i+1, tc.description)
+			assert.Regexp(t, `^2_upload_.+\.png$`, output.Name, "TEST [%v], failed.\n%s", i+1, tc.description)
+			assert.NotEmpty(t, output.URL, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_ConfirmUpload(t *testing.T) {
+	_, mockImageStore, mockUploadStore, ctx, service := initializeUploadTest(t)
+	author := withViewer(ctx, 2)
+
+	mockBlobStore := stores.NewMockBlob(gomock.NewController(t))
+	service.blobStore = mockBlobStore
+
+	expiresAt := time.Now().Add(time.Minute)
+	pending := func(id string) *models.Upload {
+		return &models.Upload{ID: id, AccountID: 2, Name: "2_upload_" + id + ".png", ContentType: "image/png", Size: 100, Status: models.UploadPending, ExpiresAt: expiresAt}
+	}
+	confirmed := &models.Upload{ID: "u5", AccountID: 2, Status: models.UploadConfirmed, Image: &models.Image{ID: "2_a"}, ExpiresAt: expiresAt}
+
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u1").Return(pending("u1"), nil)
+	mockImageStore.EXPECT().Download(gomock.Any(), "2_upload_u1.png", int64(10<<20)).Return(encodePNG(t, 40, 30), nil)
+	mockBlobStore.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(nil, errors.EntityNotFound{Entity: "blob"})
+	mockBlobStore.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, b *models.Blob) (*models.Blob, error) { return b, nil })
+	mockImageStore.EXPECT().URL(gomock.Any()).Return("http://localhost:8000/images/2_a.jpg").Times(3)
+	mockImageStore.EXPECT().Upload(gomock.Any(), gomock.Any(), "image/jpeg", gomock.Any()).Return(nil).Times(3)
+	mockUploadStore.EXPECT().Confirm(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(confirmed, nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_upload_u1.png"}).Return(nil)
+
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u2").Return(pending("u2"), nil)
+	mockImageStore.EXPECT().Download(gomock.Any(), "2_upload_u2.png", int64(10<<20)).Return(nil, errors.EntityNotFound{Entity: "file", ID: "2_upload_u2.png"})
+
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u3").Return(pending("u3"), nil)
+	mockImageStore.EXPECT().Download(gomock.Any(), "2_upload_u3.png", int64(10<<20)).Return([]byte("<svg></svg>"), nil)
+
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u4").Return(&models.Upload{ID: "u4", AccountID: 2, Status: models.UploadPending, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u5").Return(confirmed, nil).Times(2)
+	mockUploadStore.EXPECT().Get(gomock.Any(), "u6").Return(nil, errors.EntityNotFound{Entity: "upload", ID: "u6"})
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		id          string
+		output      *models.Upload
+		err         error
+	}{
+		{description: "uploaded picture processed", ctx: author, id: "u1", output: confirmed},
+		{description: "file not uploaded", ctx: author, id: "u2", err: errors.EntityNotFound{Entity: "uploaded file", ID: "u2"}},
+		{description: "file that is not a picture", ctx: author, id: "u3", err: errors.UnsupportedImage{Name: "2_upload_u3.png", Format: "text/plain; charset=utf-8"}},
+		{description: "expired upload", ctx: author, id: "u4", err: errors.EntityNotFound{Entity: "upload", ID: "u4"}},
+		{description: "upload confirmed already", ctx: author, id: "u5", output: confirmed},
+		{description: "upload of another account", ctx: withViewer(ctx, 3), id: "u5", err: errors.EntityNotFound{Entity: "upload", ID: "u5"}},
+		{description: "missing upload", ctx: author, id: "u6", err: errors.EntityNotFound{Entity: "upload", ID: "u6"}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.ConfirmUpload(tc.ctx, tc.id)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBlog_ExpireUploads(t *testing.T) {
+	_, mockImageStore, mockUploadStore, ctx, service := initializeUploadTest(t)
+
+	mockBlobStore := stores.NewMockBlob(gomock.NewController(t))
+	service.blobStore = mockBlobStore
+
+	pending := &models.Upload{ID: "u1", Name: "2_upload_u1.png", Status: models.UploadPending}
+	confirmed := &models.Upload{ID: "u2", Name: "2_upload_u2.png", Status: models.UploadConfirmed, Image: &models.Image{
+		ID:        "2_b",
+		Hash:      "bbb",
+		Thumbnail: models.Rendition{URL: "http://localhost:8000/images/2_b_thumbnail.jpg"},
+		Medium:    models.Rendition{URL: "http://localhost:8000/images/2_b_medium.jpg"},
+		Full:      models.Rendition{URL: "http://localhost:8000/images/2_b_full.jpg"},
+	}}
+	attached := &models.Upload{ID: "u3", Name: "2_upload_u3.png", Status: models.UploadConfirmed}
+
+	mockUploadStore.EXPECT().GetExpired(gomock.Any(), gomock.Any(), int64(expireBatchSize)).Return([]*models.Upload{pending, confirmed, attached}, nil)
+	mockUploadStore.EXPECT().Expire(gomock.Any(), "u1", gomock.Any()).Return(pending, nil)
+	mockUploadStore.EXPECT().Expire(gomock.Any(), "u2", gomock.Any()).Return(confirmed, nil)
+	mockUploadStore.EXPECT().Expire(gomock.Any(), "u3", gomock.Any()).Return(nil, errors.EntityNotFound{Entity: "upload", ID: "u3"})
+
+	// the files of an upload attached meanwhile are left alone
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_upload_u1.png"}).Return(nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_upload_u2.png"}).Return(errors.DBError{})
+
+	// the renditions of the image are deleted once no other image shows them
+	mockBlobStore.EXPECT().Release(gomock.Any(), "bbb").Return(&models.Blob{Hash: "bbb", Image: *confirmed.Image}, nil)
+	mockBlobStore.EXPECT().Delete(gomock.Any(), "bbb").Return(nil)
+	mockImageStore.EXPECT().DeleteBulk(gomock.Any(), []string{"2_b_thumbnail.jpg", "2_b_medium.jpg", "2_b_full.jpg"}).Return(nil)
+
+	assert.NoError(t, service.ExpireUploads(ctx))
+
+	mockUploadStore.EXPECT().GetExpired(gomock.Any(), gomock.Any(), int64(expireBatchSize)).Return(nil, errors.DBError{})
+
+	assert.Equal(t, errors.DBError{}, service.ExpireUploads(ctx))
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_CreateWithUploads(t *testing.T) {
+	mockBlogStore, _, mockUploadStore, ctx, service := initializeUploadTest(t)
+	author := withViewer(ctx, 2)
+
+	image := models.Image{ID: "2_a", Full: models.Rendition{URL: "http://localhost:8000/images/2_a_full.jpg"}}
+	upload := &models.Upload{ID: "u1", AccountID: 2, Status: models.UploadConfirmed, Image: &image}
+
+	mockBlogStore.EXPECT().GetSlugs(gomock.Any(), int64(2), "memories").Return(map[string]string{}, nil).Times(3)
+
+	// the upload is given back when the blog cannot be written
+	mockUploadStore.EXPECT().Attach(gomock.Any(), "u1", int64(2)).Return(upload, nil)
+	mockBlogStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.DBError{})
+	mockUploadStore.EXPECT().Create(gomock.Any(), upload).Return(nil)
+
+	// uploads taken before a missing one are given back
+	mockUploadStore.EXPECT().Attach(gomock.Any(), "u1", int64(2)).Return(upload, nil)
+	mockUploadStore.EXPECT().Attach(gomock.Any(), "u2", int64(2)).Return(nil, errors.EntityNotFound{Entity: "upload", ID: "u2"})
+	mockUploadStore.EXPECT().Create(gomock.Any(), upload).Return(nil)
+
+	tests := []struct {
+		description string
+		uploads     []string
+		err         error
+	}{
+		{description: "blog that cannot be written", uploads: []string{"u1"}, err: errors.DBError{}},
+		{description: "upload that is not confirmed", uploads: []string{"u1", "u2"}, err: errors.EntityNotFound{Entity: "confirmed upload", ID: "u2"}},
+		{description: "upload listed twice", uploads: []string{"u3", "u3"}, err: errors.InvalidParam{Param: "uploads"}},
+	}
+
+	mockUploadStore.EXPECT().Attach(gomock.Any(), "u3", int64(2)).Return(&models.Upload{ID: "u3", Image: &image}, nil)
+	mockUploadStore.EXPECT().Create(gomock.Any(), &models.Upload{ID: "u3", Image: &image}).Return(nil)
+
+	for i, tc := range tests {
+		model := &models.Blog{Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Uploads: tc.uploads}
+
+		output, err := service.Create(author, model, nil)
+
+		assert.Nil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -0,0 +1,105 @@
+package blog
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+)
+
+// setVisibility validates the visibility of a new or updated blog.
+// A blog without a visibility keeps the visibility of its old version, and a new blog is public.
+func setVisibility(model, old *models.Blog) error {
+	switch model.Visibility {
+	case models.VisibilityPublic, models.VisibilityUnlisted, models.VisibilityFollowers, models.VisibilityPrivate:
+		return nil
+	case "":
+		model.Visibility = models.VisibilityPublic
+		if old != nil && old.Visibility != "" {
+			model.Visibility = old.Visibility
+		}
+
+		return nil
+	default:
+		return errors.InvalidParam{Param: "visibility"}
+	}
+}
+
+// visible tells if the viewer can see a blog they reach directly, by its id or slug.
+// An author sees all of their blogs. Others see published blogs that are public or unlisted,
+// and the blogs for followers of the authors they follow, unless either of them blocks the other.
+// Muted authors are hidden from listings alone. Blogs hidden by a moderator are seen by their author alone.
+func (b blog) visible(ctx *app.Context, blog *models.Blog) (bool, error) {
+	viewer := viewerID(ctx)
+
+	switch {
+	case blog.AccountID == viewer:
+		return true, nil
+	case !blog.IsPublished(), blog.Hidden:
+		return false, nil
+	}
+
+	if viewer != 0 {
+		blocked, err := b.blockStore.Exists(ctx, viewer, blog.AccountID)
+		if err != nil || blocked {
+			return false, err
+		}
+	}
+
+	switch blog.Visibility {
+	case "", models.VisibilityPublic, models.VisibilityUnlisted:
+		return true, nil
+	case models.VisibilityFollowers:
+		if viewer == 0 {
+			return false, nil
+		}
+
+		return b.followStore.Exists(ctx, viewer, blog.AccountID)
+	default:
+		return false, nil
+	}
+}
+
+// related resolves the accounts related to the viewer of a listing: the accounts they follow,
+// whose blogs for followers are listed to them, and the accounts hidden from them by a block or a mute.
+// A listing of the blogs of an author the viewer is blocked from is hidden as a whole, and mutes do not apply to it.
+func (b blog) related(ctx *app.Context, filter *models.BlogFilter) (hidden bool, err error) {
+	if filter.Viewer == 0 {
+		return false, nil
+	}
+
+	filter.Following, err = b.followStore.GetFollowees(ctx, filter.Viewer)
+	if err != nil {
+		return false, err
+	}
+
+	blocked, err := b.blockStore.GetRelated(ctx, filter.Viewer)
+	if err != nil {
+		return false, err
+	}
+
+	if filter.AccountID != 0 {
+		return containsID(blocked, filter.AccountID), nil
+	}
+
+	muted, err := b.muteStore.GetMuted(ctx, filter.Viewer)
+	if err != nil {
+		return false, err
+	}
+
+	filter.Hidden = blocked
+	for _, user := range muted {
+		filter.Hidden = append(filter.Hidden, user.ID)
+	}
+
+	return false, nil
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package blog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func TestSetVisibility(t *testing.T) {
+	tests := []struct {
+		description string
+		model       *models.Blog
+		old         *models.Blog
+		visibility  string
+		err         error
+	}{
+		{description: "new blog without a visibility", model: &models.Blog{}, visibility: models.VisibilityPublic},
+		{description: "new unlisted blog", model: &models.Blog{Visibility: models.VisibilityUnlisted}, visibility: models.VisibilityUnlisted},
+		{description: "update keeps the visibility", model: &models.Blog{}, old: &models.Blog{Visibility: models.VisibilityFollowers}, visibility: models.VisibilityFollowers},
+		{description: "update of a blog written before visibility", model: &models.Blog{}, old: &models.Blog{}, visibility: models.VisibilityPublic},
+		{description: "update changes the visibility", model: &models.Blog{Visibility: models.VisibilityPrivate}, old: &models.Blog{Visibility: models.VisibilityPublic}, visibility: models.VisibilityPrivate},
+		{description: "unknown visibility", model: &models.Blog{Visibility: "friends"}, visibility: "friends", err: errors.InvalidParam{Param: "visibility"}},
+	}
+
+	for i, tc := range tests {
+		err := setVisibility(tc.model, tc.old)
+
+		assert.Equal(t, tc.visibility, tc.model.Visibility, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_RetagVisibility(t *testing.T) {
+	_, mockTagService, _, ctx, _ := initializeTest(t)
+	service := blog{tagService: mockTagService}
+
+	public := &models.Blog{BlogID: "MSI8WKNSH9", Tags: []string{"#music"}, Visibility: models.VisibilityPublic}
+	private := &models.Blog{BlogID: "MSI8WKNSH9", Tags: []string{"#music"}, Visibility: models.VisibilityPrivate}
+	followers := &models.Blog{BlogID: "MSI8WKNSH9", Tags: []string{"#edm"}, Visibility: models.VisibilityFollowers}
+
+	mockTagService.EXPECT().RemoveBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(nil)
+	mockTagService.EXPECT().AddBlogID(gomock.Any(), "MSI8WKNSH9", []string{"#music"}).Return(nil)
+
+	tests := []struct {
+		description string
+		old         *models.Blog
+		res         *models.Blog
+	}{
+		{description: "public blog made private is taken off its tags", old: public, res: private},
+		{description: "private blog made public is added to its tags", old: private, res: public},
+		{description: "tags of a blog that is not listed are left alone", old: private, res: followers},
+	}
+
+	for i, tc := range tests {
+		err := service.retag(ctx, &rollback{}, tc.old, tc.res)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetByIDVisibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockFollowStore := stores.NewMockFollow(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), mockFollowStore, mockBlockStore, stores.NewMockMute(ctrl), stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+
+	blogs := map[string]*models.Blog{
+		models.VisibilityPublic:    {BlogID: "MSI8WKNSH9", AccountID: 2, Visibility: models.VisibilityPublic},
+		models.VisibilityUnlisted:  {BlogID: "9SNVSH8K2M", AccountID: 2, Visibility: models.VisibilityUnlisted},
+		models.VisibilityFollowers: {BlogID: "ABK7SH2V37", AccountID: 2, Visibility: models.VisibilityFollowers},
+		models.VisibilityPrivate:   {BlogID: "9SH7SH2V37", AccountID: 2, Visibility: models.VisibilityPrivate},
+	}
+
+	for _, blog := range blogs {
+		rendered(blog)
+		mockBlogStore.EXPECT().Get(gomock.Any(), &models.Blog{BlogID: blog.BlogID}).Return(blog, nil).AnyTimes()
+	}
+
+	mockBlockStore.EXPECT().Exists(gomock.Any(), gomock.Any(), int64(2)).DoAndReturn(func(_ *app.Context, id, _ int64) (bool, error) {
+		switch id {
+		case 6:
+			return true, nil
+		case 7:
+			return false, errors.DBError{}
+		default:
+			return false, nil
+		}
+	}).AnyTimes()
+
+	mockFollowStore.EXPECT().Exists(gomock.Any(), int64(3), int64(2)).Return(true, nil)
+	mockFollowStore.EXPECT().Exists(gomock.Any(), int64(4), int64(2)).Return(false, nil)
+	mockFollowStore.EXPECT().Exists(gomock.Any(), int64(5), int64(2)).Return(false, errors.DBError{})
+
+	notFound := func(visibility string) error {
+		return errors.EntityNotFound{Entity: "blog", ID: blogs[visibility].BlogID}
+	}
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		visibility  string
+		err         error
+	}{
+		{description: "public blog to anyone", ctx: ctx, visibility: models.VisibilityPublic},
+		{description: "unlisted blog by a direct link", ctx: ctx, visibility: models.VisibilityUnlisted},
+		{description: "blog for followers to a follower", ctx: withViewer(ctx, 3), visibility: models.VisibilityFollowers},
+		{description: "blog for followers to an account that does not follow", ctx: withViewer(ctx, 4), visibility: models.VisibilityFollowers, err: notFound(models.VisibilityFollowers)},
+		{description: "blog for followers to a logged out viewer", ctx: ctx, visibility: models.VisibilityFollowers, err: notFound(models.VisibilityFollowers)},
+		{description: "error in checking the follow", ctx: withViewer(ctx, 5), visibility: models.VisibilityFollowers, err: errors.DBError{}},
+		{description: "private blog to another account", ctx: withViewer(ctx, 3), visibility: models.VisibilityPrivate, err: notFound(models.VisibilityPrivate)},
+		{description: "private blog to its author", ctx: withViewer(ctx, 2), visibility: models.VisibilityPrivate},
+		{description: "public blog to a blocked account", ctx: withViewer(ctx, 6), visibility: models.VisibilityPublic, err: notFound(models.VisibilityPublic)},
+		{description: "error in checking the block", ctx: withViewer(ctx, 7), visibility: models.VisibilityPublic, err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetByID(tc.ctx, blogs[tc.visibility].BlogID)
+
+		if tc.err == nil {
+			assert.Equal(t, blogs[tc.visibility], output, "TEST [%v], failed.\n%s", i+1, tc.description)
+		} else {
+			assert.Nil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetAllRelated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	mockFollowStore := stores.NewMockFollow(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockMuteStore := stores.NewMockMute(ctrl)
+	mockBlogService := New(mockBlogStore, services.NewMockTag(ctrl), stores.NewMockImage(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockAccount(ctrl), stores.NewMockRevision(ctrl), stores.NewMockUpload(ctrl), stores.NewMockBlob(ctrl), mockFollowStore, mockBlockStore, mockMuteStore, stores.NewMockReport(ctrl), nil)
+
+	_, _, _, ctx, _ := initializeTest(t)
+	page := &models.Page{Limit: 3, PageNo: 1}
+
+	mockFollowStore.EXPECT().GetFollowees(gomock.Any(), int64(3)).Return([]int64{2, 4}, nil).Times(4)
+	mockBlockStore.EXPECT().GetRelated(gomock.Any(), int64(3)).Return([]int64{5}, nil).Times(4)
+	mockMuteStore.EXPECT().GetMuted(gomock.Any(), int64(3)).Return([]*models.User{{ID: 6}}, nil)
+
+	// blogs for followers are listed to the followers of their authors, blocked and muted authors are not listed
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{Viewer: 3, Following: []int64{2, 4}, Hidden: []int64{5, 6}}, page).Return([]*models.Blog{}, nil)
+
+	// a muted author is listed on their profile
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{Blog: models.Blog{AccountID: 6}, Viewer: 3, Following: []int64{2, 4}}, page).Return([]*models.Blog{}, nil)
+
+	mockFollowStore.EXPECT().GetFollowees(gomock.Any(), int64(7)).Return(nil, errors.DBError{})
+	mockFollowStore.EXPECT().GetFollowees(gomock.Any(), int64(8)).Return([]int64{}, nil).Times(2)
+	mockBlockStore.EXPECT().GetRelated(gomock.Any(), int64(8)).Return(nil, errors.DBError{})
+	mockBlockStore.EXPECT().GetRelated(gomock.Any(), int64(8)).Return([]int64{}, nil)
+	mockMuteStore.EXPECT().GetMuted(gomock.Any(), int64(8)).Return(nil, errors.DBError{})
+	mockMuteStore.EXPECT().GetMuted(gomock.Any(), int64(3)).Return(nil, errors.DBError{})
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		filter      *models.BlogFilter
+		output      []*models.Blog
+		err         error
+	}{
+		{description: "listing of a viewer", ctx: withViewer(ctx, 3), filter: &models.BlogFilter{}, output: []*models.Blog{}},
+		{description: "profile of a blocked author", ctx: withViewer(ctx, 3), filter: &models.BlogFilter{Blog: models.Blog{AccountID: 5}}, output: []*models.Blog{}},
+		{description: "profile of a muted author", ctx: withViewer(ctx, 3), filter: &models.BlogFilter{Blog: models.Blog{AccountID: 6}}, output: []*models.Blog{}},
+		{description: "error in fetching the muted accounts", ctx: withViewer(ctx, 3), filter: &models.BlogFilter{}, err: errors.DBError{}},
+		{description: "error in fetching the followed accounts", ctx: withViewer(ctx, 7), filter: &models.BlogFilter{}, err: errors.DBError{}},
+		{description: "error in fetching the blocks", ctx: withViewer(ctx, 8), filter: &models.BlogFilter{}, err: errors.DBError{}},
+		{description: "error in fetching the muted accounts", ctx: withViewer(ctx, 8), filter: &models.BlogFilter{}, err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockBlogService.GetAll(tc.ctx, tc.filter, page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -36,6 +36,9 @@ type Account interface {
 	// Login logs in a user to his account.
 	Login(c *app.Context, user *models.User) (*models.Account, error)
 
+	// CheckActive refuses the requests of a suspended account, whose token is valid until it expires.
+	CheckActive(c *app.Context) error
+
 	// CheckAvailability checks if username, phone number and email exist in the database already.
 	CheckAvailability(c *app.Context, user *models.User) error
 
@@ -43,11 +46,35 @@ type Account interface {
 	VerifyPhone(ctx *app.Context, sid, otp, url string) error
 
 	SendOTP(ctx *app.Context, phone string) (*models.VerificationResponse, error)
+
+	// Follow makes the logged in user follow an account, whose blogs for followers are then visible to them.
+	Follow(ctx *app.Context, username string) error
+
+	// Unfollow makes the logged in user stop following an account.
+	Unfollow(ctx *app.Context, username string) error
+
+	// Block makes the logged in user block an account, hiding the blogs and profile of either from the other.
+	Block(ctx *app.Context, username string) error
+
+	// Unblock makes the logged in user stop blocking an account.
+	Unblock(ctx *app.Context, username string) error
+
+	// GetBlocked retrieves the accounts the logged in user blocks.
+	GetBlocked(ctx *app.Context) ([]*models.User, error)
+
+	// Mute makes the logged in user mute an account, hiding its blogs from their listings.
+	Mute(ctx *app.Context, username string) error
+
+	// Unmute makes the logged in user stop muting an account.
+	Unmute(ctx *app.Context, username string) error
+
+	// GetMuted retrieves the accounts the logged in user mutes.
+	GetMuted(ctx *app.Context) ([]*models.User, error)
 }
 
 type Blog interface {
 	// GetAll retrieve all blogs that match the filter.
-	GetAll(c *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error)
+	GetAll(c *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error)
 
 	// GetAllByTagName retrieves all blogs by tag name.
 	GetAllByTagName(c *app.Context, name string, page *models.Page) ([]*models.Blog, error)
@@ -55,20 +82,96 @@ type Blog interface {
 	// GetByID retrieves a single blog by its id.
 	GetByID(c *app.Context, id string) (*models.Blog, error)
 
+	// GetBySlug retrieves a blog of an author by its slug, or by one of its old slugs.
+	GetBySlug(c *app.Context, username, slug string) (*models.Blog, error)
+
+	// SetHidden hides a blog from everyone but its author, or lists it again. It is meant for moderators.
+	SetHidden(c *app.Context, id string, hidden bool) (*models.Blog, error)
+
 	// Create creates a Blog.
 	Create(c *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error)
 
 	// Update updates a blog based on its id.
 	// Parameters that are meant to be updated are populated, else left empty.
-	// Images can only be added, not deleted.
+	// Images are the final order of existing and uploaded images, nil to keep the existing ones.
 	Update(c *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error)
 
 	// Delete deletes a blog based on its id.
 	Delete(c *app.Context, id string) error
+
+	// GetRevisions retrieves the revision history of a blog, latest first.
+	GetRevisions(c *app.Context, id string, page *models.Page) ([]*models.Revision, error)
+
+	// Restore restores the text and tags of a revision of a blog, recording a new revision.
+	Restore(c *app.Context, id string, number int64) (*models.Blog, error)
+
+	// PublishScheduled publishes the scheduled blogs that are due.
+	PublishScheduled(c *app.Context) error
+
+	// RenderStale renders again the content of the blogs rendered by an older version of the markup policy.
+	RenderStale(c *app.Context) error
+
+	// CreateUpload issues a presigned URL to upload a picture of the content type and size straight to the storage.
+	CreateUpload(c *app.Context, model *models.Upload) (*models.Upload, error)
+
+	// ConfirmUpload checks and processes the uploaded picture, so that it can be attached to a blog.
+	ConfirmUpload(c *app.Context, id string) (*models.Upload, error)
+
+	// ExpireUploads deletes the uploads that were not confirmed or attached in time, along with their files.
+	ExpireUploads(c *app.Context) error
+
+	// CollectOrphans deletes the stored image files that no blog refers to, past a grace period.
+	// A dry run reports the orphans without deleting them.
+	CollectOrphans(c *app.Context, dryRun bool) (*models.OrphanReport, error)
 }
 
 type Tag interface {
 	Get(c *app.Context, name string) (*models.Tag, error)
-	AddBlogID(c *app.Context, blogID string, tags []string)
-	RemoveBlogID(c *app.Context, blogID string, tags []string)
+
+	// GetAll retrieves the tag directory, sorted by popularity or name.
+	GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error)
+
+	// Autocomplete suggests the most popular tags that start with the prefix.
+	Autocomplete(c *app.Context, prefix string) ([]*models.Tag, error)
+
+	// Trending retrieves the tags whose usage grew the fastest within the window (24h or 7d),
+	// compared to the window before it.
+	Trending(c *app.Context, window string) ([]*models.TrendingTag, error)
+
+	// AddBlogID adds a blog to its tags, returning the first tag that fails after trying all of them.
+	AddBlogID(c *app.Context, blogID string, tags []string) error
+
+	// RemoveBlogID removes a blog from its tags, returning the first tag that fails after trying all of them.
+	RemoveBlogID(c *app.Context, blogID string, tags []string) error
+}
+
+type Search interface {
+	// Blogs retrieves the blogs that match the query, most relevant first,
+	// with the matching fragments of each blog highlighted.
+	Blogs(c *app.Context, query string, page *models.Page) ([]*models.SearchHit, error)
+
+	// Users retrieves active users whose username, first name or last name starts with the query.
+	// Exact matches are ranked first.
+	Users(c *app.Context, query string, page *models.Page) ([]*models.User, error)
+}
+
+type Moderation interface {
+	// ReportBlog reports a blog the logged in user can see, for a reason, to the moderation queue.
+	ReportBlog(c *app.Context, blogID string, model *models.Report) (*models.Report, error)
+
+	// ReportUser reports a user, for a reason, to the moderation queue.
+	ReportUser(c *app.Context, username string, model *models.Report) (*models.Report, error)
+
+	// GetReports retrieves the moderation queue, oldest report first. It is open to moderators alone.
+	GetReports(c *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error)
+
+	// Act takes a moderation action, closes the reports it settles and records it in the audit trail.
+	// It is open to moderators alone.
+	Act(c *app.Context, model *models.ModerationAction) (*models.ModerationAction, error)
+
+	// GetActions retrieves the audit trail of moderation, latest action first. It is open to moderators alone.
+	GetActions(c *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error)
+
+	// GetWarnings retrieves the warnings the logged in user received, latest first.
+	GetWarnings(c *app.Context, page *models.Page) ([]*models.ModerationAction, error)
 }
@@ -36,6 +36,34 @@ func (m *MockAccount) EXPECT() *MockAccountMockRecorder {
 	return m.recorder
 }
 
+// Block mocks base method.
+func (m *MockAccount) Block(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockAccountMockRecorder) Block(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockAccount)(nil).Block), ctx, username)
+}
+
+// CheckActive mocks base method.
+func (m *MockAccount) CheckActive(c *app.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckActive", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckActive indicates an expected call of CheckActive.
+func (mr *MockAccountMockRecorder) CheckActive(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckActive", reflect.TypeOf((*MockAccount)(nil).CheckActive), c)
+}
+
 // CheckAvailability mocks base method.
 func (m *MockAccount) CheckAvailability(c *app.Context, user *models.User) error {
 	m.ctrl.T.Helper()
@@ -79,6 +107,20 @@ func (mr *MockAccountMockRecorder) Delete(c interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccount)(nil).Delete), c)
 }
 
+// Follow mocks base method.
+func (m *MockAccount) Follow(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockAccountMockRecorder) Follow(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockAccount)(nil).Follow), ctx, username)
+}
+
 // GetAccountWithBlogs mocks base method.
 func (m *MockAccount) GetAccountWithBlogs(c *app.Context, username string) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +151,21 @@ func (mr *MockAccountMockRecorder) GetAll(c, filter interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAccount)(nil).GetAll), c, filter)
 }
 
+// GetBlocked mocks base method.
+func (m *MockAccount) GetBlocked(ctx *app.Context) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocked", ctx)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocked indicates an expected call of GetBlocked.
+func (mr *MockAccountMockRecorder) GetBlocked(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocked", reflect.TypeOf((*MockAccount)(nil).GetBlocked), ctx)
+}
+
 // GetByID mocks base method.
 func (m *MockAccount) GetByID(c *app.Context, id int64) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +181,21 @@ func (mr *MockAccountMockRecorder) GetByID(c, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAccount)(nil).GetByID), c, id)
 }
 
+// GetMuted mocks base method.
+func (m *MockAccount) GetMuted(ctx *app.Context) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMuted", ctx)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMuted indicates an expected call of GetMuted.
+func (mr *MockAccountMockRecorder) GetMuted(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMuted", reflect.TypeOf((*MockAccount)(nil).GetMuted), ctx)
+}
+
 // Login mocks base method.
 func (m *MockAccount) Login(c *app.Context, user *models.User) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +211,77 @@ func (mr *MockAccountMockRecorder) Login(c, user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAccount)(nil).Login), c, user)
 }
 
+// Mute mocks base method.
+func (m *MockAccount) Mute(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockAccountMockRecorder) Mute(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockAccount)(nil).Mute), ctx, username)
+}
+
+// SendOTP mocks base method.
+func (m *MockAccount) SendOTP(ctx *app.Context, phone string) (*models.VerificationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendOTP", ctx, phone)
+	ret0, _ := ret[0].(*models.VerificationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendOTP indicates an expected call of SendOTP.
+func (mr *MockAccountMockRecorder) SendOTP(ctx, phone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendOTP", reflect.TypeOf((*MockAccount)(nil).SendOTP), ctx, phone)
+}
+
+// Unblock mocks base method.
+func (m *MockAccount) Unblock(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockAccountMockRecorder) Unblock(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockAccount)(nil).Unblock), ctx, username)
+}
+
+// Unfollow mocks base method.
+func (m *MockAccount) Unfollow(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockAccountMockRecorder) Unfollow(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockAccount)(nil).Unfollow), ctx, username)
+}
+
+// Unmute mocks base method.
+func (m *MockAccount) Unmute(ctx *app.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmute", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unmute indicates an expected call of Unmute.
+func (mr *MockAccountMockRecorder) Unmute(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmute", reflect.TypeOf((*MockAccount)(nil).Unmute), ctx, username)
+}
+
 // Update mocks base method.
 func (m *MockAccount) Update(c *app.Context, model *models.Account, id int64) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -183,6 +326,20 @@ func (mr *MockAccountMockRecorder) UpdateUser(c, model interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAccount)(nil).UpdateUser), c, model)
 }
 
+// VerifyPhone mocks base method.
+func (m *MockAccount) VerifyPhone(ctx *app.Context, sid, otp, url string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPhone", ctx, sid, otp, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPhone indicates an expected call of VerifyPhone.
+func (mr *MockAccountMockRecorder) VerifyPhone(ctx, sid, otp, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPhone", reflect.TypeOf((*MockAccount)(nil).VerifyPhone), ctx, sid, otp, url)
+}
+
 // MockBlog is a mock of Blog interface.
 type MockBlog struct {
 	ctrl     *gomock.Controller
@@ -206,6 +363,36 @@ func (m *MockBlog) EXPECT() *MockBlogMockRecorder {
 	return m.recorder
 }
 
+// CollectOrphans mocks base method.
+func (m *MockBlog) CollectOrphans(c *app.Context, dryRun bool) (*models.OrphanReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectOrphans", c, dryRun)
+	ret0, _ := ret[0].(*models.OrphanReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectOrphans indicates an expected call of CollectOrphans.
+func (mr *MockBlogMockRecorder) CollectOrphans(c, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectOrphans", reflect.TypeOf((*MockBlog)(nil).CollectOrphans), c, dryRun)
+}
+
+// ConfirmUpload mocks base method.
+func (m *MockBlog) ConfirmUpload(c *app.Context, id string) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmUpload", c, id)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmUpload indicates an expected call of ConfirmUpload.
+func (mr *MockBlogMockRecorder) ConfirmUpload(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmUpload", reflect.TypeOf((*MockBlog)(nil).ConfirmUpload), c, id)
+}
+
 // Create mocks base method.
 func (m *MockBlog) Create(c *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error) {
 	m.ctrl.T.Helper()
@@ -221,6 +408,21 @@ func (mr *MockBlogMockRecorder) Create(c, model, images interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBlog)(nil).Create), c, model, images)
 }
 
+// CreateUpload mocks base method.
+func (m *MockBlog) CreateUpload(c *app.Context, model *models.Upload) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpload", c, model)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUpload indicates an expected call of CreateUpload.
+func (mr *MockBlogMockRecorder) CreateUpload(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpload", reflect.TypeOf((*MockBlog)(nil).CreateUpload), c, model)
+}
+
 // Delete mocks base method.
 func (m *MockBlog) Delete(c *app.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -235,8 +437,22 @@ func (mr *MockBlogMockRecorder) Delete(c, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlog)(nil).Delete), c, id)
 }
 
+// ExpireUploads mocks base method.
+func (m *MockBlog) ExpireUploads(c *app.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireUploads", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireUploads indicates an expected call of ExpireUploads.
+func (mr *MockBlogMockRecorder) ExpireUploads(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireUploads", reflect.TypeOf((*MockBlog)(nil).ExpireUploads), c)
+}
+
 // GetAll mocks base method.
-func (m *MockBlog) GetAll(c *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error) {
+func (m *MockBlog) GetAll(c *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAll", c, filter, page)
 	ret0, _ := ret[0].([]*models.Blog)
@@ -251,18 +467,18 @@ func (mr *MockBlogMockRecorder) GetAll(c, filter, page interface{}) *gomock.Call
 }
 
 // GetAllByTagName mocks base method.
-func (m *MockBlog) GetAllByTagName(c *app.Context, name string) ([]*models.Blog, error) {
+func (m *MockBlog) GetAllByTagName(c *app.Context, name string, page *models.Page) ([]*models.Blog, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByTagName", c, name)
+	ret := m.ctrl.Call(m, "GetAllByTagName", c, name, page)
 	ret0, _ := ret[0].([]*models.Blog)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByTagName indicates an expected call of GetAllByTagName.
-func (mr *MockBlogMockRecorder) GetAllByTagName(c, name interface{}) *gomock.Call {
+func (mr *MockBlogMockRecorder) GetAllByTagName(c, name, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByTagName", reflect.TypeOf((*MockBlog)(nil).GetAllByTagName), c, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByTagName", reflect.TypeOf((*MockBlog)(nil).GetAllByTagName), c, name, page)
 }
 
 // GetByID mocks base method.
@@ -280,6 +496,94 @@ func (mr *MockBlogMockRecorder) GetByID(c, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBlog)(nil).GetByID), c, id)
 }
 
+// GetBySlug mocks base method.
+func (m *MockBlog) GetBySlug(c *app.Context, username, slug string) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", c, username, slug)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockBlogMockRecorder) GetBySlug(c, username, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockBlog)(nil).GetBySlug), c, username, slug)
+}
+
+// GetRevisions mocks base method.
+func (m *MockBlog) GetRevisions(c *app.Context, id string, page *models.Page) ([]*models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", c, id, page)
+	ret0, _ := ret[0].([]*models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockBlogMockRecorder) GetRevisions(c, id, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockBlog)(nil).GetRevisions), c, id, page)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockBlog) PublishScheduled(c *app.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockBlogMockRecorder) PublishScheduled(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockBlog)(nil).PublishScheduled), c)
+}
+
+// RenderStale mocks base method.
+func (m *MockBlog) RenderStale(c *app.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderStale", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenderStale indicates an expected call of RenderStale.
+func (mr *MockBlogMockRecorder) RenderStale(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderStale", reflect.TypeOf((*MockBlog)(nil).RenderStale), c)
+}
+
+// Restore mocks base method.
+func (m *MockBlog) Restore(c *app.Context, id string, number int64) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", c, id, number)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBlogMockRecorder) Restore(c, id, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBlog)(nil).Restore), c, id, number)
+}
+
+// SetHidden mocks base method.
+func (m *MockBlog) SetHidden(c *app.Context, id string, hidden bool) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHidden", c, id, hidden)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHidden indicates an expected call of SetHidden.
+func (mr *MockBlogMockRecorder) SetHidden(c, id, hidden interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHidden", reflect.TypeOf((*MockBlog)(nil).SetHidden), c, id, hidden)
+}
+
 // Update mocks base method.
 func (m *MockBlog) Update(c *app.Context, model *models.Blog, images []*multipart.FileHeader) (*models.Blog, error) {
 	m.ctrl.T.Helper()
@@ -319,9 +623,11 @@ func (m *MockTag) EXPECT() *MockTagMockRecorder {
 }
 
 // AddBlogID mocks base method.
-func (m *MockTag) AddBlogID(c *app.Context, blogID string, tags []string) {
+func (m *MockTag) AddBlogID(c *app.Context, blogID string, tags []string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "AddBlogID", c, blogID, tags)
+	ret := m.ctrl.Call(m, "AddBlogID", c, blogID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // AddBlogID indicates an expected call of AddBlogID.
@@ -330,6 +636,21 @@ func (mr *MockTagMockRecorder) AddBlogID(c, blogID, tags interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlogID", reflect.TypeOf((*MockTag)(nil).AddBlogID), c, blogID, tags)
 }
 
+// Autocomplete mocks base method.
+func (m *MockTag) Autocomplete(c *app.Context, prefix string) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Autocomplete", c, prefix)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Autocomplete indicates an expected call of Autocomplete.
+func (mr *MockTagMockRecorder) Autocomplete(c, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Autocomplete", reflect.TypeOf((*MockTag)(nil).Autocomplete), c, prefix)
+}
+
 // Get mocks base method.
 func (m *MockTag) Get(c *app.Context, name string) (*models.Tag, error) {
 	m.ctrl.T.Helper()
@@ -345,10 +666,27 @@ func (mr *MockTagMockRecorder) Get(c, name interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTag)(nil).Get), c, name)
 }
 
+// GetAll mocks base method.
+func (m *MockTag) GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, page, sortBy)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagMockRecorder) GetAll(c, page, sortBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTag)(nil).GetAll), c, page, sortBy)
+}
+
 // RemoveBlogID mocks base method.
-func (m *MockTag) RemoveBlogID(c *app.Context, blogID string, tags []string) {
+func (m *MockTag) RemoveBlogID(c *app.Context, blogID string, tags []string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "RemoveBlogID", c, blogID, tags)
+	ret := m.ctrl.Call(m, "RemoveBlogID", c, blogID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // RemoveBlogID indicates an expected call of RemoveBlogID.
@@ -356,3 +694,184 @@ func (mr *MockTagMockRecorder) RemoveBlogID(c, blogID, tags interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBlogID", reflect.TypeOf((*MockTag)(nil).RemoveBlogID), c, blogID, tags)
 }
+
+// Trending mocks base method.
+func (m *MockTag) Trending(c *app.Context, window string) ([]*models.TrendingTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trending", c, window)
+	ret0, _ := ret[0].([]*models.TrendingTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trending indicates an expected call of Trending.
+func (mr *MockTagMockRecorder) Trending(c, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trending", reflect.TypeOf((*MockTag)(nil).Trending), c, window)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Blogs mocks base method.
+func (m *MockSearch) Blogs(c *app.Context, query string, page *models.Page) ([]*models.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Blogs", c, query, page)
+	ret0, _ := ret[0].([]*models.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Blogs indicates an expected call of Blogs.
+func (mr *MockSearchMockRecorder) Blogs(c, query, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Blogs", reflect.TypeOf((*MockSearch)(nil).Blogs), c, query, page)
+}
+
+// Users mocks base method.
+func (m *MockSearch) Users(c *app.Context, query string, page *models.Page) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Users", c, query, page)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Users indicates an expected call of Users.
+func (mr *MockSearchMockRecorder) Users(c, query, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Users", reflect.TypeOf((*MockSearch)(nil).Users), c, query, page)
+}
+
+// MockModeration is a mock of Moderation interface.
+type MockModeration struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationMockRecorder
+}
+
+// MockModerationMockRecorder is the mock recorder for MockModeration.
+type MockModerationMockRecorder struct {
+	mock *MockModeration
+}
+
+// NewMockModeration creates a new mock instance.
+func NewMockModeration(ctrl *gomock.Controller) *MockModeration {
+	mock := &MockModeration{ctrl: ctrl}
+	mock.recorder = &MockModerationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModeration) EXPECT() *MockModerationMockRecorder {
+	return m.recorder
+}
+
+// Act mocks base method.
+func (m *MockModeration) Act(c *app.Context, model *models.ModerationAction) (*models.ModerationAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Act", c, model)
+	ret0, _ := ret[0].(*models.ModerationAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Act indicates an expected call of Act.
+func (mr *MockModerationMockRecorder) Act(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Act", reflect.TypeOf((*MockModeration)(nil).Act), c, model)
+}
+
+// GetActions mocks base method.
+func (m *MockModeration) GetActions(c *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActions", c, filter, page)
+	ret0, _ := ret[0].([]*models.ModerationAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActions indicates an expected call of GetActions.
+func (mr *MockModerationMockRecorder) GetActions(c, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActions", reflect.TypeOf((*MockModeration)(nil).GetActions), c, filter, page)
+}
+
+// GetReports mocks base method.
+func (m *MockModeration) GetReports(c *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", c, filter, page)
+	ret0, _ := ret[0].([]*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockModerationMockRecorder) GetReports(c, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockModeration)(nil).GetReports), c, filter, page)
+}
+
+// GetWarnings mocks base method.
+func (m *MockModeration) GetWarnings(c *app.Context, page *models.Page) ([]*models.ModerationAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarnings", c, page)
+	ret0, _ := ret[0].([]*models.ModerationAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarnings indicates an expected call of GetWarnings.
+func (mr *MockModerationMockRecorder) GetWarnings(c, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarnings", reflect.TypeOf((*MockModeration)(nil).GetWarnings), c, page)
+}
+
+// ReportBlog mocks base method.
+func (m *MockModeration) ReportBlog(c *app.Context, blogID string, model *models.Report) (*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportBlog", c, blogID, model)
+	ret0, _ := ret[0].(*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportBlog indicates an expected call of ReportBlog.
+func (mr *MockModerationMockRecorder) ReportBlog(c, blogID, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportBlog", reflect.TypeOf((*MockModeration)(nil).ReportBlog), c, blogID, model)
+}
+
+// ReportUser mocks base method.
+func (m *MockModeration) ReportUser(c *app.Context, username string, model *models.Report) (*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportUser", c, username, model)
+	ret0, _ := ret[0].(*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportUser indicates an expected call of ReportUser.
+func (mr *MockModerationMockRecorder) ReportUser(c, username, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportUser", reflect.TypeOf((*MockModeration)(nil).ReportUser), c, username, model)
+}
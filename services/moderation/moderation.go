@@ -0,0 +1,347 @@
+package moderation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+const maxDetailsLength = 1000
+
+type moderation struct {
+	reportStore  stores.Report
+	auditStore   stores.Audit
+	accountStore stores.Account
+	blogService  services.Blog
+}
+
+func New(reportStore stores.Report, auditStore stores.Audit, accountStore stores.Account, blogService services.Blog) services.Moderation {
+	return moderation{
+		reportStore:  reportStore,
+		auditStore:   auditStore,
+		accountStore: accountStore,
+		blogService:  blogService,
+	}
+}
+
+// ReportBlog reports a blog the logged in user can see, for a reason, to the moderation queue.
+// Users cannot report their own blogs, and have a single open report on a blog.
+func (m moderation) ReportBlog(ctx *app.Context, blogID string, model *models.Report) (*models.Report, error) {
+	if err := validateReport(model); err != nil {
+		return nil, err
+	}
+
+	// a blog the user cannot see does not exist for them
+	blog, err := m.blogService.GetByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	reporterID := userID(ctx)
+	if blog.AccountID == reporterID {
+		return nil, errors.InvalidParam{Param: "blog_id"}
+	}
+
+	return m.report(ctx, &models.Report{Kind: models.ReportBlog, BlogID: blog.BlogID, UserID: blog.AccountID,
+		ReporterID: reporterID, Reason: model.Reason, Details: model.Details})
+}
+
+// ReportUser reports a user, for a reason, to the moderation queue.
+// Users cannot report themselves, and have a single open report on a user.
+func (m moderation) ReportUser(ctx *app.Context, username string, model *models.Report) (*models.Report, error) {
+	if username == "" {
+		return nil, errors.MissingParam{Param: "username"}
+	}
+
+	if err := validateReport(model); err != nil {
+		return nil, err
+	}
+
+	account, err := m.accountStore.Get(ctx, &models.Account{User: models.User{UserName: username}})
+	if err != nil {
+		return nil, err
+	}
+
+	reporterID := userID(ctx)
+	if account.ID == reporterID {
+		return nil, errors.InvalidParam{Param: "username"}
+	}
+
+	return m.report(ctx, &models.Report{Kind: models.ReportUser, UserID: account.ID,
+		ReporterID: reporterID, Reason: model.Reason, Details: model.Details})
+}
+
+func (m moderation) report(ctx *app.Context, report *models.Report) (*models.Report, error) {
+	report.ID = uuid.New().String()
+	report.Status = models.ReportOpen
+	report.CreatedOn = time.Now()
+
+	if err := m.reportStore.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetReports retrieves the moderation queue, oldest report first. It is open to moderators alone.
+func (m moderation) GetReports(ctx *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error) {
+	if _, err := m.moderator(ctx); err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &models.ReportFilter{}
+	}
+
+	switch filter.Status {
+	case "", models.ReportOpen, models.ReportResolved, models.ReportDismissed:
+	default:
+		return nil, errors.InvalidParam{Param: "status"}
+	}
+
+	switch filter.Kind {
+	case "", models.ReportBlog, models.ReportUser:
+	default:
+		return nil, errors.InvalidParam{Param: "kind"}
+	}
+
+	if filter.Reason != "" && !validReason(filter.Reason) {
+		return nil, errors.InvalidParam{Param: "reason"}
+	}
+
+	return m.reportStore.GetAll(ctx, filter, page)
+}
+
+// Act takes a moderation action on a blog or a user, or on the blog or the user of a report.
+// Hiding a blog resolves the open reports on it, and suspending a user resolves the open reports on them and their blogs.
+// A warning carries the note of the moderator, which the user sees. Dismissing closes a report without any other action.
+// The report of an action is closed along with it. Every action is recorded in the audit trail as pending before
+// it is taken, so that none is taken without a record, and the record is completed, or failed, once it is taken.
+func (m moderation) Act(ctx *app.Context, model *models.ModerationAction) (*models.ModerationAction, error) {
+	moderatorID, err := m.moderator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if model == nil || model.Action == "" {
+		return nil, errors.MissingParam{Param: "action"}
+	}
+
+	action := &models.ModerationAction{ID: uuid.New().String(), Action: model.Action, ModeratorID: moderatorID,
+		BlogID: model.BlogID, ReportID: model.ReportID, Note: model.Note, CreatedOn: time.Now()}
+
+	if err = m.target(ctx, action, model.UserName); err != nil {
+		return nil, err
+	}
+
+	if err = validateAction(action); err != nil {
+		return nil, err
+	}
+
+	action.Status = models.ActionPending
+
+	if err = m.auditStore.Create(ctx, action); err != nil {
+		return nil, err
+	}
+
+	closing, status, err := m.take(ctx, action)
+	if err != nil {
+		action.Status = models.ActionFailed
+
+		// the failure is recorded even if the client went away
+		if auditErr := m.auditStore.SetStatus(ctx.Detach(), action); auditErr != nil {
+			ctx.Logger.Errorf("cannot record failure of moderation action %s: %s", action.ID, auditErr.Error())
+		}
+
+		return nil, err
+	}
+
+	action.Status = models.ActionCompleted
+
+	if err = m.auditStore.SetStatus(ctx, action); err != nil {
+		return nil, err
+	}
+
+	if closing != nil {
+		if _, err = m.reportStore.Close(ctx, closing, status, action.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return action, nil
+}
+
+// target resolves the blog and the user an action is taken on, from its report or the username given.
+func (m moderation) target(ctx *app.Context, action *models.ModerationAction, username string) error {
+	if action.ReportID != "" {
+		report, err := m.reportStore.Get(ctx, action.ReportID)
+		if err != nil {
+			return err
+		}
+
+		if report.Status != models.ReportOpen {
+			return errors.EntityNotFound{Entity: "open report", ID: action.ReportID}
+		}
+
+		if action.BlogID == "" {
+			action.BlogID = report.BlogID
+		}
+
+		action.UserID = report.UserID
+	}
+
+	if username != "" {
+		account, err := m.accountStore.Get(ctx, &models.Account{User: models.User{UserName: username}})
+		if err != nil {
+			return err
+		}
+
+		action.UserID = account.ID
+	}
+
+	return nil
+}
+
+// validateAction checks an action has what it is taken on, before it is recorded.
+func validateAction(action *models.ModerationAction) error {
+	switch action.Action {
+	case models.ActionHideBlog, models.ActionUnhideBlog:
+		if action.BlogID == "" {
+			return errors.MissingParam{Param: "blog_id"}
+		}
+	case models.ActionWarn:
+		if action.UserID == 0 {
+			return errors.MissingParam{Param: "user_name"}
+		}
+
+		if action.Note == "" {
+			return errors.MissingParam{Param: "note"}
+		}
+	case models.ActionSuspend:
+		if action.UserID == 0 {
+			return errors.MissingParam{Param: "user_name"}
+		}
+	case models.ActionDismiss:
+		if action.ReportID == "" {
+			return errors.MissingParam{Param: "report_id"}
+		}
+	default:
+		return errors.InvalidParam{Param: "action"}
+	}
+
+	return nil
+}
+
+// take takes a valid action, and returns the reports it settles with the status they are closed with.
+func (m moderation) take(ctx *app.Context, action *models.ModerationAction) (*models.ReportFilter, string, error) {
+	closing := &models.ReportFilter{ID: action.ReportID}
+
+	switch action.Action {
+	case models.ActionHideBlog, models.ActionUnhideBlog:
+		blog, err := m.blogService.SetHidden(ctx, action.BlogID, action.Action == models.ActionHideBlog)
+		if err != nil {
+			return nil, "", err
+		}
+
+		action.UserID = blog.AccountID
+
+		if action.Action == models.ActionHideBlog {
+			closing = &models.ReportFilter{Kind: models.ReportBlog, BlogID: blog.BlogID}
+		}
+	case models.ActionSuspend:
+		if _, err := m.accountStore.Update(ctx, &models.Account{User: models.User{ID: action.UserID}, Status: models.AccountSuspended}); err != nil {
+			return nil, "", err
+		}
+
+		closing = &models.ReportFilter{UserID: action.UserID}
+	case models.ActionDismiss:
+		return closing, models.ReportDismissed, nil
+	}
+
+	if *closing == (models.ReportFilter{}) {
+		return nil, "", nil
+	}
+
+	return closing, models.ReportResolved, nil
+}
+
+// GetActions retrieves the audit trail of moderation, latest action first. It is open to moderators alone.
+func (m moderation) GetActions(ctx *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error) {
+	if _, err := m.moderator(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.auditStore.GetAll(ctx, filter, page)
+}
+
+// GetWarnings retrieves the warnings the logged in user received, latest first. Moderators are not named to them.
+func (m moderation) GetWarnings(ctx *app.Context, page *models.Page) ([]*models.ModerationAction, error) {
+	warnings, err := m.auditStore.GetAll(ctx, &models.ModerationFilter{Action: models.ActionWarn, UserID: userID(ctx), Status: models.ActionCompleted}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warning := range warnings {
+		warning.ModeratorID = 0
+	}
+
+	return warnings, nil
+}
+
+// moderator returns the id of the logged in user, if they are a moderator.
+func (m moderation) moderator(ctx *app.Context) (int64, error) {
+	id := userID(ctx)
+
+	account, err := m.accountStore.Get(ctx, &models.Account{User: models.User{ID: id}})
+	if err != nil {
+		return 0, err
+	}
+
+	if account.Role != models.RoleModerator || account.Status == models.AccountSuspended {
+		return 0, errors.AuthError{Msg: "moderation is open to moderators alone"}
+	}
+
+	return id, nil
+}
+
+func validateReport(model *models.Report) error {
+	if model == nil || model.Reason == "" {
+		return errors.MissingParam{Param: "reason"}
+	}
+
+	if !validReason(model.Reason) {
+		return errors.InvalidParam{Param: "reason"}
+	}
+
+	if len(model.Details) > maxDetailsLength {
+		return errors.InvalidParam{Param: "details"}
+	}
+
+	return nil
+}
+
+func validReason(reason string) bool {
+	switch reason {
+	case models.ReasonSpam, models.ReasonHarassment, models.ReasonHateSpeech, models.ReasonViolence,
+		models.ReasonNudity, models.ReasonImpersonation, models.ReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+func userID(ctx *app.Context) int64 {
+	claims, ok := ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims)
+	if !ok {
+		return 0
+	}
+
+	return claims.UserID
+}
@@ -0,0 +1,262 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+type mocks struct {
+	reportStore  *stores.MockReport
+	auditStore   *stores.MockAudit
+	accountStore *stores.MockAccount
+	blogService  *services.MockBlog
+}
+
+func initializeTest(t *testing.T) (mocks, services.Moderation) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		reportStore:  stores.NewMockReport(ctrl),
+		auditStore:   stores.NewMockAudit(ctrl),
+		accountStore: stores.NewMockAccount(ctrl),
+		blogService:  services.NewMockBlog(ctrl),
+	}
+
+	return m, New(m.reportStore, m.auditStore, m.accountStore, m.blogService)
+}
+
+func withUser(id int64) *app.Context {
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.WithValue(context.TODO(), auth.JWTContextKey("claims"), &auth.Claims{UserID: id})
+
+	return ctx
+}
+
+//nolint:lll // test cases need to be readable
+func TestModeration_ReportBlog(t *testing.T) {
+	m, service := initializeTest(t)
+
+	blog := &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2}
+
+	m.blogService.EXPECT().GetByID(gomock.Any(), "MSI8WKNSH9").Return(blog, nil).Times(3)
+	m.blogService.EXPECT().GetByID(gomock.Any(), "9SNVSH8K2M").Return(nil, errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"})
+	m.reportStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	m.reportStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.EntityAlreadyExists{Entity: "report", ValueType: "blog", Value: "MSI8WKNSH9"})
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		blogID      string
+		input       *models.Report
+		err         error
+	}{
+		{description: "report of a blog", ctx: withUser(3), blogID: "MSI8WKNSH9", input: &models.Report{Reason: models.ReasonSpam, Details: "links to a shop"}},
+		{description: "second open report of a blog", ctx: withUser(3), blogID: "MSI8WKNSH9", input: &models.Report{Reason: models.ReasonSpam}, err: errors.EntityAlreadyExists{Entity: "report", ValueType: "blog", Value: "MSI8WKNSH9"}},
+		{description: "report of an own blog", ctx: withUser(2), blogID: "MSI8WKNSH9", input: &models.Report{Reason: models.ReasonSpam}, err: errors.InvalidParam{Param: "blog_id"}},
+		{description: "blog the user cannot see", ctx: withUser(3), blogID: "9SNVSH8K2M", input: &models.Report{Reason: models.ReasonSpam}, err: errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"}},
+		{description: "missing reason", ctx: withUser(3), blogID: "MSI8WKNSH9", input: &models.Report{}, err: errors.MissingParam{Param: "reason"}},
+		{description: "unknown reason", ctx: withUser(3), blogID: "MSI8WKNSH9", input: &models.Report{Reason: "boring"}, err: errors.InvalidParam{Param: "reason"}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.ReportBlog(tc.ctx, tc.blogID, tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil && assert.NotNil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.Equal(t, models.Report{ID: output.ID, Kind: models.ReportBlog, BlogID: "MSI8WKNSH9", UserID: 2, ReporterID: 3, Reason: models.ReasonSpam, Details: "links to a shop", Status: models.ReportOpen, CreatedOn: output.CreatedOn}, *output, "TEST [%v], failed.\n%s", i+1, tc.description)
+			assert.NotEmpty(t, output.ID, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestModeration_ReportUser(t *testing.T) {
+	m, service := initializeTest(t)
+
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "jaiss"}}).Return(&models.Account{User: models.User{ID: 2, UserName: "jaiss"}}, nil).Times(2)
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "nobody"}}).Return(nil, errors.EntityNotFound{Entity: "account"})
+	m.reportStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		username    string
+		err         error
+	}{
+		{description: "report of a user", ctx: withUser(3), username: "jaiss"},
+		{description: "report of oneself", ctx: withUser(2), username: "jaiss", err: errors.InvalidParam{Param: "username"}},
+		{description: "unknown user", ctx: withUser(3), username: "nobody", err: errors.EntityNotFound{Entity: "account"}},
+		{description: "missing username", ctx: withUser(3), err: errors.MissingParam{Param: "username"}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.ReportUser(tc.ctx, tc.username, &models.Report{Reason: models.ReasonImpersonation})
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil && assert.NotNil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.Equal(t, models.Report{ID: output.ID, Kind: models.ReportUser, UserID: 2, ReporterID: 3, Reason: models.ReasonImpersonation, Status: models.ReportOpen, CreatedOn: output.CreatedOn}, *output, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestModeration_GetReports(t *testing.T) {
+	m, service := initializeTest(t)
+
+	page := &models.Page{Limit: 20, PageNo: 1}
+	reports := []*models.Report{{ID: "r1", Kind: models.ReportBlog, BlogID: "MSI8WKNSH9", Status: models.ReportOpen}}
+
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{ID: 1}}).Return(&models.Account{User: models.User{ID: 1}, Role: models.RoleModerator, Status: "ACTIVE"}, nil).AnyTimes()
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{ID: 3}}).Return(&models.Account{User: models.User{ID: 3}, Role: models.RoleUser, Status: "ACTIVE"}, nil)
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{ID: 4}}).Return(&models.Account{User: models.User{ID: 4}, Role: models.RoleModerator, Status: models.AccountSuspended}, nil)
+	m.reportStore.EXPECT().GetAll(gomock.Any(), &models.ReportFilter{Kind: models.ReportBlog}, page).Return(reports, nil)
+
+	tests := []struct {
+		description string
+		ctx         *app.Context
+		filter      *models.ReportFilter
+		output      []*models.Report
+		err         error
+	}{
+		{description: "queue of a moderator", ctx: withUser(1), filter: &models.ReportFilter{Kind: models.ReportBlog}, output: reports},
+		{description: "queue of a user", ctx: withUser(3), filter: &models.ReportFilter{}, err: errors.AuthError{Msg: "moderation is open to moderators alone"}},
+		{description: "queue of a suspended moderator", ctx: withUser(4), filter: &models.ReportFilter{}, err: errors.AuthError{Msg: "moderation is open to moderators alone"}},
+		{description: "unknown status", ctx: withUser(1), filter: &models.ReportFilter{Status: "closed"}, err: errors.InvalidParam{Param: "status"}},
+		{description: "unknown kind", ctx: withUser(1), filter: &models.ReportFilter{Kind: "comment"}, err: errors.InvalidParam{Param: "kind"}},
+		{description: "unknown reason", ctx: withUser(1), filter: &models.ReportFilter{Reason: "boring"}, err: errors.InvalidParam{Param: "reason"}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.GetReports(tc.ctx, tc.filter, page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestModeration_Act(t *testing.T) {
+	m, service := initializeTest(t)
+	ctx := withUser(1)
+
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{ID: 1}}).Return(&models.Account{User: models.User{ID: 1}, Role: models.RoleModerator}, nil).AnyTimes()
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{UserName: "jaiss"}}).Return(&models.Account{User: models.User{ID: 2, UserName: "jaiss"}}, nil).AnyTimes()
+	m.reportStore.EXPECT().Get(gomock.Any(), "r1").Return(&models.Report{ID: "r1", Kind: models.ReportBlog, BlogID: "MSI8WKNSH9", UserID: 2, Status: models.ReportOpen}, nil).AnyTimes()
+	m.reportStore.EXPECT().Get(gomock.Any(), "r2").Return(&models.Report{ID: "r2", Kind: models.ReportUser, UserID: 2, Status: models.ReportDismissed}, nil)
+
+	var actions []*models.ModerationAction
+
+	m.auditStore.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *app.Context, action *models.ModerationAction) error {
+		// the action is recorded before it is taken
+		assert.Equal(t, models.ActionPending, action.Status, "TEST, failed.\npending record of %s", action.Action)
+
+		actions = append(actions, action)
+		return nil
+	}).Times(6)
+
+	m.auditStore.EXPECT().SetStatus(gomock.Any(), gomock.Any()).Return(nil).Times(6)
+
+	// hiding a blog of a report resolves every open report on the blog
+	m.blogService.EXPECT().SetHidden(gomock.Any(), "MSI8WKNSH9", true).Return(&models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Hidden: true}, nil)
+	m.reportStore.EXPECT().Close(gomock.Any(), &models.ReportFilter{Kind: models.ReportBlog, BlogID: "MSI8WKNSH9"}, models.ReportResolved, gomock.Any()).Return(int64(2), nil)
+
+	// lists the blog again, with no report to close
+	m.blogService.EXPECT().SetHidden(gomock.Any(), "MSI8WKNSH9", false).Return(&models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2}, nil)
+
+	// suspends a user, and resolves every open report on them and their blogs
+	m.accountStore.EXPECT().Update(gomock.Any(), &models.Account{User: models.User{ID: 2}, Status: models.AccountSuspended}).Return(&models.Account{}, nil)
+	m.reportStore.EXPECT().Close(gomock.Any(), &models.ReportFilter{UserID: 2}, models.ReportResolved, gomock.Any()).Return(int64(3), nil)
+
+	// dismisses a report
+	m.reportStore.EXPECT().Close(gomock.Any(), &models.ReportFilter{ID: "r1"}, models.ReportDismissed, gomock.Any()).Return(int64(1), nil)
+
+	m.blogService.EXPECT().SetHidden(gomock.Any(), "9SNVSH8K2M", true).Return(nil, errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"})
+
+	tests := []struct {
+		description string
+		input       *models.ModerationAction
+		output      *models.ModerationAction
+		err         error
+	}{
+		{description: "hide the blog of a report", input: &models.ModerationAction{Action: models.ActionHideBlog, ReportID: "r1"}, output: &models.ModerationAction{Action: models.ActionHideBlog, ModeratorID: 1, BlogID: "MSI8WKNSH9", UserID: 2, ReportID: "r1", Status: models.ActionCompleted}},
+		{description: "unhide a blog", input: &models.ModerationAction{Action: models.ActionUnhideBlog, BlogID: "MSI8WKNSH9"}, output: &models.ModerationAction{Action: models.ActionUnhideBlog, ModeratorID: 1, BlogID: "MSI8WKNSH9", UserID: 2, Status: models.ActionCompleted}},
+		{description: "warn a user", input: &models.ModerationAction{Action: models.ActionWarn, UserName: "jaiss", Note: "be kind"}, output: &models.ModerationAction{Action: models.ActionWarn, ModeratorID: 1, UserID: 2, Note: "be kind", Status: models.ActionCompleted}},
+		{description: "suspend a user", input: &models.ModerationAction{Action: models.ActionSuspend, UserName: "jaiss"}, output: &models.ModerationAction{Action: models.ActionSuspend, ModeratorID: 1, UserID: 2, Status: models.ActionCompleted}},
+		{description: "dismiss a report", input: &models.ModerationAction{Action: models.ActionDismiss, ReportID: "r1"}, output: &models.ModerationAction{Action: models.ActionDismiss, ModeratorID: 1, BlogID: "MSI8WKNSH9", UserID: 2, ReportID: "r1", Status: models.ActionCompleted}},
+		{description: "report closed already", input: &models.ModerationAction{Action: models.ActionDismiss, ReportID: "r2"}, err: errors.EntityNotFound{Entity: "open report", ID: "r2"}},
+		{description: "unknown blog", input: &models.ModerationAction{Action: models.ActionHideBlog, BlogID: "9SNVSH8K2M"}, err: errors.EntityNotFound{Entity: "blog", ID: "9SNVSH8K2M"}},
+		{description: "warning without a note", input: &models.ModerationAction{Action: models.ActionWarn, UserName: "jaiss"}, err: errors.MissingParam{Param: "note"}},
+		{description: "suspension without a user", input: &models.ModerationAction{Action: models.ActionSuspend}, err: errors.MissingParam{Param: "user_name"}},
+		{description: "dismissal without a report", input: &models.ModerationAction{Action: models.ActionDismiss}, err: errors.MissingParam{Param: "report_id"}},
+		{description: "unknown action", input: &models.ModerationAction{Action: "ban"}, err: errors.InvalidParam{Param: "action"}},
+		{description: "missing action", input: &models.ModerationAction{}, err: errors.MissingParam{Param: "action"}},
+	}
+
+	for i, tc := range tests {
+		output, err := service.Act(ctx, tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err != nil {
+			assert.Nil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+			continue
+		}
+
+		if assert.NotNil(t, output, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.NotEmpty(t, output.ID, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+			tc.output.ID, tc.output.CreatedOn = output.ID, output.CreatedOn
+			assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+			// every action taken is recorded in the audit trail
+			assert.Equal(t, output, actions[len(actions)-1], "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+
+	assert.Equal(t, 6, len(actions), "TEST, failed.\nactions recorded")
+
+	// an action that cannot be taken is recorded as failed
+	assert.Equal(t, models.ActionFailed, actions[len(actions)-1].Status, "TEST, failed.\nfailed action recorded")
+}
+
+//nolint:lll // test cases need to be readable
+func TestModeration_ActUnrecorded(t *testing.T) {
+	m, service := initializeTest(t)
+
+	m.accountStore.EXPECT().Get(gomock.Any(), &models.Account{User: models.User{ID: 1}}).Return(&models.Account{User: models.User{ID: 1}, Role: models.RoleModerator}, nil)
+	m.auditStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.DBError{})
+
+	// the blog is not hidden, as the action could not be recorded
+	output, err := service.Act(withUser(1), &models.ModerationAction{Action: models.ActionHideBlog, BlogID: "MSI8WKNSH9"})
+
+	assert.Equal(t, errors.DBError{}, err, "TEST, failed.\naction not recorded")
+	assert.Nil(t, output, "TEST, failed.\naction not recorded")
+}
+
+func TestModeration_GetWarnings(t *testing.T) {
+	m, service := initializeTest(t)
+
+	page := &models.Page{Limit: 20, PageNo: 1}
+
+	m.auditStore.EXPECT().GetAll(gomock.Any(), &models.ModerationFilter{Action: models.ActionWarn, UserID: 2, Status: models.ActionCompleted}, page).
+		Return([]*models.ModerationAction{{ID: "a1", Action: models.ActionWarn, ModeratorID: 1, UserID: 2, Note: "be kind"}}, nil)
+
+	warnings, err := service.GetWarnings(withUser(2), page)
+
+	assert.Equal(t, nil, err, "TEST, failed.\nwarnings of a user")
+	assert.Equal(t, []*models.ModerationAction{{ID: "a1", Action: models.ActionWarn, UserID: 2, Note: "be kind"}}, warnings, "TEST, failed.\nmoderators are not named")
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/fulltext"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+const maxUserQueryLength = 255 // length of the name columns
+
+type search struct {
+	searchIndex  stores.SearchIndex
+	accountStore stores.Account
+	blockStore   stores.Block
+	muteStore    stores.Mute
+}
+
+func New(searchIndex stores.SearchIndex, accountStore stores.Account, blockStore stores.Block, muteStore stores.Mute) services.Search {
+	return search{
+		searchIndex:  searchIndex,
+		accountStore: accountStore,
+		blockStore:   blockStore,
+		muteStore:    muteStore,
+	}
+}
+
+// Blogs retrieves the blogs that match the query, most relevant first.
+// As in listings, the blogs of the accounts the viewer blocks, is blocked by or mutes are left out.
+func (s search) Blogs(ctx *app.Context, query string, page *models.Page) ([]*models.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.MissingParam{Param: "q"}
+	}
+
+	terms := fulltext.Tokenize(query)
+	if len(terms) == 0 {
+		return nil, errors.InvalidParam{Param: "q"}
+	}
+
+	hidden, err := s.hidden(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.searchIndex.Search(ctx, query, hidden, page)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range hits {
+		hit.Highlights = highlight(hit.Blog, terms)
+	}
+
+	return hits, nil
+}
+
+// Users retrieves active users whose username, first name or last name starts with the query.
+func (s search) Users(ctx *app.Context, query string, page *models.Page) ([]*models.User, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return nil, errors.MissingParam{Param: "q"}
+	}
+
+	if len(query) > maxUserQueryLength {
+		return nil, errors.InvalidParam{Param: "q"}
+	}
+
+	accounts, err := s.accountStore.Search(ctx, query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.User, 0, len(accounts))
+
+	for _, account := range accounts {
+		users = append(users, &models.User{ID: account.ID, UserName: account.UserName, FName: account.FName, LName: account.LName})
+	}
+
+	return users, nil
+}
+
+// hidden resolves the accounts hidden from the viewer by a block or a mute. Nothing is hidden from anonymous viewers.
+func (s search) hidden(ctx *app.Context) ([]int64, error) {
+	claims, ok := ctx.Value(auth.JWTContextKey("claims")).(*auth.Claims)
+	if !ok || claims.UserID == 0 {
+		return nil, nil
+	}
+
+	hidden, err := s.blockStore.GetRelated(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	muted, err := s.muteStore.GetMuted(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range muted {
+		hidden = append(hidden, user.ID)
+	}
+
+	return hidden, nil
+}
+
+func highlight(blog *models.Blog, terms []string) map[string]string {
+	highlights := make(map[string]string)
+
+	fields := map[string]string{
+		"title":   blog.Title,
+		"summary": blog.Summary,
+		"content": blog.Content,
+		"tags":    strings.Join(blog.Tags, " "),
+	}
+
+	for field, text := range fields {
+		if fragment := fulltext.Highlight(text, terms); fragment != "" {
+			highlights[field] = fragment
+		}
+	}
+
+	return highlights
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/auth"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/services"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func initializeTest(t *testing.T) (*stores.MockSearchIndex, *stores.MockAccount, *app.Context, services.Search) {
+	ctrl := gomock.NewController(t)
+
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockAccountStore := stores.NewMockAccount(ctrl)
+	mockSearchService := New(mockSearchIndex, mockAccountStore, stores.NewMockBlock(ctrl), stores.NewMockMute(ctrl))
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+
+	return mockSearchIndex, mockAccountStore, ctx, mockSearchService
+}
+
+func TestSearch_Blogs(t *testing.T) {
+	mockSearchIndex, _, ctx, mockSearchService := initializeTest(t)
+
+	page := &models.Page{Limit: 3, PageNo: 1}
+	blog := &models.Blog{BlogID: "UMS672XR8J", Title: "coffee", Summary: "a blog on coffee", Content: "visit starbucks for best coffee!", Tags: []string{"#coffee"}}
+
+	mockSearchIndex.EXPECT().Search(gomock.Any(), "coffee", nil, page).Return([]*models.SearchHit{{Blog: blog, Score: 2}}, nil)
+	mockSearchIndex.EXPECT().Search(gomock.Any(), "music", nil, page).Return(nil, errors.DBError{})
+
+	tests := []struct {
+		description string
+		query       string
+		output      []*models.SearchHit
+		err         error
+	}{
+		{description: "empty query", query: " ", err: errors.MissingParam{Param: "q"}},
+		{description: "query with stop words only", query: "the", err: errors.InvalidParam{Param: "q"}},
+		{description: "database error", query: "music", err: errors.DBError{}},
+		{
+			description: "success case",
+			query:       "coffee",
+			output: []*models.SearchHit{{Blog: blog, Score: 2, Highlights: map[string]string{
+				"title":   "<em>coffee</em>",
+				"summary": "a blog on <em>coffee</em>",
+				"content": "visit starbucks for best <em>coffee</em>!",
+				"tags":    "#<em>coffee</em>",
+			}}},
+		},
+	}
+
+	for i, tc := range tests {
+		output, err := mockSearchService.Blogs(ctx, tc.query, page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestSearch_BlogsHidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSearchIndex := stores.NewMockSearchIndex(ctrl)
+	mockBlockStore := stores.NewMockBlock(ctrl)
+	mockMuteStore := stores.NewMockMute(ctrl)
+	mockSearchService := New(mockSearchIndex, stores.NewMockAccount(ctrl), mockBlockStore, mockMuteStore)
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.WithValue(context.TODO(), auth.JWTContextKey("claims"), &auth.Claims{UserID: 1})
+
+	page := &models.Page{Limit: 3, PageNo: 1}
+
+	mockBlockStore.EXPECT().GetRelated(gomock.Any(), int64(1)).Return([]int64{2}, nil)
+	mockMuteStore.EXPECT().GetMuted(gomock.Any(), int64(1)).Return([]*models.User{{ID: 3}}, nil)
+	mockSearchIndex.EXPECT().Search(gomock.Any(), "coffee", []int64{2, 3}, page).Return([]*models.SearchHit{}, nil)
+
+	mockBlockStore.EXPECT().GetRelated(gomock.Any(), int64(1)).Return(nil, errors.DBError{})
+
+	tests := []struct {
+		description string
+		output      []*models.SearchHit
+		err         error
+	}{
+		{description: "blocked and muted accounts are hidden", output: []*models.SearchHit{}},
+		{description: "database error", err: errors.DBError{}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockSearchService.Blogs(ctx, "coffee", page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestSearch_Users(t *testing.T) {
+	_, mockAccountStore, ctx, mockSearchService := initializeTest(t)
+
+	page := &models.Page{Limit: 3, PageNo: 1}
+
+	mockAccountStore.EXPECT().Search(gomock.Any(), "mainak pan", page).
+		Return([]*models.Account{{User: models.User{ID: 2, UserName: "mainak_pandit", FName: "Mainak", LName: "Pandit"}, Status: "ACTIVE"}}, nil)
+	mockAccountStore.EXPECT().Search(gomock.Any(), "divij", page).Return(nil, errors.DBError{})
+
+	tests := []struct {
+		description string
+		query       string
+		output      []*models.User
+		err         error
+	}{
+		{description: "empty query", query: "  ", err: errors.MissingParam{Param: "q"}},
+		{description: "database error", query: "divij", err: errors.DBError{}},
+		{
+			description: "extra whitespace is collapsed",
+			query:       " mainak   pan ",
+			output:      []*models.User{{ID: 2, UserName: "mainak_pandit", FName: "Mainak", LName: "Pandit"}},
+		},
+	}
+
+	for i, tc := range tests {
+		output, err := mockSearchService.Users(ctx, tc.query, page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
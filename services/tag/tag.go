@@ -3,6 +3,8 @@ package tag
 import (
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
@@ -14,12 +16,42 @@ import (
 	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
 )
 
+const (
+	maxSuggestions   = 10
+	maxTrending      = 20
+	trendingCacheTTL = 5 * time.Minute
+)
+
+//nolint:gochecknoglobals // supported windows of the trending tags, keyed by their query param value
+var trendingWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+//nolint:gochecknoglobals // compiled once, used for every autocomplete request
+var tagPattern = regexp.MustCompile(`^[0-9A-Za-z_]+$`)
+
 type tag struct {
-	store stores.Tag
+	store    stores.Tag
+	trending *trendingCache
+}
+
+// trendingCache holds the computed trending tags of each window until they expire.
+type trendingCache struct {
+	mu      sync.Mutex
+	entries map[string]trendingEntry
+}
+
+type trendingEntry struct {
+	tags    []*models.TrendingTag
+	expires time.Time
 }
 
 func New(store stores.Tag) services.Tag {
-	return tag{store: store}
+	return tag{
+		store:    store,
+		trending: &trendingCache{entries: make(map[string]trendingEntry)},
+	}
 }
 
 func (t tag) Get(c *app.Context, name string) (*models.Tag, error) {
@@ -33,11 +65,79 @@ func (t tag) Get(c *app.Context, name string) (*models.Tag, error) {
 
 		return nil, err
 	default:
+		tag.BlogCount = int64(len(tag.BlogIDList))
+
 		return tag, nil
 	}
 }
 
-func (t tag) AddBlogID(c *app.Context, blogID string, tags []string) {
+// GetAll retrieves the tag directory, sorted by popularity unless sorting by name is asked for.
+func (t tag) GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error) {
+	switch sortBy {
+	case "":
+		sortBy = models.TagSortPopularity
+	case models.TagSortPopularity, models.TagSortName:
+	default:
+		return nil, errors.InvalidParam{Param: "sort"}
+	}
+
+	return t.store.GetAll(c, page, sortBy)
+}
+
+// Autocomplete suggests the most popular tags that start with the prefix.
+// The leading '#' of the prefix is optional.
+func (t tag) Autocomplete(c *app.Context, prefix string) ([]*models.Tag, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "#")
+	if prefix == "" {
+		return nil, errors.MissingParam{Param: "prefix"}
+	}
+
+	if !tagPattern.MatchString(prefix) {
+		return nil, errors.InvalidParam{Param: "prefix"}
+	}
+
+	return t.store.GetByPrefix(c, "#"+prefix, maxSuggestions)
+}
+
+// Trending retrieves the tags whose usage grew the fastest within the window, compared to the window before it.
+// Results are cached for a few minutes, as the aggregation scans every usage event of both windows.
+func (t tag) Trending(c *app.Context, window string) ([]*models.TrendingTag, error) {
+	if window == "" {
+		window = "24h"
+	}
+
+	duration, ok := trendingWindows[window]
+	if !ok {
+		return nil, errors.InvalidParam{Param: "window"}
+	}
+
+	now := time.Now()
+
+	t.trending.mu.Lock()
+	entry, ok := t.trending.entries[window]
+	t.trending.mu.Unlock()
+
+	if ok && now.Before(entry.expires) {
+		return entry.tags, nil
+	}
+
+	tags, err := t.store.GetTrending(c, now.Add(-duration), now.Add(-2*duration), maxTrending)
+	if err != nil {
+		return nil, err
+	}
+
+	t.trending.mu.Lock()
+	t.trending.entries[window] = trendingEntry{tags: tags, expires: now.Add(trendingCacheTTL)}
+	t.trending.mu.Unlock()
+
+	return tags, nil
+}
+
+// AddBlogID adds a blog to its tags, creating the tags that do not exist yet.
+// Invalid tags are skipped. Every tag is tried, and the first failure is returned.
+func (t tag) AddBlogID(c *app.Context, blogID string, tags []string) error {
+	var failure error
+
 	for i := range tags {
 		// validate tag name
 		if !validateTag(tags[i]) {
@@ -52,20 +152,41 @@ func (t tag) AddBlogID(c *app.Context, blogID string, tags []string) {
 			// create tag if it does not exist already
 			if err = t.store.Create(c, &models.Tag{Name: tags[i], BlogIDList: []string{blogID}}); err != nil {
 				c.Logger.Errorf("cannot create tag %s: %s", tags[i], err.Error())
+				failure = first(failure, err)
+
+				continue
 			}
 		case nil:
 			// update tag if it exists
 			if err = t.store.Update(c, blogID, tags[i], constants.Add); err != nil {
 				c.Logger.Errorf("cannot update tag %s: %s", tags[i], err.Error())
+				failure = first(failure, err)
+
+				continue
 			}
 		default:
 			c.Logger.Errorf("cannot find tag %s: %s", tags[i], err.Error())
+			failure = first(failure, err)
+
+			continue
+		}
+
+		// record the usage for trending tags
+		if err = t.store.AddEvent(c, &models.TagEvent{Tag: tags[i], BlogID: blogID, CreatedOn: time.Now()}); err != nil {
+			c.Logger.Errorf("cannot record usage of tag %s: %s", tags[i], err.Error())
 		}
 	}
+
+	return failure
 }
 
+// RemoveBlogID removes a blog from its tags, along with its usages of them, deleting the tags that are left without blogs.
+// Invalid and missing tags are skipped. Every tag is tried, and the first failure is returned.
+//
 //nolint:gocognit // hampers readability of code
-func (t tag) RemoveBlogID(c *app.Context, blogID string, tags []string) {
+func (t tag) RemoveBlogID(c *app.Context, blogID string, tags []string) error {
+	var failure error
+
 	for i := range tags {
 		// validate tag name
 		if !validateTag(tags[i]) {
@@ -76,15 +197,29 @@ func (t tag) RemoveBlogID(c *app.Context, blogID string, tags []string) {
 		_, err := t.Get(c, tags[i])
 		if err != nil {
 			c.Logger.Errorf("cannot find tag %s: %s", tags[i], err.Error())
+
+			// a tag that does not exist has no blog to remove
+			if _, ok := err.(errors.EntityNotFound); !ok {
+				failure = first(failure, err)
+			}
+
 			continue
 		}
 
 		// update tag if it exists
 		if err = t.store.Update(c, blogID, tags[i], constants.Remove); err != nil {
 			c.Logger.Errorf("cannot update tag %s: %s", tags[i], err.Error())
+			failure = first(failure, err)
+
 			continue
 		}
 
+		// a tag taken off a blog is not trending by it
+		if err = t.store.DeleteEvents(c, tags[i], blogID); err != nil {
+			c.Logger.Errorf("cannot remove usage of tag %s: %s", tags[i], err.Error())
+		}
+
+		// the blog is removed already, an empty tag left behind is harmless
 		tag, err := t.Get(c, tags[i])
 		if err != nil {
 			continue
@@ -96,6 +231,17 @@ func (t tag) RemoveBlogID(c *app.Context, blogID string, tags []string) {
 			}
 		}
 	}
+
+	return failure
+}
+
+// first returns the failure recorded already, if any, else err.
+func first(failure, err error) error {
+	if failure != nil {
+		return failure
+	}
+
+	return err
 }
 
 func validateTag(name string) bool {
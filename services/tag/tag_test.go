@@ -45,7 +45,7 @@ func TestTag_Get(t *testing.T) {
 	}{
 		{description: "get tag (database error)", input: "#def", err: errors.DBError{}},
 		{description: "get tag that does not exist", input: "#abc", err: errors.EntityNotFound{Entity: "tag", ID: "#abc"}},
-		{description: "get tag with valid name", input: "#trending", output: &models.Tag{Name: "#trending", BlogIDList: []string{"UMS672XR8J", "ABK7SH2V37", "MSI8NS2909", "POQA7B2J7X"}}},
+		{description: "get tag with valid name", input: "#trending", output: &models.Tag{Name: "#trending", BlogIDList: []string{"UMS672XR8J", "ABK7SH2V37", "MSI8NS2909", "POQA7B2J7X"}, BlogCount: 4}},
 	}
 
 	for i, tc := range tests {
@@ -68,20 +68,23 @@ func TestTag_AddBlogID(t *testing.T) {
 		blogID      string
 		tags        []string
 		output      []string
+		err         error
 	}{
 		{description: "success case", blogID: "TEST_ID", tags: []string{"#tag5"}},
 		{description: "invalid tag", blogID: "TEST_ID", tags: []string{"demo-tag"}, output: []string{"invalid tag demo-tag"}},
-		{description: "create error", blogID: "TEST_ID", tags: []string{"#tag1"}, output: []string{"cannot create tag #tag1"}},
-		{description: "create and invalid tag error", blogID: "TEST_ID", tags: []string{"#tag1", "tag2"}, output: []string{"cannot create tag #tag1", "invalid tag tag2"}},
-		{description: "update, create and invalid tag error", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2"}, output: []string{"cannot update tag #tag3", "cannot create tag #tag1", "invalid tag tag2"}},
-		{description: "update, create, invalid tag error and find error", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2", "#tag4"}, output: []string{"cannot update tag #tag3", "cannot create tag #tag1", "invalid tag tag2", "cannot find tag #tag4"}},
+		{description: "create error", blogID: "TEST_ID", tags: []string{"#tag1"}, output: []string{"cannot create tag #tag1"}, err: errors.DBError{}},
+		{description: "create and invalid tag error", blogID: "TEST_ID", tags: []string{"#tag1", "tag2"}, output: []string{"cannot create tag #tag1", "invalid tag tag2"}, err: errors.DBError{}},
+		{description: "update, create and invalid tag error", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2"}, output: []string{"cannot update tag #tag3", "cannot create tag #tag1", "invalid tag tag2"}, err: errors.DBError{}},
+		{description: "update, create, invalid tag error and find error", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2", "#tag4"}, output: []string{"cannot update tag #tag3", "cannot create tag #tag1", "invalid tag tag2", "cannot find tag #tag4"}, err: errors.DBError{}},
 	}
 
 	for i, tc := range tests {
 		b := new(bytes.Buffer)
 		ctx.Logger = log.NewMockLogger(b)
 
-		mockService.AddBlogID(ctx, tc.blogID, tc.tags)
+		err := mockService.AddBlogID(ctx, tc.blogID, tc.tags)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
 
 		if len(tc.output) == 0 {
 			if b.String() != "" {
@@ -100,6 +103,8 @@ func TestTag_AddBlogID(t *testing.T) {
 }
 
 func registerAddMockCalls(mockStore *stores.MockTag) {
+	mockStore.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
 	mockStore.EXPECT().Get(gomock.Any(), "#tag1").Return(nil, errors.DBError{Err: mongo.ErrNoDocuments}).AnyTimes()
 	mockStore.EXPECT().Create(gomock.Any(), &models.Tag{Name: "#tag1", BlogIDList: []string{"TEST_ID"}}).AnyTimes().Return(errors.DBError{})
 
@@ -123,6 +128,7 @@ func TestTag_RemoveBlogID(t *testing.T) {
 		blogID      string
 		tags        []string
 		output      []string
+		err         error
 	}{
 		{description: "success case", blogID: "TEST_ID", tags: []string{"#tag4"}, output: []string(nil)},
 		{description: "invalid tag", blogID: "TEST_ID", tags: []string{""}, output: []string{"invalid tag "}},
@@ -130,14 +136,18 @@ func TestTag_RemoveBlogID(t *testing.T) {
 		{description: "get returns error", blogID: "TEST_ID", tags: []string{"#tag1"}, output: []string{"cannot find tag #tag1"}},
 		{description: "delete returns error", blogID: "TEST_ID", tags: []string{"#tag5"}, output: []string{"cannot remove tag #tag5"}},
 		{description: "get tag returns error, invalid tag name", blogID: "TEST_ID", tags: []string{"#tag1", "tag2"}, output: []string{"cannot find tag #tag1", "invalid tag tag2"}},
-		{description: "update and find return error, invalid tag name", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2"}, output: []string{"cannot update tag #tag3", "cannot find tag #tag1", "invalid tag tag2"}},
+		{description: "update and find return error, invalid tag name", blogID: "TEST_ID", tags: []string{"#tag3", "#tag1", "tag2"}, output: []string{"cannot update tag #tag3", "cannot find tag #tag1", "invalid tag tag2"}, err: errors.DBError{}},
+		{description: "find returns database error", blogID: "TEST_ID", tags: []string{"#tag7"}, output: []string{"cannot find tag #tag7"}, err: errors.DBError{}},
+		{description: "usage of the tag cannot be removed", blogID: "TEST_ID", tags: []string{"#tag8"}, output: []string{"cannot remove usage of tag #tag8"}},
 	}
 
 	for i, tc := range tests {
 		b := new(bytes.Buffer)
 		ctx.Logger = log.NewMockLogger(b)
 
-		mockService.RemoveBlogID(ctx, tc.blogID, tc.tags)
+		err := mockService.RemoveBlogID(ctx, tc.blogID, tc.tags)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
 
 		if len(tc.output) == 0 {
 			if b.String() != "" {
@@ -156,6 +166,11 @@ func TestTag_RemoveBlogID(t *testing.T) {
 }
 
 func registerRemoveMockCalls(mockStore *stores.MockTag) {
+	mockStore.EXPECT().Get(gomock.Any(), "#tag8").Return(&models.Tag{Name: "#tag8", BlogIDList: []string{"TEST_ID", "TEST_ID1"}}, nil).Times(2)
+	mockStore.EXPECT().Update(gomock.Any(), "TEST_ID", "#tag8", constants.Remove).Return(nil)
+	mockStore.EXPECT().DeleteEvents(gomock.Any(), "#tag8", "TEST_ID").Return(errors.DBError{})
+	mockStore.EXPECT().DeleteEvents(gomock.Any(), gomock.Any(), "TEST_ID").Return(nil).AnyTimes()
+
 	mockStore.EXPECT().Get(gomock.Any(), "#tag1").Return(nil, errors.DBError{Err: mongo.ErrNoDocuments}).AnyTimes()
 
 	mockStore.EXPECT().Get(gomock.Any(), "#tag3").Return(&models.Tag{Name: "#tag3", BlogIDList: []string{"TAG_ID"}}, nil).AnyTimes()
@@ -174,4 +189,94 @@ func registerRemoveMockCalls(mockStore *stores.MockTag) {
 	mockStore.EXPECT().Get(gomock.Any(), "#tag6").Return(&models.Tag{Name: "#tag6", BlogIDList: []string{"TEST_ID", "TEST_ID1"}}, nil)
 	mockStore.EXPECT().Update(gomock.Any(), "TEST_ID", "#tag6", constants.Remove).Return(nil)
 	mockStore.EXPECT().Get(gomock.Any(), "#tag6").Return(nil, errors.DBError{})
+
+	mockStore.EXPECT().Get(gomock.Any(), "#tag7").Return(nil, errors.DBError{})
+}
+
+func TestTag_GetAll(t *testing.T) {
+	mockStore, mockService, ctx := initializeTest(t)
+
+	page := &models.Page{Limit: 2, PageNo: 1}
+	output := []*models.Tag{{Name: "#life", BlogCount: 4}, {Name: "#trending", BlogCount: 4}}
+
+	mockStore.EXPECT().GetAll(gomock.Any(), page, models.TagSortPopularity).Return(output, nil).Times(2)
+	mockStore.EXPECT().GetAll(gomock.Any(), page, models.TagSortName).Return(nil, errors.DBError{})
+
+	tests := []struct {
+		description string
+		sortBy      string
+		output      []*models.Tag
+		err         error
+	}{
+		{description: "sort by popularity by default", sortBy: "", output: output},
+		{description: "sort by popularity", sortBy: "popularity", output: output},
+		{description: "sort by name (database error)", sortBy: "name", err: errors.DBError{}},
+		{description: "invalid sort", sortBy: "date", err: errors.InvalidParam{Param: "sort"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockService.GetAll(ctx, page, tc.sortBy)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestTag_Autocomplete(t *testing.T) {
+	mockStore, mockService, ctx := initializeTest(t)
+
+	output := []*models.Tag{{Name: "#love", BlogCount: 3}, {Name: "#life", BlogCount: 4}}
+
+	mockStore.EXPECT().GetByPrefix(gomock.Any(), "#l", int64(10)).Return(output, nil).Times(2)
+
+	tests := []struct {
+		description string
+		prefix      string
+		output      []*models.Tag
+		err         error
+	}{
+		{description: "prefix without #", prefix: "l", output: output},
+		{description: "prefix with #", prefix: " #l ", output: output},
+		{description: "empty prefix", prefix: "#", err: errors.MissingParam{Param: "prefix"}},
+		{description: "invalid prefix", prefix: "l.*", err: errors.InvalidParam{Param: "prefix"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockService.Autocomplete(ctx, tc.prefix)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestTag_Trending(t *testing.T) {
+	mockStore, mockService, ctx := initializeTest(t)
+
+	output := []*models.TrendingTag{{Name: "#coffee", Count: 4, BaselineCount: 1, Growth: 3}}
+
+	// the result of each window is cached, the store is called once per window
+	mockStore.EXPECT().GetTrending(gomock.Any(), gomock.Any(), gomock.Any(), int64(20)).Return(output, nil).Times(1)
+	mockStore.EXPECT().GetTrending(gomock.Any(), gomock.Any(), gomock.Any(), int64(20)).Return(nil, errors.DBError{}).Times(1)
+
+	tests := []struct {
+		description string
+		window      string
+		output      []*models.TrendingTag
+		err         error
+	}{
+		{description: "default window", window: "", output: output},
+		{description: "cached window", window: "24h", output: output},
+		{description: "database error", window: "7d", err: errors.DBError{}},
+		{description: "invalid window", window: "1y", err: errors.InvalidParam{Param: "window"}},
+	}
+
+	for i, tc := range tests {
+		output, err := mockService.Trending(ctx, tc.window)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
 }
@@ -14,14 +14,22 @@ import (
 
 type account struct{}
 
+const maxSearchResults = 100
+
 func New() stores.Account {
 	return account{}
 }
 
 const (
-	getAll = "SELECT id, user_name, email, f_name, l_name, phone_no, created_at, pwd_update, del_req, status FROM accounts WHERE "
-	get    = "SELECT id, user_name, email, password, f_name, l_name, phone_no, created_at, pwd_update, del_req, status FROM accounts WHERE "
+	getAll = "SELECT id, user_name, email, f_name, l_name, phone_no, created_at, pwd_update, del_req, status, role FROM accounts WHERE "
+	get    = "SELECT id, user_name, email, password, f_name, l_name, phone_no, created_at, pwd_update, del_req, status, role FROM accounts WHERE "
 	insert = "INSERT INTO accounts( user_name, password, email, f_name, l_name, phone_no, status) VALUES(?, ?, ?, ?, ?, ?, ?)"
+
+	// search matches a prefix of the username or of either name, or the full name when the prefix has two words.
+	// Rows are ranked by exact username match, then exact name match. The default collation makes the comparisons case-insensitive.
+	search = "SELECT id, user_name, f_name, l_name, created_at, status FROM accounts " +
+		"WHERE status = 'ACTIVE' AND del_req IS NULL AND (user_name LIKE ? OR f_name LIKE ? OR l_name LIKE ? OR (f_name LIKE ? AND l_name LIKE ?)) " +
+		"ORDER BY user_name = ? DESC, (f_name = ? OR l_name = ? OR CONCAT(f_name, ' ', l_name) = ?) DESC, user_name LIMIT ? OFFSET ?"
 )
 
 // GetAll retrieves all accounts that match the given filter.
@@ -40,7 +48,7 @@ func (a account) GetAll(ctx *app.Context, filter *models.Account) ([]*models.Acc
 
 	for rows.Next() {
 		var account models.Account
-		err := rows.Scan(&account.ID, &account.UserName, &account.Email, &account.FName, &account.LName, &account.PhoneNo, &account.CreatedAt, &account.PwdUpdate, &account.DelRequest, &account.Status)
+		err := rows.Scan(&account.ID, &account.UserName, &account.Email, &account.FName, &account.LName, &account.PhoneNo, &account.CreatedAt, &account.PwdUpdate, &account.DelRequest, &account.Status, &account.Role)
 
 		if err != nil {
 			return nil, errors.DBError{Err: err}
@@ -71,7 +79,7 @@ func (a account) Get(ctx *app.Context, filter *models.Account) (*models.Account,
 	var account models.Account
 
 	for rows.Next() {
-		err := rows.Scan(&account.ID, &account.UserName, &account.Email, &account.Password, &account.FName, &account.LName, &account.PhoneNo, &account.CreatedAt, &account.PwdUpdate, &account.DelRequest, &account.Status)
+		err := rows.Scan(&account.ID, &account.UserName, &account.Email, &account.Password, &account.FName, &account.LName, &account.PhoneNo, &account.CreatedAt, &account.PwdUpdate, &account.DelRequest, &account.Status, &account.Role)
 		if err != nil {
 			return nil, errors.DBError{Err: err}
 		}
@@ -178,10 +186,60 @@ func generateSetClause(model *models.Account) (setClause string, qp []interface{
 	return setClause, qp
 }
 
+// Search retrieves active accounts whose username, first name or last name starts with the prefix.
+func (a account) Search(ctx *app.Context, prefix string, page *models.Page) ([]*models.Account, error) {
+	pattern := escapeLike(prefix) + "%"
+
+	// for a prefix like "mainak pan", match first name "mainak" and a last name starting with "pan"
+	first, last := pattern, pattern
+	if i := strings.Index(prefix, " "); i > 0 {
+		first, last = escapeLike(prefix[:i]), escapeLike(strings.TrimSpace(prefix[i+1:]))+"%"
+	}
+
+	limit, offset := int64(maxSearchResults), int64(0)
+	if page != nil && page.Limit > 0 {
+		limit, offset = page.Limit, page.Skip()
+	}
+
+	rows, err := ctx.SQL.QueryContext(ctx, search, pattern, pattern, pattern, first, last, prefix, prefix, prefix, prefix, limit, offset)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer rows.Close()
+
+	accounts := make([]*models.Account, 0)
+
+	for rows.Next() {
+		var account models.Account
+
+		err := rows.Scan(&account.ID, &account.UserName, &account.FName, &account.LName, &account.CreatedAt, &account.Status)
+		if err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		accounts = append(accounts, &account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return accounts, nil
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern, so that they are matched literally.
+// Usernames commonly contain '_', which would otherwise match any character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 // Delete updates a delete request for an account and sets its status to inactive.
 // Account is then permanently deleted after 30 days of inactivity.
+// A suspended account is left as it is, so that its suspension is not lifted by logging in again.
 func (a account) Delete(ctx *app.Context, id int64) error {
-	_, err := ctx.SQL.ExecContext(ctx, "UPDATE accounts SET del_req = ?, status = ? WHERE id = ?", time.Now(), "INACTIVE", id)
+	_, err := ctx.SQL.ExecContext(ctx, "UPDATE accounts SET del_req = ?, status = ? WHERE id = ? AND status <> ?",
+		time.Now(), "INACTIVE", id, models.AccountSuspended)
 	if err != nil {
 		return errors.DBError{Err: err}
 	}
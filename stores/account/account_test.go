@@ -1,5 +1,16 @@
 package account
 
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
 //
 //import (
 //	"context"
@@ -60,3 +71,51 @@ package account
 //		{User: models.User{ID: 3, UserName: "divij_gupta", FName: "Divij", LName: "Gupta", Email: sql.NullString{String: "divijgupta@gmail.com", Valid: true}, PhoneNo: sql.NullString{String: "9682622125", Valid: true}}, CreatedAt: time.Now().In(loc), Status: "ACTIVE"},
 //	}
 //}
+
+// initializeSearchTest creates the accounts aakanksha_jais, mainak_pandit and divij_gupta,
+// along with mainak (Mai Nak) and a_mainak (Mainaka Sen), whose names match the same searches.
+func initializeSearchTest(t *testing.T) (*app.Context, stores.Account) {
+	test.InitializeTestAccountsTable(a.SQL.DB, a.Logger, "../../db")
+
+	ctx := &app.Context{Context: context.TODO(), App: a}
+
+	for _, name := range [][3]string{{"mainak", "Mai", "Nak"}, {"a_mainak", "Mainaka", "Sen"}} {
+		_, err := ctx.SQL.ExecContext(ctx, "INSERT INTO accounts (user_name, password, f_name, l_name) VALUES (?, ?, ?, ?)",
+			name[0], "password", name[1], name[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return ctx, New()
+}
+
+//nolint:lll // test cases need to be readable
+func TestAccount_Search(t *testing.T) {
+	ctx, account := initializeSearchTest(t)
+
+	tests := []struct {
+		description string
+		prefix      string
+		output      []string
+	}{
+		{description: "exact username first, then exact names, then prefixes, in any case", prefix: "MAINAK", output: []string{"mainak", "mainak_pandit", "a_mainak"}},
+		{description: "first name and prefix of the last name", prefix: "mainak pan", output: []string{"mainak_pandit"}},
+		{description: "underscore is matched literally", prefix: "a_", output: []string{"a_mainak"}},
+		{description: "percent sign is matched literally", prefix: "%nak", output: []string{}},
+		{description: "no match", prefix: "zed", output: []string{}},
+	}
+
+	for i, tc := range tests {
+		accounts, err := account.Search(ctx, tc.prefix, nil)
+
+		usernames := make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			usernames = append(usernames, account.UserName)
+		}
+
+		assert.Equal(t, tc.output, usernames, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
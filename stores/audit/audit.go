@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type audit struct {
+	indexes *datastore.MongoIndexes
+}
+
+// New returns an audit store, the trail of moderation actions. Actions are inserted before they are taken,
+// updated only with the status they end in, never deleted, and read by the account or the blog they are taken on.
+func New() stores.Audit {
+	return audit{indexes: datastore.NewMongoIndexes("moderation_actions",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_on", Value: -1}},
+			Options: options.Index().SetName("moderation_user_created_on"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "created_on", Value: -1}},
+			Options: options.Index().SetName("moderation_blog_created_on").SetSparse(true),
+		},
+	)}
+}
+
+// GetAll retrieves the moderation actions that match the filter, latest first.
+func (a audit) GetAll(c *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error) {
+	if filter == nil {
+		filter = &models.ModerationFilter{}
+	}
+
+	collection := c.Mongo.Collection("moderation_actions")
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_on", Value: -1}, {Key: "_id", Value: -1}})
+
+	if page != nil {
+		opts = opts.SetSkip(page.Skip()).SetLimit(page.Limit)
+	}
+
+	cursor, err := collection.Find(c, filter.GetFilter(), opts)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	actions := make([]*models.ModerationAction, 0)
+
+	for cursor.Next(c) {
+		var action models.ModerationAction
+
+		if err = cursor.Decode(&action); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		actions = append(actions, &action)
+	}
+
+	if err = cursor.Close(c); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return actions, nil
+}
+
+// Create records a moderation action.
+func (a audit) Create(c *app.Context, model *models.ModerationAction) error {
+	if err := a.indexes.Ensure(c, c.Mongo); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("moderation_actions")
+
+	if _, err := collection.InsertOne(c, model); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// SetStatus records the status a moderation action ended in, along with the user it was taken on,
+// which is known once a blog is acted upon.
+func (a audit) SetStatus(c *app.Context, model *models.ModerationAction) error {
+	collection := c.Mongo.Collection("moderation_actions")
+
+	res, err := collection.UpdateOne(c, bson.M{"_id": model.ID},
+		bson.M{"$set": bson.M{"status": model.Status, "user_id": model.UserID}})
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.EntityNotFound{Entity: "moderation action", ID: model.ID}
+	}
+
+	return nil
+}
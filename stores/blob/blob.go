@@ -0,0 +1,115 @@
+package blob
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type blob struct{}
+
+// New returns a blob store. References are counted by atomic increments, and a blob is deleted
+// through a conditional delete once it has none, so that a blob referenced meanwhile is kept.
+func New() stores.Blob {
+	return blob{}
+}
+
+// Acquire adds a reference to a blob that is referenced already, and returns it.
+func (b blob) Acquire(c *app.Context, hash string) (*models.Blob, error) {
+	collection := c.Mongo.Collection("blobs")
+
+	filter := bson.D{{Key: "_id", Value: hash}, {Key: "refs", Value: bson.M{"$gt": 0}}}
+	update := bson.M{"$inc": bson.M{"refs": 1}}
+
+	res := collection.FindOneAndUpdate(c, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	return decode(res, hash)
+}
+
+// Add adds a reference to a blob, creating it with its image if it does not exist.
+// Of blobs of the same content added at once, the image of the first is kept.
+func (b blob) Add(c *app.Context, model *models.Blob) (*models.Blob, error) {
+	collection := c.Mongo.Collection("blobs")
+
+	update := bson.M{
+		"$inc":         bson.M{"refs": 1},
+		"$setOnInsert": bson.M{"image": model.Image, "created_on": model.CreatedOn},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return decode(collection.FindOneAndUpdate(c, bson.D{{Key: "_id", Value: model.Hash}}, update, opts), model.Hash)
+}
+
+// Release removes a reference from a blob, and returns it.
+func (b blob) Release(c *app.Context, hash string) (*models.Blob, error) {
+	collection := c.Mongo.Collection("blobs")
+
+	filter := bson.D{{Key: "_id", Value: hash}, {Key: "refs", Value: bson.M{"$gt": 0}}}
+	update := bson.M{"$inc": bson.M{"refs": -1}}
+
+	res := collection.FindOneAndUpdate(c, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	return decode(res, hash)
+}
+
+// Delete removes a blob that has no references left.
+func (b blob) Delete(c *app.Context, hash string) error {
+	collection := c.Mongo.Collection("blobs")
+
+	filter := bson.D{{Key: "_id", Value: hash}, {Key: "refs", Value: bson.M{"$lte": 0}}}
+
+	_, err := decode(collection.FindOneAndDelete(c, filter), hash)
+
+	return err
+}
+
+// Each calls fn with every blob as it is read.
+func (b blob) Each(c *app.Context, fn func(blob *models.Blob)) error {
+	collection := c.Mongo.Collection("blobs")
+
+	cursor, err := collection.Find(c, bson.D{})
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	defer cursor.Close(c)
+
+	for cursor.Next(c) {
+		var blob models.Blob
+
+		if err = cursor.Decode(&blob); err != nil {
+			return errors.DBError{Err: err}
+		}
+
+		fn(&blob)
+	}
+
+	if err = cursor.Err(); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+func decode(res *mongo.SingleResult, hash string) (*models.Blob, error) {
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "blob", ID: hash}
+	default:
+		return nil, errors.DBError{Err: err}
+	}
+
+	var blob models.Blob
+
+	if err := res.Decode(&blob); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &blob, nil
+}
@@ -0,0 +1,77 @@
+package blob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func initializeTest() (*app.Context, stores.Blob) {
+	ctx := &app.Context{Context: context.TODO(), App: a}
+	_ = ctx.Mongo.Collection("blobs").Drop(ctx)
+
+	return ctx, New()
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlob_References(t *testing.T) {
+	ctx, blob := initializeTest()
+
+	createdOn := time.Now().Truncate(time.Millisecond).UTC()
+	image := models.Image{Hash: "aaa", Full: models.Rendition{URL: "http://localhost:8000/images/aaa_full.jpg", Width: 40, Height: 30}}
+	blobWith := func(refs int64) *models.Blob {
+		return &models.Blob{Hash: "aaa", Image: image, Refs: refs, CreatedOn: createdOn}
+	}
+
+	tests := []struct {
+		description string
+		run         func() (*models.Blob, error)
+		output      *models.Blob
+		err         error
+	}{
+		{description: "acquire a missing blob", run: func() (*models.Blob, error) { return blob.Acquire(ctx, "aaa") }, err: errors.EntityNotFound{Entity: "blob", ID: "aaa"}},
+		{description: "add a new blob", run: func() (*models.Blob, error) { return blob.Add(ctx, blobWith(0)) }, output: blobWith(1)},
+		{description: "add a blob of the same content, the first image is kept", run: func() (*models.Blob, error) {
+			return blob.Add(ctx, &models.Blob{Hash: "aaa", Image: models.Image{Hash: "aaa"}, CreatedOn: createdOn.Add(time.Hour)})
+		}, output: blobWith(2)},
+		{description: "acquire a blob", run: func() (*models.Blob, error) { return blob.Acquire(ctx, "aaa") }, output: blobWith(3)},
+		{description: "blob still referenced cannot be deleted", run: func() (*models.Blob, error) { return nil, blob.Delete(ctx, "aaa") }, err: errors.EntityNotFound{Entity: "blob", ID: "aaa"}},
+		{description: "release a reference", run: func() (*models.Blob, error) { return blob.Release(ctx, "aaa") }, output: blobWith(2)},
+		{description: "release a reference", run: func() (*models.Blob, error) { return blob.Release(ctx, "aaa") }, output: blobWith(1)},
+		{description: "release the last reference", run: func() (*models.Blob, error) { return blob.Release(ctx, "aaa") }, output: blobWith(0)},
+		{description: "release a blob without references", run: func() (*models.Blob, error) { return blob.Release(ctx, "aaa") }, err: errors.EntityNotFound{Entity: "blob", ID: "aaa"}},
+		{description: "acquire a blob without references", run: func() (*models.Blob, error) { return blob.Acquire(ctx, "aaa") }, err: errors.EntityNotFound{Entity: "blob", ID: "aaa"}},
+		{description: "delete a blob without references", run: func() (*models.Blob, error) { return nil, blob.Delete(ctx, "aaa") }},
+		{description: "delete a missing blob", run: func() (*models.Blob, error) { return nil, blob.Delete(ctx, "aaa") }, err: errors.EntityNotFound{Entity: "blob", ID: "aaa"}},
+	}
+
+	for i, tc := range tests {
+		output, err := tc.run()
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBlob_Each(t *testing.T) {
+	ctx, blob := initializeTest()
+
+	added, _ := blob.Add(ctx, &models.Blob{Hash: "aaa", Image: models.Image{Hash: "aaa"}, CreatedOn: time.Now().Truncate(time.Millisecond).UTC()})
+
+	blobs := make([]*models.Blob, 0)
+
+	err := blob.Each(ctx, func(b *models.Blob) {
+		blobs = append(blobs, b)
+	})
+
+	assert.Equal(t, []*models.Blob{added}, blobs, "TEST, failed.\nall blobs")
+	assert.Equal(t, nil, err, "TEST, failed.\nall blobs")
+}
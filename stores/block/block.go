@@ -0,0 +1,110 @@
+package block
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+type block struct{}
+
+// New returns a block store, on the blocks table. A block is keyed by (blocker_id, blocked_id),
+// and the accounts that block an account are looked up by blocked_id.
+func New() stores.Block {
+	return block{}
+}
+
+const (
+	insert     = "INSERT IGNORE INTO blocks (blocker_id, blocked_id) VALUES (?, ?)"
+	remove     = "DELETE FROM blocks WHERE blocker_id = ? AND blocked_id = ?"
+	exists     = "SELECT EXISTS(SELECT 1 FROM blocks WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?))"
+	getBlocked = "SELECT a.id, a.user_name, a.f_name, a.l_name FROM blocks b JOIN accounts a ON a.id = b.blocked_id " +
+		"WHERE b.blocker_id = ? ORDER BY b.created_at DESC"
+	getRelated = "SELECT blocked_id FROM blocks WHERE blocker_id = ? UNION SELECT blocker_id FROM blocks WHERE blocked_id = ?"
+)
+
+// Create makes an account block another. Blocking an account twice is the same as blocking it once.
+func (b block) Create(ctx *app.Context, blockerID, blockedID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, insert, blockerID, blockedID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Delete makes an account stop blocking another.
+func (b block) Delete(ctx *app.Context, blockerID, blockedID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, remove, blockerID, blockedID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Exists tells if either of two accounts blocks the other.
+func (b block) Exists(ctx *app.Context, id, otherID int64) (bool, error) {
+	var found bool
+
+	if err := ctx.SQL.QueryRowContext(ctx, exists, id, otherID, otherID, id).Scan(&found); err != nil {
+		return false, errors.DBError{Err: err}
+	}
+
+	return found, nil
+}
+
+// GetBlocked retrieves the accounts an account blocks, the latest blocked first.
+func (b block) GetBlocked(ctx *app.Context, blockerID int64) ([]*models.User, error) {
+	rows, err := ctx.SQL.QueryContext(ctx, getBlocked, blockerID)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+
+	for rows.Next() {
+		var user models.User
+
+		if err := rows.Scan(&user.ID, &user.UserName, &user.FName, &user.LName); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return users, nil
+}
+
+// GetRelated retrieves the ids of the accounts an account blocks, or that block it.
+func (b block) GetRelated(ctx *app.Context, id int64) ([]int64, error) {
+	rows, err := ctx.SQL.QueryContext(ctx, getRelated, id, id)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer rows.Close()
+
+	related := make([]int64, 0)
+
+	for rows.Next() {
+		var id int64
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		related = append(related, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return related, nil
+}
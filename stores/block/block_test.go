@@ -0,0 +1,61 @@
+package block
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+// initializeTest creates the accounts aakanksha_jais (1), mainak_pandit (2) and divij_gupta (3), without blocks.
+func initializeTest() (*app.Context, stores.Block) {
+	test.InitializeTestAccountsTable(a.SQL.DB, a.Logger, "../../db")
+	return &app.Context{Context: context.TODO(), App: a}, New()
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlock(t *testing.T) {
+	ctx, block := initializeTest()
+
+	exists := func(id, otherID int64) func() (interface{}, error) {
+		return func() (interface{}, error) { return block.Exists(ctx, id, otherID) }
+	}
+
+	tests := []struct {
+		description string
+		run         func() (interface{}, error)
+		output      interface{}
+	}{
+		{description: "no block", run: exists(1, 2), output: false},
+		{description: "block an account", run: func() (interface{}, error) { return nil, block.Create(ctx, 1, 2) }},
+		{description: "block an account twice", run: func() (interface{}, error) { return nil, block.Create(ctx, 1, 2) }},
+		{description: "block of the blocker", run: exists(1, 2), output: true},
+		{description: "block of the blocked account, which cannot see the blocker either", run: exists(2, 1), output: true},
+		{description: "accounts not involved", run: exists(1, 3), output: false},
+		{description: "blocked by another account", run: func() (interface{}, error) { return nil, block.Create(ctx, 3, 1) }},
+		{description: "accounts blocked", run: func() (interface{}, error) { return block.GetBlocked(ctx, 1) }, output: []*models.User{{ID: 2, UserName: "mainak_pandit", FName: "Mainak", LName: "Pandit"}}},
+		{description: "accounts blocked either way", run: func() (interface{}, error) {
+			related, err := block.GetRelated(ctx, 1)
+			sort.Slice(related, func(i, j int) bool { return related[i] < related[j] })
+
+			return related, err
+		}, output: []int64{2, 3}},
+		{description: "unblock an account", run: func() (interface{}, error) { return nil, block.Delete(ctx, 1, 2) }},
+		{description: "unblocked account", run: exists(2, 1), output: false},
+		{description: "no accounts blocked", run: func() (interface{}, error) { return block.GetBlocked(ctx, 1) }, output: []*models.User{}},
+	}
+
+	for i, tc := range tests {
+		output, err := tc.run()
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -1,11 +1,15 @@
 package blog
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,24 +18,90 @@ import (
 )
 
 type blog struct {
+	indexes *datastore.MongoIndexes
 }
 
+// New returns a blog store. Listings are served by indexes on (created_on, _id), with and without
+// the account_id or tags prefix.
+// The publisher looks up scheduled blogs by (status, publish_at), and the renderer stale blogs by render_version.
+// Slugs are unique per account, and old slugs are looked up by (account_id, old_slugs).
 func New() stores.Blog {
-	return blog{}
+	return blog{indexes: datastore.NewMongoIndexes("blogs",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "created_on", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("blog_created_on"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "account_id", Value: 1}, {Key: "created_on", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("blog_account_created_on"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "created_on", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("blog_tags_created_on"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}},
+			Options: options.Index().SetName("blog_status_publish_at"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "render_version", Value: 1}},
+			Options: options.Index().SetName("blog_render_version"),
+		},
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "slug", Value: 1}},
+			Options: options.Index().SetName("blog_account_slug").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "account_id", Value: 1}, {Key: "old_slugs", Value: 1}},
+			Options: options.Index().SetName("blog_account_old_slugs"),
+		},
+	)}
+}
+
+// GetAll is used to retrieve all blogs that match the filter, in the sort order of the filter.
+// BLogs can be filtered by account_id, blog_id, title, creation time, tags and images.
+func (b blog) GetAll(ctx *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error) {
+	return b.find(ctx, filter.GetFilter(), filter.Sort, page)
 }
 
-// GetAll is used to retrieve all blogs that match the filter.
-// BLogs can be filtered by account_id, blog_id and title.
-func (b blog) GetAll(ctx *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error) {
+// GetByIDs retrieves all blogs whose IDs have been provided as parameter.
+func (b blog) GetByIDs(ctx *app.Context, idList []string, page *models.Page) ([]*models.Blog, error) {
+	return b.find(ctx, bson.D{{Key: "_id", Value: bson.M{"$in": idList}}}, models.SortNewest, page)
+}
+
+// find retrieves the blogs that match the filter in the given sort order.
+// Chronological pages that start at a cursor are fetched with a range query on (created_on, _id), so that
+// blogs inserted while scrolling do not shift the pages. Other pages fall back to skipping.
+func (b blog) find(ctx *app.Context, filter bson.D, sort string, page *models.Page) ([]*models.Blog, error) {
+	if err := b.indexes.Ensure(ctx, ctx.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
 	collection := ctx.Mongo.Collection("blogs")
 
-	opts := options.Find().SetSort(bson.D{{Key: "created_on", Value: -1}}) // retrieve the blogs in reverse chronological order
+	opts := options.Find().SetSort(models.BlogFilter{Sort: sort}.GetSort())
 
 	if page != nil {
-		opts = opts.SetSkip((page.PageNo - 1) * page.Limit).SetLimit(page.Limit)
+		if page.IsKeyset() {
+			operator := "$lt"
+			if sort == models.SortOldest {
+				operator = "$gt"
+			}
+
+			// the filter can have an $or of its own, so both are combined by an $and
+			filter = bson.D{{Key: "$and", Value: bson.A{filter, bson.M{"$or": bson.A{
+				bson.M{"created_on": bson.M{operator: page.Cursor.CreatedOn}},
+				bson.M{"created_on": page.Cursor.CreatedOn, "_id": bson.M{operator: page.Cursor.ID}},
+			}}}}}
+		} else {
+			opts = opts.SetSkip(page.Skip())
+		}
+
+		opts = opts.SetLimit(page.Limit)
 	}
 
-	cursor, err := collection.Find(ctx, filter.GetFilter(), opts)
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, errors.DBError{Err: err}
 	}
@@ -57,40 +127,215 @@ func (b blog) GetAll(ctx *app.Context, filter *models.Blog, page *models.Page) (
 	return blogs, nil
 }
 
-// GetByIDs retrieves all blogs whose IDs have been provided as parameter.
-func (b blog) GetByIDs(ctx *app.Context, idList []string, page *models.Page) ([]*models.Blog, error) {
+// GetScheduled retrieves the scheduled blogs that are due to be published before the given time.
+func (b blog) GetScheduled(ctx *app.Context, before time.Time, limit int64) ([]*models.Blog, error) {
+	filter := bson.D{
+		{Key: "status", Value: models.StatusScheduled},
+		{Key: "publish_at", Value: bson.M{"$lte": before}},
+	}
+
+	return b.find(ctx, filter, models.SortOldest, &models.Page{Limit: limit})
+}
+
+// GetStale retrieves the blogs whose content is rendered by an older version than the given one, or not rendered at all.
+func (b blog) GetStale(ctx *app.Context, version int, limit int64) ([]*models.Blog, error) {
+	filter := bson.D{{Key: "render_version", Value: bson.M{"$not": bson.M{"$gte": version}}}}
+
+	return b.find(ctx, filter, models.SortOldest, &models.Page{Limit: limit})
+}
+
+// SetRendered stores the content of a blog rendered by the given version, unless the content has changed since it was read,
+// or it is rendered by a later version already. A blog that is not updated is not found.
+func (b blog) SetRendered(ctx *app.Context, model *models.Blog) error {
+	collection := ctx.Mongo.Collection("blogs")
+
+	filter := bson.D{
+		{Key: "_id", Value: model.BlogID},
+		{Key: "content", Value: model.Content},
+		{Key: "render_version", Value: bson.M{"$not": bson.M{"$gte": model.RenderVersion}}},
+	}
+	update := bson.M{"$set": bson.M{"content_html": model.ContentHTML, "render_version": model.RenderVersion}}
+
+	res, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.EntityNotFound{Entity: "stale blog", ID: model.BlogID}
+	}
+
+	return nil
+}
+
+// GetBySlug retrieves a blog of an account by its slug, or by one of its old slugs if no blog has the slug.
+func (b blog) GetBySlug(ctx *app.Context, accountID int64, slug string) (*models.Blog, error) {
+	if err := b.indexes.Ensure(ctx, ctx.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
 	collection := ctx.Mongo.Collection("blogs")
 
-	opts := options.Find().SetSort(bson.D{{Key: "created_on", Value: -1}})
+	for _, key := range []string{"slug", "old_slugs"} {
+		var blog models.Blog
 
-	if page != nil {
-		opts = opts.SetSkip((page.PageNo - 1) * page.Limit).SetLimit(page.Limit)
+		err := collection.FindOne(ctx, bson.D{{Key: "account_id", Value: accountID}, {Key: key, Value: slug}}).Decode(&blog)
+
+		switch err {
+		case nil:
+			return &blog, nil
+		case mongo.ErrNoDocuments:
+		default:
+			return nil, errors.DBError{Err: err}
+		}
 	}
 
-	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": idList}}, opts)
+	return nil, errors.EntityNotFound{Entity: "blog", ID: slug}
+}
+
+// GetSlugs retrieves the slugs, current and old, of the blogs of an account that are the base slug or the base slug
+// with a numeric suffix, mapped to the IDs of the blogs that hold them. A current slug is held over an old one.
+// The slugs are found by a single query, however many suffixes are taken.
+func (b blog) GetSlugs(ctx *app.Context, accountID int64, base string) (map[string]string, error) {
+	if err := b.indexes.Ensure(ctx, ctx.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	collection := ctx.Mongo.Collection("blogs")
+
+	matcher := regexp.MustCompile("^" + regexp.QuoteMeta(base) + "(-[0-9]+)?$")
+	pattern := bson.M{"$regex": matcher.String()}
+	filter := bson.D{
+		{Key: "account_id", Value: accountID},
+		{Key: "$or", Value: bson.A{bson.M{"slug": pattern}, bson.M{"old_slugs": pattern}}},
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"slug": 1, "old_slugs": 1}))
 	if err != nil {
 		return nil, errors.DBError{Err: err}
 	}
 
-	blogs := make([]*models.Blog, 0)
+	defer cursor.Close(ctx)
+
+	current, old := make(map[string]string), make(map[string]string)
 
 	for cursor.Next(ctx) {
 		var blog models.Blog
 
-		err = cursor.Decode(&blog)
-		if err != nil {
+		if err = cursor.Decode(&blog); err != nil {
 			return nil, errors.DBError{Err: err}
 		}
 
-		blogs = append(blogs, &blog)
+		// a blog matches by one of its slugs, and the others are left out
+		if matcher.MatchString(blog.Slug) {
+			current[blog.Slug] = blog.BlogID
+		}
+
+		for _, slug := range blog.OldSlugs {
+			if matcher.MatchString(slug) {
+				old[slug] = blog.BlogID
+			}
+		}
 	}
 
-	err = cursor.Close(ctx)
+	if err = cursor.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	for slug, blogID := range old {
+		if _, ok := current[slug]; !ok {
+			current[slug] = blogID
+		}
+	}
+
+	return current, nil
+}
+
+// EachImage calls fn with the images of every blog, whatever its status, as they are read.
+// The blogs are not held in memory, however many there are.
+func (b blog) EachImage(ctx *app.Context, fn func(image models.Image)) error {
+	collection := ctx.Mongo.Collection("blogs")
+
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetProjection(bson.M{"images": 1}))
 	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var blog models.Blog
+
+		if err = cursor.Decode(&blog); err != nil {
+			return errors.DBError{Err: err}
+		}
+
+		for _, image := range blog.Images {
+			fn(image)
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Publish publishes a scheduled blog, its publication becomes its creation time.
+// A blog that is no longer scheduled is not found, so that concurrent publishers publish a blog only once.
+func (b blog) Publish(ctx *app.Context, blogID string, publishedOn time.Time) (*models.Blog, error) {
+	collection := ctx.Mongo.Collection("blogs")
+
+	filter := bson.D{{Key: "_id", Value: blogID}, {Key: "status", Value: models.StatusScheduled}}
+	update := bson.M{"$set": bson.M{"status": models.StatusPublished, "created_on": publishedOn}, "$unset": bson.M{"publish_at": ""}}
+
+	res := collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "scheduled blog", ID: blogID}
+	default:
 		return nil, errors.DBError{Err: err}
 	}
 
-	return blogs, nil
+	var blog models.Blog
+
+	if err := res.Decode(&blog); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &blog, nil
+}
+
+// SetHidden hides a blog from everyone but its author, or lists it again, and returns it.
+func (b blog) SetHidden(ctx *app.Context, blogID string, hidden bool) (*models.Blog, error) {
+	collection := ctx.Mongo.Collection("blogs")
+
+	update := bson.M{"$unset": bson.M{"hidden": ""}}
+	if hidden {
+		update = bson.M{"$set": bson.M{"hidden": true}}
+	}
+
+	res := collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: blogID}}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "blog", ID: blogID}
+	default:
+		return nil, errors.DBError{Err: err}
+	}
+
+	var blog models.Blog
+
+	if err := res.Decode(&blog); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &blog, nil
 }
 
 // Get is used to retrieve a SINGLE blog that matches the filter.
@@ -122,17 +367,21 @@ func (b blog) Get(ctx *app.Context, filter *models.Blog) (*models.Blog, error) {
 	}
 }
 
-// Create is used to create a new blog.
+// Create is used to create a new blog. A slug the account has taken already fails with EntityAlreadyExists.
 func (b blog) Create(ctx *app.Context, model *models.Blog) (*models.Blog, error) {
 	if model == nil {
 		return nil, nil
 	}
 
+	if err := b.indexes.Ensure(ctx, ctx.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
 	collection := ctx.Mongo.Collection("blogs")
 
 	res, err := collection.InsertOne(ctx, model) // nil is returned if InsertOne operation is successful
 	if err != nil {
-		return nil, errors.DBError{Err: err}
+		return nil, writeError(err, model)
 	}
 
 	id := res.InsertedID
@@ -140,7 +389,9 @@ func (b blog) Create(ctx *app.Context, model *models.Blog) (*models.Blog, error)
 	return b.Get(ctx, &models.Blog{BlogID: id.(string)})
 }
 
-// Update updates the blog by its ID.
+// Update updates the blog by its ID and the account of its author, in a single write.
+// Tags are overwritten, and images are replaced by the images of the model unless they are nil.
+// A blog of another account is not found, and a slug the account has taken already fails with EntityAlreadyExists.
 func (b blog) Update(ctx *app.Context, model *models.Blog) (*models.Blog, error) {
 	if model == nil {
 		return nil, nil
@@ -148,40 +399,111 @@ func (b blog) Update(ctx *app.Context, model *models.Blog) (*models.Blog, error)
 
 	collection := ctx.Mongo.Collection("blogs")
 
-	res := collection.FindOneAndUpdate(ctx, bson.M{"_id": model.BlogID}, generateFilter(model))
+	res := collection.FindOneAndUpdate(ctx, bson.M{"_id": model.BlogID, "account_id": model.AccountID}, generateFilter(model))
 
 	if err := res.Err(); err != nil {
-		return nil, errors.DBError{Err: err}
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.EntityNotFound{Entity: "blog", ID: model.BlogID}
+		}
+
+		return nil, writeError(err, model)
 	}
 
-	// if new images are added
-	if len(model.Images) != 0 {
-		// push them to the array
-		r := collection.FindOneAndUpdate(ctx, bson.M{"_id": model.BlogID}, bson.M{"$push": bson.M{"images": bson.M{"$each": model.Images}}})
-		if err := r.Err(); err != nil {
-			return nil, errors.DBError{Err: err}
-		}
+	return b.Get(ctx, &models.Blog{BlogID: model.BlogID})
+}
+
+// Revert writes back a version of a blog over an update, by its ID and the account of its author.
+// Unlike Update, which leaves out the fields that are empty, every field an update writes is set as it is
+// in the model, and those that are empty are unset, so that the blog is as it was before the update.
+func (b blog) Revert(ctx *app.Context, model *models.Blog) error {
+	collection := ctx.Mongo.Collection("blogs")
+
+	res, err := collection.UpdateOne(ctx, bson.M{"_id": model.BlogID, "account_id": model.AccountID}, revertFilter(model))
+	if err != nil {
+		return writeError(err, model)
 	}
 
-	// remove all existing tags
-	r := collection.FindOneAndUpdate(ctx, bson.M{"_id": model.BlogID}, bson.M{"$unset": bson.M{"tags": ""}})
-	if err := r.Err(); err != nil {
-		return nil, errors.DBError{Err: err}
+	if res.MatchedCount == 0 {
+		return errors.EntityNotFound{Entity: "blog", ID: model.BlogID}
 	}
 
-	// add new tags
-	if len(model.Tags) != 0 {
-		r = collection.FindOneAndUpdate(ctx, bson.M{"_id": model.BlogID}, bson.M{"$push": bson.M{"tags": bson.M{"$each": model.Tags}}})
-		if err := r.Err(); err != nil {
-			return nil, errors.DBError{Err: err}
+	return nil
+}
+
+// revertFilter sets the fields an update writes as they are in the model, and unsets those that are empty.
+func revertFilter(model *models.Blog) bson.M {
+	update := bson.M{
+		"title":      model.Title,
+		"summary":    model.Summary,
+		"content":    model.Content,
+		"created_on": model.CreatedOn,
+	}
+	unset := bson.M{}
+
+	// images are never nil, so that a blog without images is read back as one
+	if model.Images != nil {
+		update["images"] = model.Images
+	} else {
+		update["images"] = []models.Image{}
+	}
+
+	optional := []struct {
+		key   string
+		value interface{}
+		empty bool
+	}{
+		{"tags", model.Tags, len(model.Tags) == 0},
+		{"content_format", model.ContentFormat, model.ContentFormat == ""},
+		{"content_html", model.ContentHTML, model.ContentHTML == ""},
+		{"render_version", model.RenderVersion, model.RenderVersion == 0},
+		{"status", model.Status, model.Status == ""},
+		{"visibility", model.Visibility, model.Visibility == ""},
+		{"slug", model.Slug, model.Slug == ""},
+		{"old_slugs", model.OldSlugs, len(model.OldSlugs) == 0},
+		{"publish_at", model.PublishAt, model.PublishAt == nil},
+	}
+
+	for _, field := range optional {
+		if field.empty {
+			unset[field.key] = ""
+		} else {
+			update[field.key] = field.value
 		}
 	}
 
-	return b.Get(ctx, &models.Blog{BlogID: model.BlogID})
+	filter := bson.M{"$set": update}
+
+	if len(unset) != 0 {
+		filter["$unset"] = unset
+	}
+
+	return filter
+}
+
+// writeError tells a slug that is taken from other failures of a write.
+func writeError(err error, model *models.Blog) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.EntityAlreadyExists{Entity: "blog", ValueType: "slug", Value: model.Slug}
+	}
+
+	return errors.DBError{Err: err}
 }
 
 func generateFilter(model *models.Blog) bson.M {
+	filter := bson.M{}
 	update := bson.M{}
+	unset := bson.M{}
+
+	if model.Images != nil {
+		update["images"] = model.Images
+	}
+
+	if len(model.Tags) != 0 {
+		update["tags"] = model.Tags
+	} else {
+		unset["tags"] = ""
+	}
+
 	if model.Title != "" {
 		update["title"] = model.Title
 	}
@@ -190,11 +512,49 @@ func generateFilter(model *models.Blog) bson.M {
 		update["summary"] = model.Summary
 	}
 
+	// the rendering is written with the content it is rendered from, even if empty, so that it never goes stale unnoticed
 	if model.Content != "" {
 		update["content"] = model.Content
+		update["content_format"] = model.ContentFormat
+		update["content_html"] = model.ContentHTML
+		update["render_version"] = model.RenderVersion
+	}
+
+	if model.Status != "" {
+		update["status"] = model.Status
+	}
+
+	if model.Visibility != "" {
+		update["visibility"] = model.Visibility
+	}
+
+	// old slugs are written with the slug, as the slugs the blog had before it
+	if model.Slug != "" {
+		update["slug"] = model.Slug
+		update["old_slugs"] = model.OldSlugs
+	}
+
+	// a blog that is no longer scheduled has no publishing time
+	if model.PublishAt != nil {
+		update["publish_at"] = model.PublishAt
+	} else {
+		unset["publish_at"] = ""
+	}
+
+	// set when a blog is published
+	if !model.CreatedOn.IsZero() {
+		update["created_on"] = model.CreatedOn
+	}
+
+	if len(update) != 0 {
+		filter["$set"] = update
+	}
+
+	if len(unset) != 0 {
+		filter["$unset"] = unset
 	}
 
-	return bson.M{"$set": update}
+	return filter
 }
 
 // Delete deletes a blog by its ID.
@@ -8,6 +8,7 @@ import (
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
@@ -21,6 +22,17 @@ func initializeTest() (*app.Context, stores.Blog) {
 	return &app.Context{Context: context.TODO(), App: a}, New()
 }
 
+// legacyImages describes images stored as bare URLs, before renditions were generated.
+func legacyImages(urls ...string) []models.Image {
+	images := make([]models.Image, 0, len(urls))
+
+	for _, url := range urls {
+		images = append(images, models.ImageFromURL(url))
+	}
+
+	return images
+}
+
 func getTime(date string) time.Time {
 	t, _ := time.Parse("2006-01-02T15:04:05Z07:00", date)
 	return t
@@ -32,54 +44,61 @@ func TestBlog_GetAll(t *testing.T) {
 
 	tests := []struct {
 		description string
-		input       *models.Blog
+		input       *models.BlogFilter
 		page        *models.Page
 		output      []*models.Blog
 		err         error
 	}{
 		{
 			description: "get all with empty filter and page = nil",
-			input:       &models.Blog{},
+			input:       &models.BlogFilter{},
 			output:      getAllOutput(),
 			err:         nil,
 		},
 		{
 			description: "get all with empty filter and page limit = 2",
-			input:       &models.Blog{},
+			input:       &models.BlogFilter{},
 			output: []*models.Blog{
-				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"}},
-				{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#love", "#nature", "#life"}, CreatedOn: getTime("2021-04-16T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg"}},
+				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")},
+				{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#love", "#nature", "#life"}, CreatedOn: getTime("2021-04-16T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg")},
 			},
 			page: &models.Page{Limit: 2, PageNo: 1},
 			err:  nil,
 		},
 		{
 			description: "get all (page number = 1, limit = 3)",
-			input:       &models.Blog{},
+			input:       &models.BlogFilter{},
 			output:      getAllOutput()[:3],
 			page:        &models.Page{Limit: 3, PageNo: 1},
 			err:         nil,
 		},
-		{description: "get all with empty filter and invalid page offset", input: &models.Blog{}, output: []*models.Blog(nil), page: &models.Page{Limit: 2, PageNo: 100}, err: nil},
-		{description: "get all with empty filter and zero page limit", input: &models.Blog{}, output: getAllOutput(), page: &models.Page{Limit: 0, PageNo: 1}, err: nil},
-		{description: "get all with empty filter and empty page", input: &models.Blog{}, output: getAllOutput(), page: &models.Page{}, err: nil},
+		{description: "get all with empty filter and invalid page offset", input: &models.BlogFilter{}, output: []*models.Blog(nil), page: &models.Page{Limit: 2, PageNo: 100}, err: nil},
+		{description: "get all after a cursor", input: &models.BlogFilter{}, output: getAllOutput()[2:4], page: &models.Page{Limit: 2, Cursor: &models.Cursor{CreatedOn: getTime("2021-04-16T15:04:05Z"), ID: "9SNVSH8K2M"}}, err: nil},
+		{description: "get all after an offset cursor", input: &models.BlogFilter{}, output: getAllOutput()[3:6], page: &models.Page{Limit: 3, Cursor: &models.Cursor{Offset: 3}}, err: nil},
+		{description: "get all with any of the tags", input: &models.BlogFilter{Tags: []string{"#love", "#life"}}, output: []*models.Blog{getAllOutput()[1], getAllOutput()[3], getAllOutput()[5], getAllOutput()[7], getAllOutput()[9]}, err: nil},
+		{description: "get all with all of the tags", input: &models.BlogFilter{Tags: []string{"#love", "#life"}, AllTags: true}, output: getAllOutput()[1:2], err: nil},
+		{description: "get all within a date range", input: &models.BlogFilter{CreatedAfter: getTime("2021-03-01T00:00:00Z"), CreatedBefore: getTime("2021-04-09T00:00:00Z")}, output: getAllOutput()[3:6], err: nil},
+		{description: "get all oldest first", input: &models.BlogFilter{Sort: models.SortOldest}, output: []*models.Blog{getAllOutput()[9], getAllOutput()[8]}, page: &models.Page{Limit: 2, PageNo: 1}, err: nil},
+		{description: "get all oldest first after a cursor", input: &models.BlogFilter{Sort: models.SortOldest}, output: []*models.Blog{getAllOutput()[7], getAllOutput()[6]}, page: &models.Page{Limit: 2, Cursor: &models.Cursor{CreatedOn: getTime("2020-12-15T15:04:05Z"), ID: "MSI8NS2909"}}, err: nil},
+		{description: "get all with empty filter and zero page limit", input: &models.BlogFilter{}, output: getAllOutput(), page: &models.Page{Limit: 0, PageNo: 1}, err: nil},
+		{description: "get all with empty filter and empty page", input: &models.BlogFilter{}, output: getAllOutput(), page: &models.Page{}, err: nil},
 		{
 			description: "get all with account id = 2",
-			input:       &models.Blog{AccountID: 2},
+			input:       &models.BlogFilter{Blog: models.Blog{AccountID: 2}},
 			output: []*models.Blog{
-				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"}},
-				{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate!", Tags: []string{"#cocoa", "#sweet", "#chocolate", "#trending"}, CreatedOn: getTime("2021-04-10T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg"}},
-				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
-				{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg"}},
+				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")},
+				{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate!", Tags: []string{"#cocoa", "#sweet", "#chocolate", "#trending"}, CreatedOn: getTime("2021-04-10T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg")},
+				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+				{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg")},
 			},
 			err: nil,
 		},
 		{
 			description: "get all with account id = 2",
-			input:       &models.Blog{AccountID: 2},
+			input:       &models.BlogFilter{Blog: models.Blog{AccountID: 2}},
 			output: []*models.Blog{
-				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
-				{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg"}},
+				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+				{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg")},
 			},
 			page: &models.Page{PageNo: 2, Limit: 2},
 			err:  nil,
@@ -113,11 +132,11 @@ func TestBlog_GetAll_Error(t *testing.T) {
 
 	tc := struct {
 		description string
-		input       *models.Blog
+		input       *models.BlogFilter
 		page        *models.Page
 		output      []*models.Blog
 		err         error
-	}{description: "get blog with blog id = TEST_ID", input: &models.Blog{BlogID: "TEST_ID"}, page: &models.Page{Limit: 2, PageNo: 1}, err: errors.DBError{}}
+	}{description: "get blog with blog id = TEST_ID", input: &models.BlogFilter{Blog: models.Blog{BlogID: "TEST_ID"}}, page: &models.Page{Limit: 2, PageNo: 1}, err: errors.DBError{}}
 
 	output, err := blog.GetAll(ctx, tc.input, tc.page)
 
@@ -141,9 +160,9 @@ func TestBlog_GetByIDs(t *testing.T) {
 			description: "get blogs with id = MSI8WKNSH9, 9SH7SH2V37, MSO8WB2J7X",
 			input:       []string{"MSI8WKNSH9", "9SH7SH2V37", "MSO8WB2J7X"},
 			output: []*models.Blog{
-				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"}},
-				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
-				{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg"}},
+				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")},
+				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+				{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg")},
 			},
 			page: &models.Page{Limit: 3, PageNo: 1},
 			err:  nil,
@@ -152,9 +171,9 @@ func TestBlog_GetByIDs(t *testing.T) {
 			description: "get blogs (in reverse chronological order always)",
 			input:       []string{"POQA7B2J7X", "MSI8WKNSH9", "9SH7SH2V37", "MSO8WB2J7X"},
 			output: []*models.Blog{
-				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"}},
-				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
-				{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg"}},
+				{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")},
+				{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+				{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg")},
 			},
 			page: &models.Page{Limit: 3, PageNo: 1},
 			err:  nil,
@@ -213,7 +232,7 @@ func TestBlog_Get(t *testing.T) {
 		{
 			description: "get blog with blog id = MSO8WB2J7X",
 			input:       &models.Blog{BlogID: "MSO8WB2J7X"},
-			output:      &models.Blog{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg"}},
+			output:      &models.Blog{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg")},
 		},
 		{description: "get blog with non-existing blog id", input: &models.Blog{BlogID: "DUMMY_ID_123"}, err: errors.DBError{Err: mongo.ErrNoDocuments}},
 	}
@@ -264,7 +283,7 @@ func TestBlog_Create(t *testing.T) {
 		Content:   "content",
 		Tags:      []string{"tag"},
 		CreatedOn: getTime("2020-09-21T15:04:05Z"),
-		Images:    []string{"url"},
+		Images:    legacyImages("url"),
 	}
 
 	tests := []struct {
@@ -296,18 +315,28 @@ func TestBlog_Update(t *testing.T) {
 	}{
 		{
 			description: "valid update on title, tags and images.",
-			input:       &models.Blog{BlogID: "MSO8WB2J7X", Title: "new_title", Tags: []string{"tag1", "tag3"}, Images: []string{"url8"}},
-			output:      &models.Blog{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "new_title", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"tag1", "tag3"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg", "url8"}},
+			input:       &models.Blog{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "new_title", Tags: []string{"tag1", "tag3"}, Images: legacyImages("url8")},
+			output:      &models.Blog{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "new_title", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"tag1", "tag3"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: legacyImages("url8")},
+		},
+		{
+			description: "valid update on the order of images.",
+			input:       &models.Blog{BlogID: "ABK7SH2V37", AccountID: 2, Tags: []string{"#cocoa"}, Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg")},
+			output:      &models.Blog{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate!", Tags: []string{"#cocoa"}, CreatedOn: getTime("2021-04-10T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg")},
 		},
 		{
 			description: "valid update on content and tags.",
-			input:       &models.Blog{BlogID: "POQA7B2J7X", Content: "new_content", Tags: []string{"#love", "life"}},
-			output:      &models.Blog{BlogID: "POQA7B2J7X", AccountID: 1, Title: "love", Summary: "a blog on love", Content: "new_content", Tags: []string{"#love", "life"}, CreatedOn: getTime("2019-03-16T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/3b6c399e8f5d9d4f54f4d91c6db7cfde.jpg"}},
+			input:       &models.Blog{BlogID: "POQA7B2J7X", AccountID: 1, Content: "new_content", Tags: []string{"#love", "life"}},
+			output:      &models.Blog{BlogID: "POQA7B2J7X", AccountID: 1, Title: "love", Summary: "a blog on love", Content: "new_content", Tags: []string{"#love", "life"}, CreatedOn: getTime("2019-03-16T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/3b6c399e8f5d9d4f54f4d91c6db7cfde.jpg")},
 		},
 		{
 			description: "valid update on summary.",
-			input:       &models.Blog{BlogID: "9SH7SH2V37", Summary: "new_summary", Tags: []string{"#memories", "#life"}},
-			output:      &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "new_summary", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
+			input:       &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Summary: "new_summary", Tags: []string{"#memories", "#life"}},
+			output:      &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "new_summary", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+		},
+		{
+			description: "update of a blog of another account.",
+			input:       &models.Blog{BlogID: "9SH7SH2V37", AccountID: 3, Title: "stolen"},
+			err:         errors.EntityNotFound{Entity: "blog", ID: "9SH7SH2V37"},
 		},
 	}
 
@@ -320,6 +349,328 @@ func TestBlog_Update(t *testing.T) {
 	}
 }
 
+//nolint:lll // test cases need to be readable
+func TestBlog_Publish(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	publishAt := getTime("2021-06-01T15:04:05Z")
+	publishedOn := getTime("2021-06-01T15:05:00Z")
+
+	scheduled := &models.Blog{BlogID: "SCHEDULED", AccountID: 2, Title: "title", Summary: "summary", Content: "content", Tags: []string{"#tag"}, CreatedOn: getTime("2021-05-30T15:04:05Z"), Images: []models.Image{}, Status: models.StatusScheduled, PublishAt: &publishAt}
+
+	_, _ = blog.Create(ctx, scheduled)
+
+	due, err := blog.GetScheduled(ctx, getTime("2021-05-31T15:04:05Z"), 10)
+	assert.Equal(t, []*models.Blog{}, due, "TEST, failed.\nno blog is due")
+	assert.Equal(t, nil, err, "TEST, failed.\nno blog is due")
+
+	due, err = blog.GetScheduled(ctx, publishedOn, 10)
+	assert.Equal(t, []*models.Blog{scheduled}, due, "TEST, failed.\nscheduled blog is due")
+	assert.Equal(t, nil, err, "TEST, failed.\nscheduled blog is due")
+
+	published := *scheduled
+	published.Status = models.StatusPublished
+	published.CreatedOn = publishedOn
+	published.PublishAt = nil
+
+	tests := []struct {
+		description string
+		blogID      string
+		output      *models.Blog
+		err         error
+	}{
+		{description: "publish a scheduled blog", blogID: "SCHEDULED", output: &published},
+		{description: "publish a blog again", blogID: "SCHEDULED", err: errors.EntityNotFound{Entity: "scheduled blog", ID: "SCHEDULED"}},
+		{description: "publish a blog that was never scheduled", blogID: "MSI8WKNSH9", err: errors.EntityNotFound{Entity: "scheduled blog", ID: "MSI8WKNSH9"}},
+	}
+
+	for i := range tests {
+		output, err := blog.Publish(ctx, tests[i].blogID, publishedOn)
+
+		assert.Equal(t, tests[i].output, output, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+
+		assert.Equal(t, tests[i].err, err, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_UpdateUnscheduled(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	publishAt := getTime("2021-06-01T15:04:05Z")
+
+	_, _ = blog.Create(ctx, &models.Blog{BlogID: "SCHEDULED", AccountID: 2, Title: "title", Summary: "summary", Content: "content", CreatedOn: getTime("2021-05-30T15:04:05Z"), Status: models.StatusScheduled, PublishAt: &publishAt})
+
+	res, err := blog.Update(ctx, &models.Blog{BlogID: "SCHEDULED", AccountID: 2, Status: models.StatusDraft})
+
+	assert.Equal(t, nil, err, "TEST, failed.\nscheduled blog becomes a draft")
+
+	if assert.NotNil(t, res, "TEST, failed.\nscheduled blog becomes a draft") {
+		assert.Equal(t, models.StatusDraft, res.Status, "TEST, failed.\nscheduled blog becomes a draft")
+		assert.Nil(t, res.PublishAt, "TEST, failed.\nscheduled blog becomes a draft")
+	}
+}
+
+func TestBlog_EachImage(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	images := make([]models.Image, 0)
+
+	err := blog.EachImage(ctx, func(image models.Image) {
+		images = append(images, image)
+	})
+
+	assert.Equal(t, nil, err, "TEST, failed.\nimages of every blog")
+	assert.Contains(t, images, legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")[0], "TEST, failed.\nimages of every blog")
+	assert.Contains(t, images, legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/oculus.jpg")[0], "TEST, failed.\nimages of every blog")
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetBySlug(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	_, err := blog.Update(ctx, &models.Blog{BlogID: "MSI8WKNSH9", AccountID: 2, Slug: "music", OldSlugs: []string{"edm"}, Tags: []string{}})
+	assert.Equal(t, nil, err, "TEST, failed.\nslug of a blog")
+
+	tests := []struct {
+		description string
+		accountID   int64
+		slug        string
+		blogID      string
+		err         error
+	}{
+		{description: "blog by its slug", accountID: 2, slug: "music", blogID: "MSI8WKNSH9"},
+		{description: "blog by an old slug", accountID: 2, slug: "edm", blogID: "MSI8WKNSH9"},
+		{description: "slug of another account", accountID: 3, slug: "music", err: errors.EntityNotFound{Entity: "blog", ID: "music"}},
+		{description: "unknown slug", accountID: 2, slug: "house", err: errors.EntityNotFound{Entity: "blog", ID: "house"}},
+	}
+
+	for i, tc := range tests {
+		res, err := blog.GetBySlug(ctx, tc.accountID, tc.slug)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		if tc.err == nil && assert.NotNil(t, res, "TEST [%v], failed.\n%s", i+1, tc.description) {
+			assert.Equal(t, tc.blogID, res.BlogID, "TEST [%v], failed.\n%s", i+1, tc.description)
+			assert.Equal(t, "music", res.Slug, "TEST [%v], failed.\n%s", i+1, tc.description)
+		}
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetSlugs(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	for _, model := range []*models.Blog{
+		{BlogID: "MSI8WKNSH9", AccountID: 2, Slug: "music", OldSlugs: []string{"music-3", "edm"}, Tags: []string{}},
+		{BlogID: "KN78FH8K2M", AccountID: 2, Slug: "music-2", OldSlugs: []string{"music", "musical"}, Tags: []string{}},
+	} {
+		_, err := blog.Update(ctx, model)
+		assert.Equal(t, nil, err, "TEST, failed.\nslug of a blog")
+	}
+
+	tests := []struct {
+		description string
+		accountID   int64
+		base        string
+		output      map[string]string
+	}{
+		{description: "slugs and old slugs with numeric suffixes, current slugs held over old ones", accountID: 2, base: "music", output: map[string]string{"music": "MSI8WKNSH9", "music-2": "KN78FH8K2M", "music-3": "MSI8WKNSH9"}},
+		{description: "slug that only starts with the base", accountID: 2, base: "musical", output: map[string]string{"musical": "KN78FH8K2M"}},
+		{description: "slugs of another account", accountID: 3, base: "music", output: map[string]string{}},
+		{description: "regex characters are matched literally", accountID: 2, base: "m.sic", output: map[string]string{}},
+	}
+
+	for i, tc := range tests {
+		output, err := blog.GetSlugs(ctx, tc.accountID, tc.base)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_GetAllVisibility(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	for _, model := range []*models.Blog{
+		{BlogID: "9SH7SH2V37", AccountID: 2, Visibility: models.VisibilityUnlisted, Tags: []string{"#memories", "#life"}},
+		{BlogID: "KN78FH8K2M", AccountID: 2, Visibility: models.VisibilityFollowers, Tags: []string{"#life"}},
+		{BlogID: "ABK7SH2V37", AccountID: 2, Visibility: models.VisibilityPrivate, Tags: []string{"#cocoa", "#sweet", "#chocolate", "#trending"}},
+	} {
+		_, err := blog.Update(ctx, model)
+		assert.Equal(t, nil, err, "TEST, failed.\nvisibility of a blog")
+	}
+
+	tests := []struct {
+		description string
+		filter      *models.BlogFilter
+		blogIDs     []string
+	}{
+		{description: "logged out viewer", filter: &models.BlogFilter{Blog: models.Blog{AccountID: 2}}, blogIDs: []string{"MSI8WKNSH9"}},
+		{description: "viewer who does not follow the author", filter: &models.BlogFilter{Blog: models.Blog{AccountID: 2}, Viewer: 3}, blogIDs: []string{"MSI8WKNSH9"}},
+		{description: "follower of the author", filter: &models.BlogFilter{Blog: models.Blog{AccountID: 2}, Viewer: 3, Following: []int64{2}}, blogIDs: []string{"MSI8WKNSH9", "KN78FH8K2M"}},
+		{description: "author", filter: &models.BlogFilter{Blog: models.Blog{AccountID: 2}, Viewer: 2}, blogIDs: []string{"MSI8WKNSH9", "ABK7SH2V37", "9SH7SH2V37", "KN78FH8K2M"}},
+		{description: "blogs among ids", filter: &models.BlogFilter{IDs: []string{"9SH7SH2V37", "9SNVSH8K2M"}}, blogIDs: []string{"9SNVSH8K2M"}},
+		{description: "blogs of hidden accounts", filter: &models.BlogFilter{IDs: []string{"MSI8WKNSH9", "9SNVSH8K2M"}, Hidden: []int64{1, 2}}, blogIDs: []string{"9SNVSH8K2M"}},
+	}
+
+	for i, tc := range tests {
+		res, err := blog.GetAll(ctx, tc.filter, nil)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		blogIDs := make([]string, 0, len(res))
+		for _, b := range res {
+			blogIDs = append(blogIDs, b.BlogID)
+		}
+
+		assert.Equal(t, tc.blogIDs, blogIDs, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBlog_GetStale(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	stale, err := blog.GetStale(ctx, 1, 3)
+
+	assert.Equal(t, nil, err, "TEST, failed.\nblogs never rendered")
+
+	if assert.Equal(t, 3, len(stale), "TEST, failed.\nblogs never rendered") {
+		assert.Equal(t, "MSI8NS2909", stale[0].BlogID, "TEST, failed.\noldest blog first")
+	}
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_SetRendered(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		err         error
+	}{
+		{description: "content changed since it was read", model: &models.Blog{BlogID: "MSI8NS2909", Content: "oculus", ContentHTML: "<p>oculus</p>\n", RenderVersion: 1}, err: errors.EntityNotFound{Entity: "stale blog", ID: "MSI8NS2909"}},
+		{description: "stale blog", model: &models.Blog{BlogID: "MSI8NS2909", Content: "oculus is terrific!!!", ContentHTML: "<p>oculus is terrific!!!</p>\n", RenderVersion: 1}},
+		{description: "rendered by the version already", model: &models.Blog{BlogID: "MSI8NS2909", Content: "oculus is terrific!!!", ContentHTML: "<p>oculus is terrific!!!</p>\n", RenderVersion: 1}, err: errors.EntityNotFound{Entity: "stale blog", ID: "MSI8NS2909"}},
+	}
+
+	for i, tc := range tests {
+		err := blog.SetRendered(ctx, tc.model)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+
+	res, err := blog.Get(ctx, &models.Blog{BlogID: "MSI8NS2909"})
+	if assert.Equal(t, nil, err, "TEST, failed.\nrendered blog") {
+		assert.Equal(t, "<p>oculus is terrific!!!</p>\n", res.ContentHTML, "TEST, failed.\nrendered blog")
+		assert.Equal(t, 1, res.RenderVersion, "TEST, failed.\nrendered blog")
+	}
+}
+
+func TestBlog_SetHidden(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	res, err := blog.SetHidden(ctx, "MSO8WB2J7X", true)
+	if assert.Equal(t, nil, err, "TEST, failed.\nhide a blog") {
+		assert.True(t, res.Hidden, "TEST, failed.\nhide a blog")
+	}
+
+	// a hidden blog is listed to its author alone
+	blogs, err := blog.GetAll(ctx, &models.BlogFilter{Blog: models.Blog{AccountID: 3}}, &models.Page{Limit: 10, PageNo: 1})
+	if assert.Equal(t, nil, err, "TEST, failed.\nlisting of another viewer") {
+		for _, b := range blogs {
+			assert.NotEqual(t, "MSO8WB2J7X", b.BlogID, "TEST, failed.\nlisting of another viewer")
+		}
+	}
+
+	blogs, err = blog.GetAll(ctx, &models.BlogFilter{Blog: models.Blog{AccountID: 3}, Viewer: 3}, &models.Page{Limit: 10, PageNo: 1})
+	if assert.Equal(t, nil, err, "TEST, failed.\nlisting of the author") {
+		assert.Contains(t, blogIDs(blogs), "MSO8WB2J7X", "TEST, failed.\nlisting of the author")
+	}
+
+	res, err = blog.SetHidden(ctx, "MSO8WB2J7X", false)
+	if assert.Equal(t, nil, err, "TEST, failed.\nlist a blog again") {
+		assert.False(t, res.Hidden, "TEST, failed.\nlist a blog again")
+	}
+
+	_, err = blog.SetHidden(ctx, "NOTABLOG00", true)
+	assert.Equal(t, errors.EntityNotFound{Entity: "blog", ID: "NOTABLOG00"}, err, "TEST, failed.\nunknown blog")
+}
+
+func blogIDs(blogs []*models.Blog) []string {
+	ids := make([]string, 0, len(blogs))
+
+	for _, b := range blogs {
+		ids = append(ids, b.BlogID)
+	}
+
+	return ids
+}
+
+//nolint:lll // test cases need to be readable
+func TestBlog_Revert(t *testing.T) {
+	ctx, blog := initializeTest()
+
+	previous, err := blog.Get(ctx, &models.Blog{BlogID: "9SH7SH2V37"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	publishAt := getTime("2021-05-01T15:04:05Z")
+
+	_, err = blog.Update(ctx, &models.Blog{BlogID: "9SH7SH2V37", AccountID: 2, Title: "new_title", Slug: "new-title", OldSlugs: []string{"memories"}, Content: "new_content", ContentFormat: models.FormatMarkdown, ContentHTML: "<p>new_content</p>\n", RenderVersion: 1, Status: models.StatusScheduled, PublishAt: &publishAt, Images: []models.Image{}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the fields the blog did not have before the update are unset
+	err = blog.Revert(ctx, previous)
+	assert.NoError(t, err, "TEST [1], failed.\nrevert an update")
+
+	output, err := blog.Get(ctx, &models.Blog{BlogID: "9SH7SH2V37"})
+	assert.NoError(t, err, "TEST [1], failed.\nrevert an update")
+	assert.Equal(t, previous, output, "TEST [1], failed.\nrevert an update")
+
+	err = blog.Revert(ctx, &models.Blog{BlogID: "9SH7SH2V37", AccountID: 3, Title: "stolen"})
+	assert.Equal(t, errors.EntityNotFound{Entity: "blog", ID: "9SH7SH2V37"}, err, "TEST [2], failed.\nrevert a blog of another account")
+}
+
+//nolint:lll // test cases need to be readable
+func TestRevertFilter(t *testing.T) {
+	publishAt := getTime("2021-05-01T15:04:05Z")
+	createdOn := getTime("2021-04-08T15:04:05Z")
+
+	tests := []struct {
+		description string
+		model       *models.Blog
+		output      bson.M
+	}{
+		{
+			description: "empty fields are unset",
+			model:       &models.Blog{BlogID: "9SH7SH2V37", Title: "memories", CreatedOn: createdOn},
+			output: bson.M{
+				"$set":   bson.M{"title": "memories", "summary": "", "content": "", "created_on": createdOn, "images": []models.Image{}},
+				"$unset": bson.M{"tags": "", "content_format": "", "content_html": "", "render_version": "", "status": "", "visibility": "", "slug": "", "old_slugs": "", "publish_at": ""},
+			},
+		},
+		{
+			description: "every field is set",
+			model:       &models.Blog{BlogID: "9SH7SH2V37", Title: "memories", Summary: "a blog", Content: "days", ContentFormat: models.FormatMarkdown, ContentHTML: "<p>days</p>\n", RenderVersion: 1, Tags: []string{"#life"}, Images: legacyImages("kid.jpg"), Status: models.StatusScheduled, Visibility: models.VisibilityPrivate, Slug: "memories", OldSlugs: []string{"days"}, PublishAt: &publishAt, CreatedOn: createdOn},
+			output: bson.M{
+				"$set": bson.M{"title": "memories", "summary": "a blog", "content": "days", "created_on": createdOn, "images": legacyImages("kid.jpg"), "tags": []string{"#life"}, "content_format": models.FormatMarkdown, "content_html": "<p>days</p>\n", "render_version": 1, "status": models.StatusScheduled, "visibility": models.VisibilityPrivate, "slug": "memories", "old_slugs": []string{"days"}, "publish_at": &publishAt},
+			},
+		},
+	}
+
+	for i, tc := range tests {
+		output := revertFilter(tc.model)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
 func TestBlog_DeleteBlog(t *testing.T) {
 	ctx, blog := initializeTest()
 
@@ -342,15 +693,15 @@ func TestBlog_DeleteBlog(t *testing.T) {
 //nolint:lll // hampers readability
 func getAllOutput() []*models.Blog {
 	return []*models.Blog{
-		{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg"}},
-		{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#love", "#nature", "#life"}, CreatedOn: getTime("2021-04-16T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg"}},
-		{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate!", Tags: []string{"#cocoa", "#sweet", "#chocolate", "#trending"}, CreatedOn: getTime("2021-04-10T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg"}},
-		{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg"}},
-		{BlogID: "UMS672XR8J", AccountID: 1, Title: "coffee", Summary: "a blog on coffee", Content: "visit starbucks for best coffee!", Tags: []string{"#caffiene", "#coffee", "#trending"}, CreatedOn: getTime("2021-03-25T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/812231.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/809031.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/761638.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/653598.jpg"}},
-		{BlogID: "U72B72XR8J", AccountID: 1, Title: "songs", Summary: "a blog on songs", Content: "billie eilish is love<3", Tags: []string{"#love", "#eilish"}, CreatedOn: getTime("2021-03-14T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/billieeilish.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/billie.jpg"}},
-		{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg"}},
-		{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg"}},
-		{BlogID: "MSI8NS2909", AccountID: 3, Title: "movies", Summary: "a blog on movies", Content: "oculus is terrific!!!", Tags: []string{"#movie", "#movies", "#trending"}, CreatedOn: getTime("2020-12-15T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/oculus.jpg"}},
-		{BlogID: "POQA7B2J7X", AccountID: 1, Title: "love", Summary: "a blog on love", Content: "<3", Tags: []string{"#love", "life", "#trending"}, CreatedOn: getTime("2019-03-16T15:04:05Z"), Images: []string{"https://picshot-images.s3.ap-south-1.amazonaws.com/3b6c399e8f5d9d4f54f4d91c6db7cfde.jpg"}},
+		{BlogID: "MSI8WKNSH9", AccountID: 2, Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{}, CreatedOn: getTime("2021-05-23T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/avicii.jpg")},
+		{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#love", "#nature", "#life"}, CreatedOn: getTime("2021-04-16T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/orchids.jpg")},
+		{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate!", Tags: []string{"#cocoa", "#sweet", "#chocolate", "#trending"}, CreatedOn: getTime("2021-04-10T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate-gettyimages-473741340.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/chocolate.jpg")},
+		{BlogID: "9SH7SH2V37", AccountID: 2, Title: "memories", Summary: "a blog on memories", Content: "the best of childhood days!", Tags: []string{"#memories", "#life"}, CreatedOn: getTime("2021-04-08T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/girl.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/kid.jpg")},
+		{BlogID: "UMS672XR8J", AccountID: 1, Title: "coffee", Summary: "a blog on coffee", Content: "visit starbucks for best coffee!", Tags: []string{"#caffiene", "#coffee", "#trending"}, CreatedOn: getTime("2021-03-25T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/812231.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/809031.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/761638.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/653598.jpg")},
+		{BlogID: "U72B72XR8J", AccountID: 1, Title: "songs", Summary: "a blog on songs", Content: "billie eilish is love<3", Tags: []string{"#love", "#eilish"}, CreatedOn: getTime("2021-03-14T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/billieeilish.jpeg", "https://picshot-images.s3.ap-south-1.amazonaws.com/billie.jpg")},
+		{BlogID: "MSO8WB2J7X", AccountID: 3, Title: "books", Summary: "a blog on books", Content: "the subtle art of not giving a fuck- an award winner", Tags: []string{"#markmanson"}, CreatedOn: getTime("2021-02-12T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/book.jpg")},
+		{BlogID: "KN78FH8K2M", AccountID: 2, Title: "life", Summary: "a blog on life", Content: "life is a journey..", Tags: []string{"#life"}, CreatedOn: getTime("2021-01-06T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/691169.jpg", "https://picshot-images.s3.ap-south-1.amazonaws.com/411820.jpg")},
+		{BlogID: "MSI8NS2909", AccountID: 3, Title: "movies", Summary: "a blog on movies", Content: "oculus is terrific!!!", Tags: []string{"#movie", "#movies", "#trending"}, CreatedOn: getTime("2020-12-15T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/oculus.jpg")},
+		{BlogID: "POQA7B2J7X", AccountID: 1, Title: "love", Summary: "a blog on love", Content: "<3", Tags: []string{"#love", "life", "#trending"}, CreatedOn: getTime("2019-03-16T15:04:05Z"), Images: legacyImages("https://picshot-images.s3.ap-south-1.amazonaws.com/3b6c399e8f5d9d4f54f4d91c6db7cfde.jpg")},
 	}
 }
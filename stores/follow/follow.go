@@ -0,0 +1,79 @@
+package follow
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+type follow struct{}
+
+// New returns a follow store, on the follows table. A follow is keyed by (follower_id, followee_id),
+// and the followers of an account are looked up by followee_id.
+func New() stores.Follow {
+	return follow{}
+}
+
+const (
+	insert       = "INSERT IGNORE INTO follows (follower_id, followee_id) VALUES (?, ?)"
+	remove       = "DELETE FROM follows WHERE follower_id = ? AND followee_id = ?"
+	exists       = "SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = ? AND followee_id = ?)"
+	getFollowees = "SELECT followee_id FROM follows WHERE follower_id = ?"
+)
+
+// Create makes an account follow another. Following an account twice is the same as following it once.
+func (f follow) Create(ctx *app.Context, followerID, followeeID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, insert, followerID, followeeID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Delete makes an account stop following another.
+func (f follow) Delete(ctx *app.Context, followerID, followeeID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, remove, followerID, followeeID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Exists tells if an account follows another.
+func (f follow) Exists(ctx *app.Context, followerID, followeeID int64) (bool, error) {
+	var found bool
+
+	if err := ctx.SQL.QueryRowContext(ctx, exists, followerID, followeeID).Scan(&found); err != nil {
+		return false, errors.DBError{Err: err}
+	}
+
+	return found, nil
+}
+
+// GetFollowees retrieves the ids of the accounts an account follows.
+func (f follow) GetFollowees(ctx *app.Context, followerID int64) ([]int64, error) {
+	rows, err := ctx.SQL.QueryContext(ctx, getFollowees, followerID)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer rows.Close()
+
+	followees := make([]int64, 0)
+
+	for rows.Next() {
+		var id int64
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		followees = append(followees, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return followees, nil
+}
@@ -0,0 +1,168 @@
+package image
+
+import (
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/configs"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+// DefaultDir is the directory images are stored in by the local store, unless IMAGE_DIR is set.
+const DefaultDir = "./images"
+
+// local stores images in a directory of the local filesystem, which the app serves itself.
+// It is meant for local development and single instance deployments.
+type local struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocal returns an Image store over the IMAGE_DIR directory.
+// The images are served by the app at constants.ImagesPath, unless IMAGE_PUBLIC_URL says otherwise.
+func NewLocal(config configs.Config) stores.Image {
+	baseURL := config.GetOrDefault("IMAGE_PUBLIC_URL", "http://localhost:"+config.Get("HTTP_PORT")+constants.ImagesPath)
+
+	return local{dir: config.GetOrDefault("IMAGE_DIR", DefaultDir), baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// URL returns the public URL of a file in the directory.
+func (l local) URL(name string) string {
+	return l.baseURL + "/" + url.PathEscape(name)
+}
+
+// Upload streams an image file to the directory.
+// The file is written under a temporary name and then renamed, so that a partial file is never served.
+func (l local) Upload(_ *app.Context, name, _ string, r io.Reader) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(l.dir, 0o755); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	tmp, err := ioutil.TempFile(l.dir, ".upload-*")
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	// temporary files are created private to the process
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// PresignUpload is not supported, as files reach the directory through the app alone.
+func (l local) PresignUpload(_ *app.Context, _, _ string, _ int64, _ time.Duration) (string, map[string]string, error) {
+	return "", nil, errors.Unsupported{Operation: "presigned upload to the local image storage"}
+}
+
+// Download reads a file from the directory, up to one byte more than the limit.
+func (l local) Download(_ *app.Context, name string, limit int64) ([]byte, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.EntityNotFound{Entity: "file", ID: name}
+		}
+
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return data, nil
+}
+
+// List lists a page of the files of the directory by name. The token of a page is the name of the last file
+// of the page before it. Hidden files, such as those being written, are left out.
+func (l local) List(_ *app.Context, token string, limit int) ([]models.StoredFile, string, error) {
+	entries, err := ioutil.ReadDir(l.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.StoredFile{}, "", nil
+		}
+
+		return nil, "", errors.DBError{Err: err}
+	}
+
+	files := make([]models.StoredFile, 0)
+
+	// the entries are sorted by name
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() <= token {
+			continue
+		}
+
+		// a file past the page tells there is a next page
+		if limit > 0 && len(files) == limit {
+			return files, files[len(files)-1].Name, nil
+		}
+
+		files = append(files, models.StoredFile{Name: entry.Name(), Size: entry.Size(), ModifiedOn: entry.ModTime()})
+	}
+
+	return files, "", nil
+}
+
+// DeleteBulk removes image files from the directory. Files that do not exist are skipped.
+func (l local) DeleteBulk(_ *app.Context, names []string) error {
+	for _, name := range names {
+		path, err := l.path(name)
+		if err != nil {
+			return err
+		}
+
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.DBError{Err: err}
+		}
+	}
+
+	return nil
+}
+
+// path returns the path of a file in the directory, rejecting names that would lead out of it.
+func (l local) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.InvalidParam{Param: "name"}
+	}
+
+	return filepath.Join(l.dir, name), nil
+}
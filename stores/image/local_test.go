@@ -0,0 +1,124 @@
+package image
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+)
+
+func TestLocal_UploadAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocal(testConfig{"IMAGE_DIR": dir, "HTTP_PORT": "8000"})
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+
+	assert.Equal(t, "http://localhost:8000/images/1_a_full.jpg", store.URL("1_a_full.jpg"))
+
+	if assert.NoError(t, store.Upload(ctx, "1_a_full.jpg", "image/jpeg", strings.NewReader("jpeg"))) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "1_a_full.jpg"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("jpeg"), data)
+	}
+
+	data, err := store.Download(ctx, "1_a_full.jpg", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpe"), data, "a file is read up to one byte more than the limit")
+
+	_, err = store.Download(ctx, "1_b_full.jpg", 2)
+	assert.Equal(t, errors.EntityNotFound{Entity: "file", ID: "1_b_full.jpg"}, err)
+
+	_, _, err = store.PresignUpload(ctx, "1_c_full.jpg", "image/jpeg", 4, time.Minute)
+	assert.Equal(t, errors.Unsupported{Operation: "presigned upload to the local image storage"}, err)
+
+	// missing files are skipped
+	assert.NoError(t, store.DeleteBulk(ctx, []string{"1_a_full.jpg", "1_b_full.jpg"}))
+
+	_, err = os.Stat(filepath.Join(dir, "1_a_full.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Empty(t, entries, "temporary files are left behind")
+
+	for _, name := range []string{"../a.jpg", "a/b.jpg", ".hidden", ""} {
+		assert.Equal(t, errors.InvalidParam{Param: "name"}, store.Upload(ctx, name, "image/jpeg", strings.NewReader("")), name)
+	}
+}
+
+func TestLocal_List(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocal(testConfig{"IMAGE_DIR": dir})
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger()})
+	ctx.Context = context.TODO()
+
+	files, next, err := store.List(ctx, "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, files, "a directory that does not exist has no files")
+	assert.Empty(t, next, "a directory that does not exist has no files")
+
+	for _, name := range []string{"1_c_full.jpg", "1_a_full.jpg", "1_b_full.jpg"} {
+		assert.NoError(t, store.Upload(ctx, name, "image/jpeg", strings.NewReader("jpeg")))
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".upload-1"), []byte("partial"), 0o600))
+
+	tests := []struct {
+		description string
+		token       string
+		limit       int
+		names       []string
+		next        string
+	}{
+		{description: "first page", limit: 2, names: []string{"1_a_full.jpg", "1_b_full.jpg"}, next: "1_b_full.jpg"},
+		{description: "last page", token: "1_b_full.jpg", limit: 2, names: []string{"1_c_full.jpg"}},
+		{description: "page of all the files", limit: 3, names: []string{"1_a_full.jpg", "1_b_full.jpg", "1_c_full.jpg"}},
+		{description: "past the last file", token: "1_c_full.jpg", limit: 2, names: []string{}},
+	}
+
+	for i, tc := range tests {
+		files, next, err := store.List(ctx, tc.token, tc.limit)
+
+		names := make([]string, 0)
+		for _, file := range files {
+			names = append(names, file.Name)
+		}
+
+		assert.Equal(t, tc.names, names, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.next, next, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.NoError(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+
+	if files, _, _ := store.List(ctx, "", 1); assert.Len(t, files, 1) {
+		assert.Equal(t, int64(4), files[0].Size)
+		assert.False(t, files[0].ModifiedOn.IsZero())
+	}
+}
+
+// testConfig is a config of fixed values.
+type testConfig map[string]string
+
+func (c testConfig) Get(key string) string {
+	return c[key]
+}
+
+func (c testConfig) GetOrDefault(key, defaultVal string) string {
+	if val, ok := c[key]; ok {
+		return val
+	}
+
+	return defaultVal
+}
@@ -0,0 +1,229 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/configs"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	// uploadConcurrency is the number of parts of a file that are uploaded at a time.
+	uploadConcurrency = 2
+
+	// defaultKeyPrefix is the folder of the bucket the files are stored in, unless IMAGE_KEY_PREFIX is set.
+	defaultKeyPrefix = "images/"
+)
+
+// bucket stores images in an S3 bucket, of AWS or of an S3 compatible store such as MinIO.
+type bucket struct {
+	name    string
+	baseURL string // public URL of the bucket, which the keys of the files are appended to
+	prefix  string // folder of the files in the bucket, empty or ending with a slash
+}
+
+// NewS3 returns an Image store over the AWS_BUCKET bucket.
+// For S3 compatible stores, AWS_ENDPOINT is the URL of the store, and AWS_S3_FORCE_PATH_STYLE addresses
+// the bucket in the path of the URL instead of its host. IMAGE_PUBLIC_URL overrides the URL the images
+// are served at, such as that of a CDN in front of the bucket.
+// The files are kept in the IMAGE_KEY_PREFIX folder of the bucket, "images/" by default and the root of the bucket
+// for "/", and the files of the bucket outside of it are never listed or deleted.
+func NewS3(config configs.Config) stores.Image {
+	name := config.Get("AWS_BUCKET")
+
+	return bucket{name: name, baseURL: bucketURL(config, name), prefix: keyPrefix(config)}
+}
+
+func keyPrefix(config configs.Config) string {
+	prefix := strings.Trim(config.GetOrDefault("IMAGE_KEY_PREFIX", defaultKeyPrefix), "/")
+	if prefix == "" {
+		return ""
+	}
+
+	return prefix + "/"
+}
+
+// key returns the key of a file in the bucket.
+func (b bucket) key(name string) string {
+	return b.prefix + name
+}
+
+func bucketURL(config configs.Config, name string) string {
+	if publicURL := config.Get("IMAGE_PUBLIC_URL"); publicURL != "" {
+		return strings.TrimSuffix(publicURL, "/")
+	}
+
+	endpoint := strings.TrimSuffix(config.Get("AWS_ENDPOINT"), "/")
+	if endpoint == "" {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", name, config.GetOrDefault("AWS_REGION", "ap-south-1"))
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || config.Get("AWS_S3_FORCE_PATH_STYLE") == "true" {
+		return endpoint + "/" + name
+	}
+
+	return fmt.Sprintf("%s://%s.%s%s", u.Scheme, name, u.Host, u.Path)
+}
+
+// URL returns the public URL of a file in the bucket.
+func (b bucket) URL(name string) string {
+	return b.baseURL + "/" + (&url.URL{Path: b.key(name)}).EscapedPath()
+}
+
+// Upload streams an image file to the S3 bucket. Large files are sent in parts, a few at a time,
+// so that the memory taken by an upload is bounded whatever the size of the file.
+// The files stored by the app are the renditions of images, which are served publicly by their URL.
+func (b bucket) Upload(ctx *app.Context, name, contentType string, r io.Reader) error {
+	uploader := s3manager.NewUploaderWithClient(ctx.S3.S3, func(u *s3manager.Uploader) {
+		u.PartSize = s3manager.MinUploadPartSize
+		u.Concurrency = uploadConcurrency
+	})
+
+	_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(b.name),
+		Key:         aws.String(b.key(name)),
+		ACL:         aws.String("public-read"),
+		Body:        r,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// PresignUpload returns a URL to PUT a file in the bucket at. The content type and length are signed,
+// so that the storage rejects a file other than the one declared. The file is private, as it is read by the app alone
+// to be checked and processed, and must not be served before it is.
+func (b bucket) PresignUpload(ctx *app.Context, name, contentType string, size int64, expiry time.Duration) (string, map[string]string, error) {
+	req, _ := ctx.S3.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(b.name),
+		Key:           aws.String(b.key(name)),
+		ACL:           aws.String(s3.ObjectCannedACLPrivate),
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+
+	signedURL, header, err := req.PresignRequest(expiry)
+	if err != nil {
+		return "", nil, errors.DBError{Err: err}
+	}
+
+	headers := make(map[string]string, len(header))
+
+	// the signed headers are keyed as they are signed, in lower case, which Get does not find
+	for key, values := range header {
+		headers[key] = strings.Join(values, ",")
+	}
+
+	return signedURL, headers, nil
+}
+
+// Download reads a file from the bucket, up to one byte more than the limit.
+func (b bucket) Download(ctx *app.Context, name string, limit int64) ([]byte, error) {
+	out, err := ctx.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{Bucket: aws.String(b.name), Key: aws.String(b.key(name))})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errors.EntityNotFound{Entity: "file", ID: name}
+		}
+
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(out.Body, limit+1))
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return data, nil
+}
+
+// List lists a page of the files of the folder of the bucket by name, from the continuation token of the previous page.
+// Files in folders of their own under it, which the app never writes, are left out.
+func (b bucket) List(ctx *app.Context, token string, limit int) ([]models.StoredFile, string, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(b.name),
+		Prefix:    aws.String(b.prefix),
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int64(int64(limit)),
+	}
+
+	if token != "" {
+		input.ContinuationToken = aws.String(token)
+	}
+
+	out, err := ctx.S3.ListObjectsV2WithContext(ctx, input)
+	if err != nil {
+		return nil, "", errors.DBError{Err: err}
+	}
+
+	files := make([]models.StoredFile, 0, len(out.Contents))
+
+	for _, object := range out.Contents {
+		name := strings.TrimPrefix(aws.StringValue(object.Key), b.prefix)
+		if name == "" {
+			continue // the folder itself
+		}
+
+		files = append(files, models.StoredFile{
+			Name:       name,
+			Size:       aws.Int64Value(object.Size),
+			ModifiedOn: aws.TimeValue(object.LastModified),
+		})
+	}
+
+	if !aws.BoolValue(out.IsTruncated) {
+		return files, "", nil
+	}
+
+	return files, aws.StringValue(out.NextContinuationToken), nil
+}
+
+func (b bucket) DeleteBulk(ctx *app.Context, names []string) error {
+	svc := ctx.S3
+
+	objects := make([]*s3.ObjectIdentifier, 0)
+
+	for _, name := range names {
+		objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(b.key(name))})
+	}
+
+	input := &s3.DeleteObjectsInput{
+		Bucket: aws.String(b.name),
+		Delete: &s3.Delete{Objects: objects},
+	}
+
+	if err := input.Validate(); err != nil {
+		err := err.(request.ErrInvalidParams)
+
+		return errors.DBError{Err: err.OrigErr()}
+	}
+
+	_, err := svc.DeleteObjectsWithContext(ctx, input)
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
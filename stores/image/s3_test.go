@@ -0,0 +1,59 @@
+package image
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+)
+
+//nolint:lll // test cases need to be readable
+func TestBucket_URL(t *testing.T) {
+	tests := []struct {
+		description string
+		config      testConfig
+		output      string
+	}{
+		{description: "aws bucket in the configured region", config: testConfig{"AWS_BUCKET": "picshot", "AWS_REGION": "us-east-1"}, output: "https://picshot.s3.us-east-1.amazonaws.com/images/1_a_full.jpg"},
+		{description: "aws bucket in the default region", config: testConfig{"AWS_BUCKET": "picshot"}, output: "https://picshot.s3.ap-south-1.amazonaws.com/images/1_a_full.jpg"},
+		{description: "s3 compatible store, path style", config: testConfig{"AWS_BUCKET": "picshot", "AWS_ENDPOINT": "http://localhost:9000/", "AWS_S3_FORCE_PATH_STYLE": "true"}, output: "http://localhost:9000/picshot/images/1_a_full.jpg"},
+		{description: "s3 compatible store, virtual host style", config: testConfig{"AWS_BUCKET": "picshot", "AWS_ENDPOINT": "https://storage.example.com"}, output: "https://picshot.storage.example.com/images/1_a_full.jpg"},
+		{description: "public url of a cdn", config: testConfig{"AWS_BUCKET": "picshot", "IMAGE_PUBLIC_URL": "https://cdn.example.com/images/", "IMAGE_KEY_PREFIX": "/"}, output: "https://cdn.example.com/images/1_a_full.jpg"},
+		{description: "folder of its own", config: testConfig{"AWS_BUCKET": "picshot", "IMAGE_KEY_PREFIX": "/picshot/photos"}, output: "https://picshot.s3.ap-south-1.amazonaws.com/picshot/photos/1_a_full.jpg"},
+		{description: "root of the bucket", config: testConfig{"AWS_BUCKET": "picshot", "IMAGE_KEY_PREFIX": "/"}, output: "https://picshot.s3.ap-south-1.amazonaws.com/1_a_full.jpg"},
+	}
+
+	for i, tc := range tests {
+		output := NewS3(tc.config).URL("1_a_full.jpg")
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestBucket_PresignUpload(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("ap-south-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+
+	ctx := app.NewContext(nil, nil, &app.App{Logger: log.NewLogger(), DataStore: datastore.DataStore{S3: datastore.AWSS3{S3: s3.New(sess)}}})
+	ctx.Context = context.TODO()
+
+	signedURL, headers, err := NewS3(testConfig{"AWS_BUCKET": "picshot"}).PresignUpload(ctx, "1_upload_a.jpg", "image/jpeg", 4, time.Minute)
+
+	if assert.NoError(t, err) {
+		assert.Contains(t, signedURL, "/images/1_upload_a.jpg?")
+		assert.Equal(t, "private", headers["x-amz-acl"], "uploaded files are not served before they are processed")
+		assert.Equal(t, "image/jpeg", headers["content-type"])
+		assert.Equal(t, "4", headers["content-length"])
+	}
+}
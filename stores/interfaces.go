@@ -1,7 +1,8 @@
 package stores
 
 import (
-	"mime/multipart"
+	"io"
+	"time"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
 
@@ -25,37 +26,159 @@ type Account interface {
 	// Delete updates a delete request for an account and sets its status to inactive.
 	// Account is then permanently deleted after 30 days of inactivity.
 	Delete(c *app.Context, id int64) error
+
+	// Search retrieves active accounts whose username, first name or last name starts with the prefix.
+	// Matching is case-insensitive and exact matches are ranked first.
+	Search(c *app.Context, prefix string, page *models.Page) ([]*models.Account, error)
+}
+
+type Follow interface {
+	// Create makes an account follow another. Following an account twice is the same as following it once.
+	Create(c *app.Context, followerID, followeeID int64) error
+
+	// Delete makes an account stop following another.
+	Delete(c *app.Context, followerID, followeeID int64) error
+
+	// Exists tells if an account follows another.
+	Exists(c *app.Context, followerID, followeeID int64) (bool, error)
+
+	// GetFollowees retrieves the ids of the accounts an account follows.
+	GetFollowees(c *app.Context, followerID int64) ([]int64, error)
+}
+
+type Block interface {
+	// Create makes an account block another. Blocking an account twice is the same as blocking it once.
+	Create(c *app.Context, blockerID, blockedID int64) error
+
+	// Delete makes an account stop blocking another.
+	Delete(c *app.Context, blockerID, blockedID int64) error
+
+	// Exists tells if either of two accounts blocks the other.
+	Exists(c *app.Context, id, otherID int64) (bool, error)
+
+	// GetBlocked retrieves the accounts an account blocks.
+	GetBlocked(c *app.Context, blockerID int64) ([]*models.User, error)
+
+	// GetRelated retrieves the ids of the accounts an account blocks, or that block it.
+	GetRelated(c *app.Context, id int64) ([]int64, error)
+}
+
+type Mute interface {
+	// Create makes an account mute another. Muting an account twice is the same as muting it once.
+	Create(c *app.Context, muterID, mutedID int64) error
+
+	// Delete makes an account stop muting another.
+	Delete(c *app.Context, muterID, mutedID int64) error
+
+	// GetMuted retrieves the accounts an account mutes.
+	GetMuted(c *app.Context, muterID int64) ([]*models.User, error)
+}
+
+type Report interface {
+	// GetAll retrieves the reports that match the filter, oldest first.
+	GetAll(c *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error)
+
+	// Get retrieves a report by its ID.
+	Get(c *app.Context, id string) (*models.Report, error)
+
+	// Create creates a report, and fails with EntityAlreadyExists if the reporter has an open report on the same blog or user.
+	Create(c *app.Context, model *models.Report) error
+
+	// Close closes the open reports that match the filter with the status and action given, and returns how many it closed.
+	Close(c *app.Context, filter *models.ReportFilter, status, actionID string) (int64, error)
+}
+
+type Audit interface {
+	// GetAll retrieves the moderation actions that match the filter, latest first.
+	GetAll(c *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error)
+
+	// Create records a moderation action.
+	Create(c *app.Context, model *models.ModerationAction) error
+
+	// SetStatus records the status a moderation action ended in, along with the user it was taken on.
+	SetStatus(c *app.Context, model *models.ModerationAction) error
 }
 
 type Blog interface {
-	// GetAll is used to retrieve all blogs that match the filter.
-	// BLogs can be filtered by account_id, blog_id and title.
-	GetAll(c *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error)
+	// GetAll is used to retrieve all blogs that match the filter, in the sort order of the filter.
+	// BLogs can be filtered by account_id, blog_id, title, creation time, tags and images.
+	GetAll(c *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error)
 
 	// GetByIDs retrieves all blogs whose IDs have been provided as parameter.
 	GetByIDs(c *app.Context, idList []string, page *models.Page) ([]*models.Blog, error)
 
+	// GetScheduled retrieves the scheduled blogs that are due to be published before the given time.
+	GetScheduled(c *app.Context, before time.Time, limit int64) ([]*models.Blog, error)
+
+	// GetBySlug retrieves a blog of an account by its slug, or by one of its old slugs if no blog has the slug.
+	GetBySlug(c *app.Context, accountID int64, slug string) (*models.Blog, error)
+
+	// GetSlugs retrieves the slugs, current and old, of the blogs of an account that are the base slug or the base slug
+	// with a numeric suffix, mapped to the IDs of the blogs that hold them. A current slug is held over an old one.
+	GetSlugs(c *app.Context, accountID int64, base string) (map[string]string, error)
+
+	// GetStale retrieves the blogs whose content is rendered by an older version than the given one, oldest first.
+	GetStale(c *app.Context, version int, limit int64) ([]*models.Blog, error)
+
+	// SetRendered stores the rendered content of a blog, and fails with EntityNotFound if its content changed since it was read.
+	SetRendered(c *app.Context, model *models.Blog) error
+
+	// EachImage calls fn with the images of every blog as they are read, for the storage to be reconciled with them.
+	EachImage(c *app.Context, fn func(image models.Image)) error
+
+	// SetHidden hides a blog from everyone but its author, or lists it again, and returns it.
+	SetHidden(c *app.Context, blogID string, hidden bool) (*models.Blog, error)
+
+	// Publish publishes a scheduled blog, and fails with EntityNotFound if it is no longer scheduled.
+	Publish(c *app.Context, blogID string, publishedOn time.Time) (*models.Blog, error)
+
 	// Get is used to retrieve a SINGLE blog that matches the filter.
 	// A blog can be filtered by account_id, blog_id and title.
 	Get(c *app.Context, filter *models.Blog) (*models.Blog, error)
 
-	// Create is used to create a new blog.
+	// Create is used to create a new blog. A slug the account has taken already fails with EntityAlreadyExists.
 	Create(c *app.Context, model *models.Blog) (*models.Blog, error)
 
 	// Update updates the blog by its ID.
 	// Images and Tags can be added and not deleted todo
+	// A slug the account has taken already fails with EntityAlreadyExists.
 	Update(c *app.Context, model *models.Blog) (*models.Blog, error)
 
+	// Revert writes back a version of a blog over an update, by its ID and the account of its author.
+	// Every field an update writes is set as it is in the model, and those that are empty are unset.
+	Revert(c *app.Context, model *models.Blog) error
+
 	// Delete deletes a blog by its ID.
 	Delete(c *app.Context, blogID string) error
 }
 
+type Revision interface {
+	// GetAll retrieves the revisions of a blog, latest first.
+	GetAll(c *app.Context, blogID string, page *models.Page) ([]*models.Revision, error)
+
+	// Get retrieves a revision of a blog by its number.
+	Get(c *app.Context, blogID string, number int64) (*models.Revision, error)
+
+	// GetLatest retrieves the latest revision of a blog.
+	GetLatest(c *app.Context, blogID string) (*models.Revision, error)
+
+	// Create records a revision, and fails with EntityAlreadyExists if its number is taken.
+	Create(c *app.Context, model *models.Revision) error
+}
+
 type Tag interface {
 	// Get retrieves a tag by its name.
 	// A tag name uniquely identifies a tag entity.
 	// The tag entity has tag name and list of blog_id's associated with the tag.
 	Get(c *app.Context, name string) (*models.Tag, error)
 
+	// GetAll retrieves tags with their blog counts, without the list of blog_id's.
+	// Tags are sorted by popularity (most blogs first) or by name.
+	GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error)
+
+	// GetByPrefix retrieves the most popular tags whose name starts with the prefix.
+	GetByPrefix(c *app.Context, prefix string, limit int64) ([]*models.Tag, error)
+
 	// Update adds blog_id to given list of tags.
 	// Tags are created if they do not exist already.
 	Update(c *app.Context, blogID string, tag string, operation constants.Operation) error
@@ -65,12 +188,94 @@ type Tag interface {
 
 	// Delete removes a tag by its name.
 	Delete(c *app.Context, tag string) error
+
+	// AddEvent records a usage of a tag.
+	AddEvent(c *app.Context, event *models.TagEvent) error
+
+	// DeleteEvents removes the usages of a tag by a blog, once the tag is taken off the blog or the blog is deleted.
+	DeleteEvents(c *app.Context, tag, blogID string) error
+
+	// GetTrending compares the usage of tags since windowStart with their usage between baselineStart and windowStart.
+	// Tags whose usage has grown are returned, fastest growing first.
+	GetTrending(c *app.Context, windowStart, baselineStart time.Time, limit int64) ([]*models.TrendingTag, error)
 }
 
+// Image is a storage provider of image files, such as an S3 bucket or a local directory.
 type Image interface {
-	// Upload uploads a file to S3 Bucket.
-	Upload(c *app.Context, fileHeader *multipart.FileHeader, name string) error
+	// Upload streams a file to the storage.
+	Upload(c *app.Context, name, contentType string, r io.Reader) error
+
+	// URL returns the public URL a stored file is served at.
+	URL(name string) string
+
+	// PresignUpload returns a URL, valid for the expiry, that a client can PUT a file of the content type
+	// and size at, along with the headers to be sent with it. Providers that cannot presign URLs fail with Unsupported.
+	PresignUpload(c *app.Context, name, contentType string, size int64, expiry time.Duration) (string, map[string]string, error)
+
+	// Download reads a file from the storage, up to one byte more than the limit,
+	// so that a file larger than the limit can be told apart. A missing file is EntityNotFound.
+	Download(c *app.Context, name string, limit int64) ([]byte, error)
+
+	// List lists a page of up to limit files of the storage by name, from the token of the previous page or from the
+	// first file for an empty token. It returns the token of the next page, which is empty after the last page.
+	List(c *app.Context, token string, limit int) ([]models.StoredFile, string, error)
 
 	// DeleteBulk deletes multiple files whose names are passed as parameter.
 	DeleteBulk(ctx *app.Context, names []string) error
 }
+
+// Blob counts the references to the content of the pictures, which are stored once however many images show them.
+type Blob interface {
+	// Acquire adds a reference to a blob that is referenced already, and returns it.
+	// It fails with EntityNotFound if there is no such blob.
+	Acquire(c *app.Context, hash string) (*models.Blob, error)
+
+	// Add adds a reference to a blob, creating it if it does not exist, and returns it.
+	Add(c *app.Context, model *models.Blob) (*models.Blob, error)
+
+	// Release removes a reference from a blob, and returns it.
+	// It fails with EntityNotFound if there is no such blob.
+	Release(c *app.Context, hash string) (*models.Blob, error)
+
+	// Delete removes a blob that has no references left.
+	// It fails with EntityNotFound if the blob is referenced again.
+	Delete(c *app.Context, hash string) error
+
+	// Each calls fn with every blob as it is read.
+	Each(c *app.Context, fn func(blob *models.Blob)) error
+}
+
+type Upload interface {
+	// Get retrieves an upload by its ID.
+	Get(c *app.Context, id string) (*models.Upload, error)
+
+	// Create creates an upload.
+	Create(c *app.Context, model *models.Upload) error
+
+	// Confirm sets the image of a pending upload that has not expired, and the time it must be attached by.
+	// It fails with EntityNotFound if there is no such upload.
+	Confirm(c *app.Context, id string, image *models.Image, expiresAt time.Time) (*models.Upload, error)
+
+	// Attach removes a confirmed upload of an account that has not expired, and returns it.
+	// It fails with EntityNotFound if there is no such upload.
+	Attach(c *app.Context, id string, accountID int64) (*models.Upload, error)
+
+	// GetExpired retrieves uploads that expired before a time, oldest first.
+	GetExpired(c *app.Context, before time.Time, limit int64) ([]*models.Upload, error)
+
+	// Expire removes an upload that expired before a time, and returns it.
+	// It fails with EntityNotFound if there is no such upload.
+	Expire(c *app.Context, id string, before time.Time) (*models.Upload, error)
+}
+
+type SearchIndex interface {
+	// Index adds a blog to the search index, replacing any earlier version of it.
+	Index(c *app.Context, blog *models.Blog) error
+
+	// Remove removes a blog from the search index.
+	Remove(c *app.Context, blogID string) error
+
+	// Search retrieves the blogs that match the query, most relevant first.
+	// Title, summary, content and tags are searched, and the blogs of the hidden accounts are left out.
+	Search(c *app.Context, query string, hidden []int64, page *models.Page) ([]*models.SearchHit, error)
+}
@@ -5,8 +5,9 @@
 package stores
 
 import (
-	multipart "mime/multipart"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/Aakanksha-jais/picshot-golang-backend/models"
 	app "github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
@@ -96,6 +97,21 @@ func (mr *MockAccountMockRecorder) GetAll(c, filter interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAccount)(nil).GetAll), c, filter)
 }
 
+// Search mocks base method.
+func (m *MockAccount) Search(c *app.Context, prefix string, page *models.Page) ([]*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", c, prefix, page)
+	ret0, _ := ret[0].([]*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockAccountMockRecorder) Search(c, prefix, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAccount)(nil).Search), c, prefix, page)
+}
+
 // Update mocks base method.
 func (m *MockAccount) Update(c *app.Context, model *models.Account) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +127,397 @@ func (mr *MockAccountMockRecorder) Update(c, model interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccount)(nil).Update), c, model)
 }
 
+// MockFollow is a mock of Follow interface.
+type MockFollow struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowMockRecorder
+}
+
+// MockFollowMockRecorder is the mock recorder for MockFollow.
+type MockFollowMockRecorder struct {
+	mock *MockFollow
+}
+
+// NewMockFollow creates a new mock instance.
+func NewMockFollow(ctrl *gomock.Controller) *MockFollow {
+	mock := &MockFollow{ctrl: ctrl}
+	mock.recorder = &MockFollowMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollow) EXPECT() *MockFollowMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFollow) Create(c *app.Context, followerID, followeeID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, followerID, followeeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFollowMockRecorder) Create(c, followerID, followeeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFollow)(nil).Create), c, followerID, followeeID)
+}
+
+// Delete mocks base method.
+func (m *MockFollow) Delete(c *app.Context, followerID, followeeID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, followerID, followeeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFollowMockRecorder) Delete(c, followerID, followeeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFollow)(nil).Delete), c, followerID, followeeID)
+}
+
+// Exists mocks base method.
+func (m *MockFollow) Exists(c *app.Context, followerID, followeeID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", c, followerID, followeeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockFollowMockRecorder) Exists(c, followerID, followeeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockFollow)(nil).Exists), c, followerID, followeeID)
+}
+
+// GetFollowees mocks base method.
+func (m *MockFollow) GetFollowees(c *app.Context, followerID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowees", c, followerID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowees indicates an expected call of GetFollowees.
+func (mr *MockFollowMockRecorder) GetFollowees(c, followerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowees", reflect.TypeOf((*MockFollow)(nil).GetFollowees), c, followerID)
+}
+
+// MockBlock is a mock of Block interface.
+type MockBlock struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockMockRecorder
+}
+
+// MockBlockMockRecorder is the mock recorder for MockBlock.
+type MockBlockMockRecorder struct {
+	mock *MockBlock
+}
+
+// NewMockBlock creates a new mock instance.
+func NewMockBlock(ctrl *gomock.Controller) *MockBlock {
+	mock := &MockBlock{ctrl: ctrl}
+	mock.recorder = &MockBlockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlock) EXPECT() *MockBlockMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBlock) Create(c *app.Context, blockerID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBlockMockRecorder) Create(c, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBlock)(nil).Create), c, blockerID, blockedID)
+}
+
+// Delete mocks base method.
+func (m *MockBlock) Delete(c *app.Context, blockerID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlockMockRecorder) Delete(c, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlock)(nil).Delete), c, blockerID, blockedID)
+}
+
+// Exists mocks base method.
+func (m *MockBlock) Exists(c *app.Context, id, otherID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", c, id, otherID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockBlockMockRecorder) Exists(c, id, otherID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockBlock)(nil).Exists), c, id, otherID)
+}
+
+// GetBlocked mocks base method.
+func (m *MockBlock) GetBlocked(c *app.Context, blockerID int64) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocked", c, blockerID)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocked indicates an expected call of GetBlocked.
+func (mr *MockBlockMockRecorder) GetBlocked(c, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocked", reflect.TypeOf((*MockBlock)(nil).GetBlocked), c, blockerID)
+}
+
+// GetRelated mocks base method.
+func (m *MockBlock) GetRelated(c *app.Context, id int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelated", c, id)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelated indicates an expected call of GetRelated.
+func (mr *MockBlockMockRecorder) GetRelated(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelated", reflect.TypeOf((*MockBlock)(nil).GetRelated), c, id)
+}
+
+// MockMute is a mock of Mute interface.
+type MockMute struct {
+	ctrl     *gomock.Controller
+	recorder *MockMuteMockRecorder
+}
+
+// MockMuteMockRecorder is the mock recorder for MockMute.
+type MockMuteMockRecorder struct {
+	mock *MockMute
+}
+
+// NewMockMute creates a new mock instance.
+func NewMockMute(ctrl *gomock.Controller) *MockMute {
+	mock := &MockMute{ctrl: ctrl}
+	mock.recorder = &MockMuteMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMute) EXPECT() *MockMuteMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMute) Create(c *app.Context, muterID, mutedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMuteMockRecorder) Create(c, muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMute)(nil).Create), c, muterID, mutedID)
+}
+
+// Delete mocks base method.
+func (m *MockMute) Delete(c *app.Context, muterID, mutedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, muterID, mutedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMuteMockRecorder) Delete(c, muterID, mutedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMute)(nil).Delete), c, muterID, mutedID)
+}
+
+// GetMuted mocks base method.
+func (m *MockMute) GetMuted(c *app.Context, muterID int64) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMuted", c, muterID)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMuted indicates an expected call of GetMuted.
+func (mr *MockMuteMockRecorder) GetMuted(c, muterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMuted", reflect.TypeOf((*MockMute)(nil).GetMuted), c, muterID)
+}
+
+// MockReport is a mock of Report interface.
+type MockReport struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportMockRecorder
+}
+
+// MockReportMockRecorder is the mock recorder for MockReport.
+type MockReportMockRecorder struct {
+	mock *MockReport
+}
+
+// NewMockReport creates a new mock instance.
+func NewMockReport(ctrl *gomock.Controller) *MockReport {
+	mock := &MockReport{ctrl: ctrl}
+	mock.recorder = &MockReportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReport) EXPECT() *MockReportMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockReport) Close(c *app.Context, filter *models.ReportFilter, status, actionID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", c, filter, status, actionID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockReportMockRecorder) Close(c, filter, status, actionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockReport)(nil).Close), c, filter, status, actionID)
+}
+
+// Create mocks base method.
+func (m *MockReport) Create(c *app.Context, model *models.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReportMockRecorder) Create(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReport)(nil).Create), c, model)
+}
+
+// Get mocks base method.
+func (m *MockReport) Get(c *app.Context, id string) (*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", c, id)
+	ret0, _ := ret[0].(*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReportMockRecorder) Get(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReport)(nil).Get), c, id)
+}
+
+// GetAll mocks base method.
+func (m *MockReport) GetAll(c *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, filter, page)
+	ret0, _ := ret[0].([]*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockReportMockRecorder) GetAll(c, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockReport)(nil).GetAll), c, filter, page)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAudit) Create(c *app.Context, model *models.ModerationAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditMockRecorder) Create(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAudit)(nil).Create), c, model)
+}
+
+// GetAll mocks base method.
+func (m *MockAudit) GetAll(c *app.Context, filter *models.ModerationFilter, page *models.Page) ([]*models.ModerationAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, filter, page)
+	ret0, _ := ret[0].([]*models.ModerationAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuditMockRecorder) GetAll(c, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAudit)(nil).GetAll), c, filter, page)
+}
+
+// SetStatus mocks base method.
+func (m *MockAudit) SetStatus(c *app.Context, model *models.ModerationAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockAuditMockRecorder) SetStatus(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockAudit)(nil).SetStatus), c, model)
+}
+
 // MockBlog is a mock of Blog interface.
 type MockBlog struct {
 	ctrl     *gomock.Controller
@@ -160,67 +567,281 @@ func (m *MockBlog) Delete(c *app.Context, blogID string) error {
 // Delete indicates an expected call of Delete.
 func (mr *MockBlogMockRecorder) Delete(c, blogID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlog)(nil).Delete), c, blogID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlog)(nil).Delete), c, blogID)
+}
+
+// EachImage mocks base method.
+func (m *MockBlog) EachImage(c *app.Context, fn func(models.Image)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachImage", c, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachImage indicates an expected call of EachImage.
+func (mr *MockBlogMockRecorder) EachImage(c, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachImage", reflect.TypeOf((*MockBlog)(nil).EachImage), c, fn)
+}
+
+// Get mocks base method.
+func (m *MockBlog) Get(c *app.Context, filter *models.Blog) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", c, filter)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlogMockRecorder) Get(c, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlog)(nil).Get), c, filter)
+}
+
+// GetAll mocks base method.
+func (m *MockBlog) GetAll(c *app.Context, filter *models.BlogFilter, page *models.Page) ([]*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, filter, page)
+	ret0, _ := ret[0].([]*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockBlogMockRecorder) GetAll(c, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBlog)(nil).GetAll), c, filter, page)
+}
+
+// GetByIDs mocks base method.
+func (m *MockBlog) GetByIDs(c *app.Context, idList []string, page *models.Page) ([]*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", c, idList, page)
+	ret0, _ := ret[0].([]*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockBlogMockRecorder) GetByIDs(c, idList, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockBlog)(nil).GetByIDs), c, idList, page)
+}
+
+// GetBySlug mocks base method.
+func (m *MockBlog) GetBySlug(c *app.Context, accountID int64, slug string) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", c, accountID, slug)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockBlogMockRecorder) GetBySlug(c, accountID, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockBlog)(nil).GetBySlug), c, accountID, slug)
+}
+
+// GetScheduled mocks base method.
+func (m *MockBlog) GetScheduled(c *app.Context, before time.Time, limit int64) ([]*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduled", c, before, limit)
+	ret0, _ := ret[0].([]*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduled indicates an expected call of GetScheduled.
+func (mr *MockBlogMockRecorder) GetScheduled(c, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduled", reflect.TypeOf((*MockBlog)(nil).GetScheduled), c, before, limit)
+}
+
+// GetSlugs mocks base method.
+func (m *MockBlog) GetSlugs(c *app.Context, accountID int64, base string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSlugs", c, accountID, base)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSlugs indicates an expected call of GetSlugs.
+func (mr *MockBlogMockRecorder) GetSlugs(c, accountID, base interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSlugs", reflect.TypeOf((*MockBlog)(nil).GetSlugs), c, accountID, base)
+}
+
+// GetStale mocks base method.
+func (m *MockBlog) GetStale(c *app.Context, version int, limit int64) ([]*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", c, version, limit)
+	ret0, _ := ret[0].([]*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockBlogMockRecorder) GetStale(c, version, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockBlog)(nil).GetStale), c, version, limit)
+}
+
+// Publish mocks base method.
+func (m *MockBlog) Publish(c *app.Context, blogID string, publishedOn time.Time) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", c, blogID, publishedOn)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockBlogMockRecorder) Publish(c, blogID, publishedOn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockBlog)(nil).Publish), c, blogID, publishedOn)
+}
+
+// Revert mocks base method.
+func (m *MockBlog) Revert(c *app.Context, model *models.Blog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockBlogMockRecorder) Revert(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockBlog)(nil).Revert), c, model)
+}
+
+// SetHidden mocks base method.
+func (m *MockBlog) SetHidden(c *app.Context, blogID string, hidden bool) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHidden", c, blogID, hidden)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHidden indicates an expected call of SetHidden.
+func (mr *MockBlogMockRecorder) SetHidden(c, blogID, hidden interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHidden", reflect.TypeOf((*MockBlog)(nil).SetHidden), c, blogID, hidden)
+}
+
+// SetRendered mocks base method.
+func (m *MockBlog) SetRendered(c *app.Context, model *models.Blog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRendered", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRendered indicates an expected call of SetRendered.
+func (mr *MockBlogMockRecorder) SetRendered(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRendered", reflect.TypeOf((*MockBlog)(nil).SetRendered), c, model)
+}
+
+// Update mocks base method.
+func (m *MockBlog) Update(c *app.Context, model *models.Blog) (*models.Blog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c, model)
+	ret0, _ := ret[0].(*models.Blog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBlogMockRecorder) Update(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBlog)(nil).Update), c, model)
+}
+
+// MockRevision is a mock of Revision interface.
+type MockRevision struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionMockRecorder
+}
+
+// MockRevisionMockRecorder is the mock recorder for MockRevision.
+type MockRevisionMockRecorder struct {
+	mock *MockRevision
+}
+
+// NewMockRevision creates a new mock instance.
+func NewMockRevision(ctrl *gomock.Controller) *MockRevision {
+	mock := &MockRevision{ctrl: ctrl}
+	mock.recorder = &MockRevisionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevision) EXPECT() *MockRevisionMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRevision) Create(c *app.Context, model *models.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRevisionMockRecorder) Create(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRevision)(nil).Create), c, model)
 }
 
 // Get mocks base method.
-func (m *MockBlog) Get(c *app.Context, filter *models.Blog) (*models.Blog, error) {
+func (m *MockRevision) Get(c *app.Context, blogID string, number int64) (*models.Revision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", c, filter)
-	ret0, _ := ret[0].(*models.Blog)
+	ret := m.ctrl.Call(m, "Get", c, blogID, number)
+	ret0, _ := ret[0].(*models.Revision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockBlogMockRecorder) Get(c, filter interface{}) *gomock.Call {
+func (mr *MockRevisionMockRecorder) Get(c, blogID, number interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlog)(nil).Get), c, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRevision)(nil).Get), c, blogID, number)
 }
 
 // GetAll mocks base method.
-func (m *MockBlog) GetAll(c *app.Context, filter *models.Blog, page *models.Page) ([]*models.Blog, error) {
+func (m *MockRevision) GetAll(c *app.Context, blogID string, page *models.Page) ([]*models.Revision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", c, filter, page)
-	ret0, _ := ret[0].([]*models.Blog)
+	ret := m.ctrl.Call(m, "GetAll", c, blogID, page)
+	ret0, _ := ret[0].([]*models.Revision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockBlogMockRecorder) GetAll(c, filter, page interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBlog)(nil).GetAll), c, filter, page)
-}
-
-// GetByIDs mocks base method.
-func (m *MockBlog) GetByIDs(c *app.Context, idList []string, page *models.Page) ([]*models.Blog, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIDs", c, idList, page)
-	ret0, _ := ret[0].([]*models.Blog)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetByIDs indicates an expected call of GetByIDs.
-func (mr *MockBlogMockRecorder) GetByIDs(c, idList, page interface{}) *gomock.Call {
+func (mr *MockRevisionMockRecorder) GetAll(c, blogID, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockBlog)(nil).GetByIDs), c, idList, page)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRevision)(nil).GetAll), c, blogID, page)
 }
 
-// Update mocks base method.
-func (m *MockBlog) Update(c *app.Context, model *models.Blog) (*models.Blog, error) {
+// GetLatest mocks base method.
+func (m *MockRevision) GetLatest(c *app.Context, blogID string) (*models.Revision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", c, model)
-	ret0, _ := ret[0].(*models.Blog)
+	ret := m.ctrl.Call(m, "GetLatest", c, blogID)
+	ret0, _ := ret[0].(*models.Revision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockBlogMockRecorder) Update(c, model interface{}) *gomock.Call {
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockRevisionMockRecorder) GetLatest(c, blogID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBlog)(nil).Update), c, model)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockRevision)(nil).GetLatest), c, blogID)
 }
 
 // MockTag is a mock of Tag interface.
@@ -246,6 +867,20 @@ func (m *MockTag) EXPECT() *MockTagMockRecorder {
 	return m.recorder
 }
 
+// AddEvent mocks base method.
+func (m *MockTag) AddEvent(c *app.Context, event *models.TagEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", c, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *MockTagMockRecorder) AddEvent(c, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockTag)(nil).AddEvent), c, event)
+}
+
 // Create mocks base method.
 func (m *MockTag) Create(c *app.Context, tag *models.Tag) error {
 	m.ctrl.T.Helper()
@@ -274,6 +909,20 @@ func (mr *MockTagMockRecorder) Delete(c, tag interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTag)(nil).Delete), c, tag)
 }
 
+// DeleteEvents mocks base method.
+func (m *MockTag) DeleteEvents(c *app.Context, tag, blogID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvents", c, tag, blogID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvents indicates an expected call of DeleteEvents.
+func (mr *MockTagMockRecorder) DeleteEvents(c, tag, blogID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvents", reflect.TypeOf((*MockTag)(nil).DeleteEvents), c, tag, blogID)
+}
+
 // Get mocks base method.
 func (m *MockTag) Get(c *app.Context, name string) (*models.Tag, error) {
 	m.ctrl.T.Helper()
@@ -289,6 +938,51 @@ func (mr *MockTagMockRecorder) Get(c, name interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTag)(nil).Get), c, name)
 }
 
+// GetAll mocks base method.
+func (m *MockTag) GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, page, sortBy)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagMockRecorder) GetAll(c, page, sortBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTag)(nil).GetAll), c, page, sortBy)
+}
+
+// GetByPrefix mocks base method.
+func (m *MockTag) GetByPrefix(c *app.Context, prefix string, limit int64) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPrefix", c, prefix, limit)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPrefix indicates an expected call of GetByPrefix.
+func (mr *MockTagMockRecorder) GetByPrefix(c, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrefix", reflect.TypeOf((*MockTag)(nil).GetByPrefix), c, prefix, limit)
+}
+
+// GetTrending mocks base method.
+func (m *MockTag) GetTrending(c *app.Context, windowStart, baselineStart time.Time, limit int64) ([]*models.TrendingTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrending", c, windowStart, baselineStart, limit)
+	ret0, _ := ret[0].([]*models.TrendingTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrending indicates an expected call of GetTrending.
+func (mr *MockTagMockRecorder) GetTrending(c, windowStart, baselineStart, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrending", reflect.TypeOf((*MockTag)(nil).GetTrending), c, windowStart, baselineStart, limit)
+}
+
 // Update mocks base method.
 func (m *MockTag) Update(c *app.Context, blogID, tag string, operation constants.Operation) error {
 	m.ctrl.T.Helper()
@@ -340,16 +1034,351 @@ func (mr *MockImageMockRecorder) DeleteBulk(ctx, names interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBulk", reflect.TypeOf((*MockImage)(nil).DeleteBulk), ctx, names)
 }
 
+// Download mocks base method.
+func (m *MockImage) Download(c *app.Context, name string, limit int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", c, name, limit)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockImageMockRecorder) Download(c, name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockImage)(nil).Download), c, name, limit)
+}
+
+// List mocks base method.
+func (m *MockImage) List(c *app.Context, token string, limit int) ([]models.StoredFile, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", c, token, limit)
+	ret0, _ := ret[0].([]models.StoredFile)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockImageMockRecorder) List(c, token, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockImage)(nil).List), c, token, limit)
+}
+
+// PresignUpload mocks base method.
+func (m *MockImage) PresignUpload(c *app.Context, name, contentType string, size int64, expiry time.Duration) (string, map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignUpload", c, name, contentType, size, expiry)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(map[string]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PresignUpload indicates an expected call of PresignUpload.
+func (mr *MockImageMockRecorder) PresignUpload(c, name, contentType, size, expiry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignUpload", reflect.TypeOf((*MockImage)(nil).PresignUpload), c, name, contentType, size, expiry)
+}
+
+// URL mocks base method.
+func (m *MockImage) URL(name string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", name)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockImageMockRecorder) URL(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockImage)(nil).URL), name)
+}
+
 // Upload mocks base method.
-func (m *MockImage) Upload(c *app.Context, fileHeader *multipart.FileHeader, name string) error {
+func (m *MockImage) Upload(c *app.Context, name, contentType string, r io.Reader) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", c, fileHeader, name)
+	ret := m.ctrl.Call(m, "Upload", c, name, contentType, r)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Upload indicates an expected call of Upload.
-func (mr *MockImageMockRecorder) Upload(c, fileHeader, name interface{}) *gomock.Call {
+func (mr *MockImageMockRecorder) Upload(c, name, contentType, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockImage)(nil).Upload), c, name, contentType, r)
+}
+
+// MockBlob is a mock of Blob interface.
+type MockBlob struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobMockRecorder
+}
+
+// MockBlobMockRecorder is the mock recorder for MockBlob.
+type MockBlobMockRecorder struct {
+	mock *MockBlob
+}
+
+// NewMockBlob creates a new mock instance.
+func NewMockBlob(ctrl *gomock.Controller) *MockBlob {
+	mock := &MockBlob{ctrl: ctrl}
+	mock.recorder = &MockBlobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlob) EXPECT() *MockBlobMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockBlob) Acquire(c *app.Context, hash string) (*models.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", c, hash)
+	ret0, _ := ret[0].(*models.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockBlobMockRecorder) Acquire(c, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockBlob)(nil).Acquire), c, hash)
+}
+
+// Add mocks base method.
+func (m *MockBlob) Add(c *app.Context, model *models.Blob) (*models.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", c, model)
+	ret0, _ := ret[0].(*models.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockBlobMockRecorder) Add(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBlob)(nil).Add), c, model)
+}
+
+// Delete mocks base method.
+func (m *MockBlob) Delete(c *app.Context, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobMockRecorder) Delete(c, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlob)(nil).Delete), c, hash)
+}
+
+// Each mocks base method.
+func (m *MockBlob) Each(c *app.Context, fn func(*models.Blob)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Each", c, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Each indicates an expected call of Each.
+func (mr *MockBlobMockRecorder) Each(c, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Each", reflect.TypeOf((*MockBlob)(nil).Each), c, fn)
+}
+
+// Release mocks base method.
+func (m *MockBlob) Release(c *app.Context, hash string) (*models.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", c, hash)
+	ret0, _ := ret[0].(*models.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockBlobMockRecorder) Release(c, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockBlob)(nil).Release), c, hash)
+}
+
+// MockUpload is a mock of Upload interface.
+type MockUpload struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadMockRecorder
+}
+
+// MockUploadMockRecorder is the mock recorder for MockUpload.
+type MockUploadMockRecorder struct {
+	mock *MockUpload
+}
+
+// NewMockUpload creates a new mock instance.
+func NewMockUpload(ctrl *gomock.Controller) *MockUpload {
+	mock := &MockUpload{ctrl: ctrl}
+	mock.recorder = &MockUploadMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpload) EXPECT() *MockUploadMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockUpload) Attach(c *app.Context, id string, accountID int64) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", c, id, accountID)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockUploadMockRecorder) Attach(c, id, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockUpload)(nil).Attach), c, id, accountID)
+}
+
+// Confirm mocks base method.
+func (m *MockUpload) Confirm(c *app.Context, id string, image *models.Image, expiresAt time.Time) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", c, id, image, expiresAt)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockUploadMockRecorder) Confirm(c, id, image, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockUpload)(nil).Confirm), c, id, image, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockUpload) Create(c *app.Context, model *models.Upload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUploadMockRecorder) Create(c, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUpload)(nil).Create), c, model)
+}
+
+// Expire mocks base method.
+func (m *MockUpload) Expire(c *app.Context, id string, before time.Time) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", c, id, before)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockUploadMockRecorder) Expire(c, id, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockUpload)(nil).Expire), c, id, before)
+}
+
+// Get mocks base method.
+func (m *MockUpload) Get(c *app.Context, id string) (*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", c, id)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUploadMockRecorder) Get(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUpload)(nil).Get), c, id)
+}
+
+// GetExpired mocks base method.
+func (m *MockUpload) GetExpired(c *app.Context, before time.Time, limit int64) ([]*models.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", c, before, limit)
+	ret0, _ := ret[0].([]*models.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockUploadMockRecorder) GetExpired(c, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockUpload)(nil).GetExpired), c, before, limit)
+}
+
+// MockSearchIndex is a mock of SearchIndex interface.
+type MockSearchIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchIndexMockRecorder
+}
+
+// MockSearchIndexMockRecorder is the mock recorder for MockSearchIndex.
+type MockSearchIndexMockRecorder struct {
+	mock *MockSearchIndex
+}
+
+// NewMockSearchIndex creates a new mock instance.
+func NewMockSearchIndex(ctrl *gomock.Controller) *MockSearchIndex {
+	mock := &MockSearchIndex{ctrl: ctrl}
+	mock.recorder = &MockSearchIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchIndex) EXPECT() *MockSearchIndexMockRecorder {
+	return m.recorder
+}
+
+// Index mocks base method.
+func (m *MockSearchIndex) Index(c *app.Context, blog *models.Blog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Index", c, blog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Index indicates an expected call of Index.
+func (mr *MockSearchIndexMockRecorder) Index(c, blog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Index", reflect.TypeOf((*MockSearchIndex)(nil).Index), c, blog)
+}
+
+// Remove mocks base method.
+func (m *MockSearchIndex) Remove(c *app.Context, blogID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", c, blogID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockSearchIndexMockRecorder) Remove(c, blogID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockSearchIndex)(nil).Remove), c, blogID)
+}
+
+// Search mocks base method.
+func (m *MockSearchIndex) Search(c *app.Context, query string, hidden []int64, page *models.Page) ([]*models.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", c, query, hidden, page)
+	ret0, _ := ret[0].([]*models.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchIndexMockRecorder) Search(c, query, hidden, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockImage)(nil).Upload), c, fileHeader, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchIndex)(nil).Search), c, query, hidden, page)
 }
@@ -0,0 +1,68 @@
+package mute
+
+import (
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+type mute struct{}
+
+// New returns a mute store, on the mutes table. A mute is keyed by (muter_id, muted_id).
+func New() stores.Mute {
+	return mute{}
+}
+
+const (
+	insert   = "INSERT IGNORE INTO mutes (muter_id, muted_id) VALUES (?, ?)"
+	remove   = "DELETE FROM mutes WHERE muter_id = ? AND muted_id = ?"
+	getMuted = "SELECT a.id, a.user_name, a.f_name, a.l_name FROM mutes m JOIN accounts a ON a.id = m.muted_id " +
+		"WHERE m.muter_id = ? ORDER BY m.created_at DESC"
+)
+
+// Create makes an account mute another. Muting an account twice is the same as muting it once.
+func (m mute) Create(ctx *app.Context, muterID, mutedID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, insert, muterID, mutedID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Delete makes an account stop muting another.
+func (m mute) Delete(ctx *app.Context, muterID, mutedID int64) error {
+	if _, err := ctx.SQL.ExecContext(ctx, remove, muterID, mutedID); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// GetMuted retrieves the accounts an account mutes, the latest muted first.
+func (m mute) GetMuted(ctx *app.Context, muterID int64) ([]*models.User, error) {
+	rows, err := ctx.SQL.QueryContext(ctx, getMuted, muterID)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+
+	for rows.Next() {
+		var user models.User
+
+		if err := rows.Scan(&user.ID, &user.UserName, &user.FName, &user.LName); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return users, nil
+}
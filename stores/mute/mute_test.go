@@ -0,0 +1,45 @@
+package mute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+// initializeTest creates the accounts aakanksha_jais (1), mainak_pandit (2) and divij_gupta (3), without mutes.
+func initializeTest() (*app.Context, stores.Mute) {
+	test.InitializeTestAccountsTable(a.SQL.DB, a.Logger, "../../db")
+	return &app.Context{Context: context.TODO(), App: a}, New()
+}
+
+//nolint:lll // test cases need to be readable
+func TestMute(t *testing.T) {
+	ctx, mute := initializeTest()
+
+	tests := []struct {
+		description string
+		run         func() (interface{}, error)
+		output      interface{}
+	}{
+		{description: "mute an account", run: func() (interface{}, error) { return nil, mute.Create(ctx, 1, 2) }},
+		{description: "mute an account twice", run: func() (interface{}, error) { return nil, mute.Create(ctx, 1, 2) }},
+		{description: "accounts muted", run: func() (interface{}, error) { return mute.GetMuted(ctx, 1) }, output: []*models.User{{ID: 2, UserName: "mainak_pandit", FName: "Mainak", LName: "Pandit"}}},
+		{description: "a mute is one way", run: func() (interface{}, error) { return mute.GetMuted(ctx, 2) }, output: []*models.User{}},
+		{description: "unmute an account", run: func() (interface{}, error) { return nil, mute.Delete(ctx, 1, 2) }},
+		{description: "no accounts muted", run: func() (interface{}, error) { return mute.GetMuted(ctx, 1) }, output: []*models.User{}},
+	}
+
+	for i, tc := range tests {
+		output, err := tc.run()
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
@@ -0,0 +1,141 @@
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type report struct {
+	indexes *datastore.MongoIndexes
+}
+
+// New returns a report store. An open report is unique for its reporter and the blog or user it is about,
+// and the moderation queue is read by status, oldest first.
+func New() stores.Report {
+	return report{indexes: datastore.NewMongoIndexes("reports",
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "reporter_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "blog_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("report_open_reporter").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.ReportOpen}),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_on", Value: 1}},
+			Options: options.Index().SetName("report_status_created_on"),
+		},
+	)}
+}
+
+// GetAll retrieves the reports that match the filter, oldest first.
+func (r report) GetAll(c *app.Context, filter *models.ReportFilter, page *models.Page) ([]*models.Report, error) {
+	if filter == nil {
+		filter = &models.ReportFilter{}
+	}
+
+	collection := c.Mongo.Collection("reports")
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_on", Value: 1}, {Key: "_id", Value: 1}})
+
+	if page != nil {
+		opts = opts.SetSkip(page.Skip()).SetLimit(page.Limit)
+	}
+
+	cursor, err := collection.Find(c, filter.GetFilter(), opts)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	reports := make([]*models.Report, 0)
+
+	for cursor.Next(c) {
+		var report models.Report
+
+		if err = cursor.Decode(&report); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		reports = append(reports, &report)
+	}
+
+	if err = cursor.Close(c); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return reports, nil
+}
+
+// Get retrieves a report by its ID.
+func (r report) Get(c *app.Context, id string) (*models.Report, error) {
+	collection := c.Mongo.Collection("reports")
+
+	res := collection.FindOne(c, bson.D{{Key: "_id", Value: id}})
+
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "report", ID: id}
+	default:
+		return nil, errors.DBError{Err: err}
+	}
+
+	var report models.Report
+
+	if err := res.Decode(&report); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &report, nil
+}
+
+// Create creates a report, and fails with EntityAlreadyExists if the reporter has an open report on the same blog or user.
+func (r report) Create(c *app.Context, model *models.Report) error {
+	if err := r.indexes.Ensure(c, c.Mongo); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("reports")
+
+	_, err := collection.InsertOne(c, model)
+
+	switch {
+	case err == nil:
+		return nil
+	case mongo.IsDuplicateKeyError(err):
+		return errors.EntityAlreadyExists{Entity: "report", ValueType: model.Kind, Value: target(model)}
+	default:
+		return errors.DBError{Err: err}
+	}
+}
+
+// Close closes the open reports that match the filter with the status and action given, and returns how many it closed.
+func (r report) Close(c *app.Context, filter *models.ReportFilter, status, actionID string) (int64, error) {
+	open := *filter
+	open.Status = models.ReportOpen
+
+	collection := c.Mongo.Collection("reports")
+
+	update := bson.M{"$set": bson.M{"status": status, "action_id": actionID, "resolved_on": time.Now()}}
+
+	res, err := collection.UpdateMany(c, open.GetFilter(), update)
+	if err != nil {
+		return 0, errors.DBError{Err: err}
+	}
+
+	return res.ModifiedCount, nil
+}
+
+func target(model *models.Report) string {
+	if model.Kind == models.ReportBlog {
+		return model.BlogID
+	}
+
+	return strconv.FormatInt(model.UserID, 10)
+}
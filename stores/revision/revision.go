@@ -0,0 +1,118 @@
+package revision
+
+import (
+	"strconv"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type revision struct {
+	indexes *datastore.MongoIndexes
+}
+
+// New returns a revision store. A revision number is unique within a blog, so that
+// concurrent updates cannot record the same revision twice.
+func New() stores.Revision {
+	return revision{indexes: datastore.NewMongoIndexes("blog_revisions",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "rev", Value: -1}},
+			Options: options.Index().SetName("revision_blog_rev").SetUnique(true),
+		},
+	)}
+}
+
+// GetAll retrieves the revisions of a blog, latest first.
+func (r revision) GetAll(c *app.Context, blogID string, page *models.Page) ([]*models.Revision, error) {
+	if err := r.indexes.Ensure(c, c.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("blog_revisions")
+
+	opts := options.Find().SetSort(bson.D{{Key: "rev", Value: -1}})
+
+	if page != nil {
+		opts = opts.SetSkip(page.Skip()).SetLimit(page.Limit)
+	}
+
+	cursor, err := collection.Find(c, bson.D{{Key: "blog_id", Value: blogID}}, opts)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	revisions := make([]*models.Revision, 0)
+
+	for cursor.Next(c) {
+		var revision models.Revision
+
+		if err = cursor.Decode(&revision); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	if err = cursor.Close(c); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return revisions, nil
+}
+
+// Get retrieves a revision of a blog by its number.
+func (r revision) Get(c *app.Context, blogID string, number int64) (*models.Revision, error) {
+	return r.findOne(c, bson.D{{Key: "blog_id", Value: blogID}, {Key: "rev", Value: number}}, options.FindOne())
+}
+
+// GetLatest retrieves the latest revision of a blog.
+func (r revision) GetLatest(c *app.Context, blogID string) (*models.Revision, error) {
+	return r.findOne(c, bson.D{{Key: "blog_id", Value: blogID}}, options.FindOne().SetSort(bson.D{{Key: "rev", Value: -1}}))
+}
+
+func (r revision) findOne(c *app.Context, filter bson.D, opts *options.FindOneOptions) (*models.Revision, error) {
+	collection := c.Mongo.Collection("blog_revisions")
+
+	res := collection.FindOne(c, filter, opts)
+
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "revision"}
+	default:
+		return nil, errors.DBError{Err: err}
+	}
+
+	var revision models.Revision
+
+	if err := res.Decode(&revision); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &revision, nil
+}
+
+// Create records a revision. Recording a revision number that exists already fails.
+func (r revision) Create(c *app.Context, model *models.Revision) error {
+	if err := r.indexes.Ensure(c, c.Mongo); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("blog_revisions")
+
+	if _, err := collection.InsertOne(c, model); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.EntityAlreadyExists{Entity: "revision", ValueType: "number", Value: strconv.FormatInt(model.Number, 10)}
+		}
+
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
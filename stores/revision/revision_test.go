@@ -0,0 +1,128 @@
+package revision
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func initializeTest() (*app.Context, stores.Revision) {
+	ctx := &app.Context{Context: context.TODO(), App: a}
+	_ = ctx.Mongo.Collection("blog_revisions").Drop(ctx)
+
+	revision := New()
+
+	for _, model := range getRevisions() {
+		_ = revision.Create(ctx, model)
+	}
+
+	return ctx, revision
+}
+
+func getTime(date string) time.Time {
+	t, _ := time.Parse("2006-01-02T15:04:05Z07:00", date)
+	return t
+}
+
+//nolint:lll // test cases need to be readable
+func getRevisions() []*models.Revision {
+	return []*models.Revision{
+		{BlogID: "MSI8WKNSH9", Number: 1, AccountID: 2, CreatedOn: getTime("2021-05-23T15:04:05Z"), Title: "music", Summary: "a blog on music", Content: "avicii", Tags: []string{}, Changes: []string{"created"}},
+		{BlogID: "MSI8WKNSH9", Number: 2, AccountID: 2, CreatedOn: getTime("2021-05-24T15:04:05Z"), Title: "music", Summary: "a blog on music", Content: "avicii left :(", Tags: []string{"#music"}, Changes: []string{"content changed", "tags added: #music"}},
+		{BlogID: "9SNVSH8K2M", Number: 1, AccountID: 3, CreatedOn: getTime("2021-04-16T15:04:05Z"), Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#nature"}, Changes: []string{"created"}},
+	}
+}
+
+func TestRevision_GetAll(t *testing.T) {
+	ctx, revision := initializeTest()
+
+	tests := []struct {
+		description string
+		blogID      string
+		page        *models.Page
+		output      []*models.Revision
+	}{
+		{description: "revisions of a blog, latest first", blogID: "MSI8WKNSH9", output: []*models.Revision{getRevisions()[1], getRevisions()[0]}},
+		{description: "second page of revisions", blogID: "MSI8WKNSH9", page: &models.Page{Limit: 1, PageNo: 2}, output: getRevisions()[:1]},
+		{description: "blog without revisions", blogID: "ABK7SH2V37", output: []*models.Revision{}},
+	}
+
+	for i, tc := range tests {
+		output, err := revision.GetAll(ctx, tc.blogID, tc.page)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, nil, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestRevision_Get(t *testing.T) {
+	ctx, revision := initializeTest()
+
+	tests := []struct {
+		description string
+		blogID      string
+		number      int64
+		output      *models.Revision
+		err         error
+	}{
+		{description: "existing revision", blogID: "MSI8WKNSH9", number: 1, output: getRevisions()[0]},
+		{description: "missing revision", blogID: "MSI8WKNSH9", number: 3, err: errors.EntityNotFound{Entity: "revision"}},
+	}
+
+	for i, tc := range tests {
+		output, err := revision.Get(ctx, tc.blogID, tc.number)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestRevision_GetLatest(t *testing.T) {
+	ctx, revision := initializeTest()
+
+	tests := []struct {
+		description string
+		blogID      string
+		output      *models.Revision
+		err         error
+	}{
+		{description: "latest of many revisions", blogID: "MSI8WKNSH9", output: getRevisions()[1]},
+		{description: "blog without revisions", blogID: "ABK7SH2V37", err: errors.EntityNotFound{Entity: "revision"}},
+	}
+
+	for i, tc := range tests {
+		output, err := revision.GetLatest(ctx, tc.blogID)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestRevision_Create(t *testing.T) {
+	ctx, revision := initializeTest()
+
+	tests := []struct {
+		description string
+		input       *models.Revision
+		err         error
+	}{
+		{description: "next revision", input: &models.Revision{BlogID: "9SNVSH8K2M", Number: 2, AccountID: 3, Changes: []string{"title changed"}}},
+		{description: "revision number taken", input: &models.Revision{BlogID: "9SNVSH8K2M", Number: 2, AccountID: 3}, err: errors.EntityAlreadyExists{Entity: "revision", ValueType: "number", Value: "2"}},
+	}
+
+	for i, tc := range tests {
+		err := revision.Create(ctx, tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
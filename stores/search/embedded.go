@@ -0,0 +1,237 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/fulltext"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+// field weights, kept in line with the weights of the mongo text index
+const (
+	titleWeight   = 10
+	tagsWeight    = 5
+	summaryWeight = 3
+	contentWeight = 1
+)
+
+// embedded is an in-memory inverted index over blogs, for local and offline use.
+// It is loaded from the blog store on first use and then kept in sync through Index and Remove.
+type embedded struct {
+	blogStore stores.Blog
+
+	mu       *sync.RWMutex
+	loaded   *bool
+	blogs    map[string]*models.Blog       // blog id -> blog
+	postings map[string]map[string]float64 // term -> blog id -> weighted term frequency
+}
+
+// NewEmbedded returns a SearchIndex that is held in the memory of the process.
+func NewEmbedded(blogStore stores.Blog) stores.SearchIndex {
+	loaded := false
+
+	return embedded{
+		blogStore: blogStore,
+		mu:        &sync.RWMutex{},
+		loaded:    &loaded,
+		blogs:     make(map[string]*models.Blog),
+		postings:  make(map[string]map[string]float64),
+	}
+}
+
+// Index adds a blog to the index, replacing any earlier version of it.
+func (e embedded) Index(ctx *app.Context, blog *models.Blog) error {
+	if blog == nil {
+		return nil
+	}
+
+	if err := e.load(ctx); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.add(blog)
+
+	return nil
+}
+
+// Remove removes a blog from the index.
+func (e embedded) Remove(ctx *app.Context, blogID string) error {
+	if err := e.load(ctx); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.remove(blogID)
+
+	return nil
+}
+
+// Search ranks the blogs that contain at least one of the query terms by tf-idf.
+func (e embedded) Search(ctx *app.Context, query string, hidden []int64, page *models.Page) ([]*models.SearchHit, error) {
+	if err := e.load(ctx); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	n := float64(len(e.blogs))
+
+	for _, term := range fulltext.Tokenize(query) {
+		postings := e.postings[term]
+		idf := math.Log(1 + n/float64(len(postings)+1))
+
+		for id, tf := range postings {
+			scores[id] += tf * idf
+		}
+	}
+
+	hits := make([]*models.SearchHit, 0, len(scores))
+
+	skip := make(map[int64]bool, len(hidden))
+	for _, id := range hidden {
+		skip[id] = true
+	}
+
+	for id, score := range scores {
+		if skip[e.blogs[id].AccountID] {
+			continue
+		}
+
+		blog := *e.blogs[id]
+
+		hits = append(hits, &models.SearchHit{Blog: &blog, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].Blog.CreatedOn.After(hits[j].Blog.CreatedOn)
+		}
+
+		return hits[i].Score > hits[j].Score
+	})
+
+	return paginate(hits, page), nil
+}
+
+// load populates the index from the blog store if it has not been populated yet.
+func (e embedded) load(ctx *app.Context) error {
+	e.mu.RLock()
+	loaded := *e.loaded
+	e.mu.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	blogs, err := e.blogStore.GetAll(ctx, &models.BlogFilter{}, nil)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if *e.loaded {
+		return nil
+	}
+
+	for _, blog := range blogs {
+		e.add(blog)
+	}
+
+	*e.loaded = true
+
+	ctx.Logger.Infof("search index loaded with %v blogs", len(blogs))
+
+	return nil
+}
+
+// add indexes a blog, the caller must hold the write lock.
+func (e embedded) add(blog *models.Blog) {
+	e.remove(blog.BlogID)
+
+	b := *blog
+	e.blogs[b.BlogID] = &b
+
+	weights := make(map[string]float64)
+
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{b.Title, titleWeight},
+		{b.Summary, summaryWeight},
+		{b.Content, contentWeight},
+	} {
+		for _, term := range fulltext.Tokenize(field.text) {
+			weights[term] += field.weight
+		}
+	}
+
+	for _, tag := range b.Tags {
+		for _, term := range fulltext.Tokenize(tag) {
+			weights[term] += tagsWeight
+		}
+	}
+
+	for term, weight := range weights {
+		if e.postings[term] == nil {
+			e.postings[term] = make(map[string]float64)
+		}
+
+		e.postings[term][b.BlogID] = weight
+	}
+}
+
+// remove drops a blog from the index, the caller must hold the write lock.
+func (e embedded) remove(blogID string) {
+	blog, ok := e.blogs[blogID]
+	if !ok {
+		return
+	}
+
+	terms := fulltext.Tokenize(blog.Title + " " + blog.Summary + " " + blog.Content)
+
+	for _, tag := range blog.Tags {
+		terms = append(terms, fulltext.Tokenize(tag)...)
+	}
+
+	for _, term := range terms {
+		delete(e.postings[term], blogID)
+
+		if len(e.postings[term]) == 0 {
+			delete(e.postings, term)
+		}
+	}
+
+	delete(e.blogs, blogID)
+}
+
+func paginate(hits []*models.SearchHit, page *models.Page) []*models.SearchHit {
+	if page == nil || page.Limit == 0 {
+		return hits
+	}
+
+	start := page.Skip()
+	if start < 0 || start >= int64(len(hits)) {
+		return []*models.SearchHit{}
+	}
+
+	end := start + page.Limit
+	if end > int64(len(hits)) {
+		end = int64(len(hits))
+	}
+
+	return hits[start:end]
+}
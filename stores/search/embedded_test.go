@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/log"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+//nolint:lll // test cases need to be readable
+func getBlogs() []*models.Blog {
+	return []*models.Blog{
+		{BlogID: "UMS672XR8J", AccountID: 1, Title: "coffee", Summary: "a blog on coffee", Content: "visit starbucks for best coffee!", Tags: []string{"#caffiene", "#coffee"}, CreatedOn: time.Date(2021, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{BlogID: "ABK7SH2V37", AccountID: 2, Title: "chocolate", Summary: "a blog on chocolate", Content: "bournville is the best chocolate, goes well with coffee", Tags: []string{"#cocoa"}, CreatedOn: time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "flowers", Summary: "a blog on flowers", Content: "blue orchids are the most beautiful", Tags: []string{"#nature"}, CreatedOn: time.Date(2021, 4, 16, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func ids(hits []*models.SearchHit) []string {
+	res := make([]string, 0)
+
+	for _, hit := range hits {
+		res = append(res, hit.Blog.BlogID)
+	}
+
+	return res
+}
+
+func TestEmbedded_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	ctx := &app.Context{Context: context.TODO(), App: &app.App{Logger: log.NewLogger()}}
+
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{}, nil).Return(getBlogs(), nil).Times(1)
+
+	index := NewEmbedded(mockBlogStore)
+
+	tests := []struct {
+		description string
+		query       string
+		hidden      []int64
+		page        *models.Page
+		output      []string
+	}{
+		{description: "title match ranks above content match", query: "coffee", output: []string{"UMS672XR8J", "ABK7SH2V37"}},
+		{description: "match on tag", query: "cocoa", output: []string{"ABK7SH2V37"}},
+		{description: "no match", query: "music", output: []string{}},
+		{description: "second page", query: "coffee", page: &models.Page{Limit: 1, PageNo: 2}, output: []string{"ABK7SH2V37"}},
+		{description: "page out of range", query: "coffee", page: &models.Page{Limit: 1, PageNo: 5}, output: []string{}},
+		{description: "blogs of hidden accounts", query: "coffee", hidden: []int64{1}, output: []string{"ABK7SH2V37"}},
+	}
+
+	for i, tc := range tests {
+		output, err := index.Search(ctx, tc.query, tc.hidden, tc.page)
+
+		assert.Equal(t, tc.output, ids(output), "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestEmbedded_IndexAndRemove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlogStore := stores.NewMockBlog(ctrl)
+	ctx := &app.Context{Context: context.TODO(), App: &app.App{Logger: log.NewLogger()}}
+
+	mockBlogStore.EXPECT().GetAll(gomock.Any(), &models.BlogFilter{}, nil).Return(getBlogs(), nil).Times(1)
+
+	index := NewEmbedded(mockBlogStore)
+
+	// replace the title of an indexed blog
+	err := index.Index(ctx, &models.Blog{BlogID: "9SNVSH8K2M", AccountID: 3, Title: "music", Summary: "a blog on music", Content: "avicii left :("})
+	assert.Nil(t, err)
+
+	hits, _ := index.Search(ctx, "flowers", nil, nil)
+	assert.Equal(t, []string{}, ids(hits), "stale terms should be removed on re-index")
+
+	hits, _ = index.Search(ctx, "avicii", nil, nil)
+	assert.Equal(t, []string{"9SNVSH8K2M"}, ids(hits))
+
+	err = index.Remove(ctx, "9SNVSH8K2M")
+	assert.Nil(t, err)
+
+	hits, _ = index.Search(ctx, "music", nil, nil)
+	assert.Equal(t, []string{}, ids(hits), "removed blog should not be returned")
+}
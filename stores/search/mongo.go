@@ -0,0 +1,105 @@
+package search
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+// mongoIndex searches blogs using a text index on the blogs collection.
+// Mongo keeps the text index up to date on every write to the collection,
+// so Index and Remove have nothing to do.
+type mongoIndex struct {
+	indexes *datastore.MongoIndexes
+}
+
+// NewMongo returns a SearchIndex backed by a Mongo text index.
+// Matches in the title weigh the most, followed by tags, summary and content.
+func NewMongo() stores.SearchIndex {
+	textIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "summary", Value: "text"},
+			{Key: "content", Value: "text"},
+			{Key: "tags", Value: "text"},
+		},
+		Options: options.Index().SetName("blog_text_index").SetWeights(bson.M{
+			"title":   10,
+			"tags":    5,
+			"summary": 3,
+			"content": 1,
+		}),
+	}
+
+	return mongoIndex{indexes: datastore.NewMongoIndexes("blogs", textIndex)}
+}
+
+// Index is a no-op, the text index is maintained by Mongo.
+func (m mongoIndex) Index(_ *app.Context, _ *models.Blog) error {
+	return nil
+}
+
+// Remove is a no-op, the text index is maintained by Mongo.
+func (m mongoIndex) Remove(_ *app.Context, _ string) error {
+	return nil
+}
+
+// Search retrieves the blogs that match the query, sorted by text score.
+func (m mongoIndex) Search(ctx *app.Context, query string, hidden []int64, page *models.Page) ([]*models.SearchHit, error) {
+	if err := m.indexes.Ensure(ctx, ctx.Mongo); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	collection := ctx.Mongo.Collection("blogs")
+
+	score := bson.M{"$meta": "textScore"}
+
+	opts := options.Find().SetProjection(bson.M{"score": score}).SetSort(bson.D{{Key: "score", Value: score}})
+
+	if page != nil {
+		opts = opts.SetSkip(page.Skip()).SetLimit(page.Limit)
+	}
+
+	// only published blogs listed to everyone are searchable
+	filter := append(bson.D{{Key: "$text", Value: bson.M{"$search": query}}}, models.ListedFilter(nil)...)
+
+	if len(hidden) != 0 {
+		filter = append(filter, bson.E{Key: "account_id", Value: bson.M{"$nin": hidden}})
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	hits := make([]*models.SearchHit, 0)
+
+	for cursor.Next(ctx) {
+		var res struct {
+			models.Blog `bson:",inline"`
+			Score       float64 `bson:"score"`
+		}
+
+		err = cursor.Decode(&res)
+		if err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		blog := res.Blog
+
+		hits = append(hits, &models.SearchHit{Blog: &blog, Score: res.Score})
+	}
+
+	err = cursor.Close(ctx)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return hits, nil
+}
@@ -1,19 +1,36 @@
 package tag
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
 	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type tag struct{}
+// eventRetention is how long tag usage events are kept before mongo expires them.
+// It must be longer than the largest trending window together with its baseline.
+const eventRetention = 30 * 24 * time.Hour
+
+type tag struct {
+	eventIndexes *datastore.MongoIndexes
+}
 
 func New() stores.Tag {
-	return tag{}
+	eventIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_on", Value: 1}},
+		Options: options.Index().SetName("tag_events_ttl").SetExpireAfterSeconds(int32(eventRetention.Seconds())),
+	}
+
+	return tag{eventIndexes: datastore.NewMongoIndexes("tag_events", eventIndex)}
 }
 
 // Get retrieves a tag by its name.
@@ -38,6 +55,74 @@ func (t tag) Get(c *app.Context, name string) (*models.Tag, error) {
 	return &tag, nil
 }
 
+// GetAll retrieves tags with their blog counts, without the list of blog_id's.
+// Tags are sorted by popularity (most blogs first) or by name.
+func (t tag) GetAll(c *app.Context, page *models.Page, sortBy string) ([]*models.Tag, error) {
+	sort := bson.D{{Key: "blog_count", Value: -1}, {Key: "_id", Value: 1}}
+	if sortBy == models.TagSortName {
+		sort = bson.D{{Key: "_id", Value: 1}}
+	}
+
+	pipeline := mongo.Pipeline{countStage(), {{Key: "$sort", Value: sort}}}
+
+	if page != nil && page.Limit > 0 {
+		if skip := page.Skip(); skip > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$skip", Value: skip}})
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: page.Limit}})
+	}
+
+	return t.aggregate(c, pipeline)
+}
+
+// GetByPrefix retrieves the most popular tags whose name starts with the prefix.
+// The anchored regex is served by the index on _id.
+func (t tag) GetByPrefix(c *app.Context, prefix string, limit int64) ([]*models.Tag, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}}},
+		countStage(),
+		{{Key: "$sort", Value: bson.D{{Key: "blog_count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	return t.aggregate(c, pipeline)
+}
+
+// countStage replaces the list of blog_id's of a tag with its length.
+func countStage() bson.D {
+	return bson.D{{Key: "$project", Value: bson.M{"blog_count": bson.M{"$size": bson.M{"$ifNull": bson.A{"$blog_id_list", bson.A{}}}}}}}
+}
+
+func (t tag) aggregate(c *app.Context, pipeline mongo.Pipeline) ([]*models.Tag, error) {
+	collection := c.Mongo.Collection("tags")
+
+	cursor, err := collection.Aggregate(c, pipeline)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	tags := make([]*models.Tag, 0)
+
+	for cursor.Next(c) {
+		var tag models.Tag
+
+		err = cursor.Decode(&tag)
+		if err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	err = cursor.Close(c)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return tags, nil
+}
+
 // Update adds blog_id to given list of tags.
 // Tags are created if they do not exist already.
 func (t tag) Update(c *app.Context, blogID, tag string, operation constants.Operation) error {
@@ -75,3 +160,80 @@ func (t tag) Delete(c *app.Context, tag string) error {
 
 	return nil
 }
+
+// AddEvent records a usage of a tag.
+func (t tag) AddEvent(c *app.Context, event *models.TagEvent) error {
+	if err := t.eventIndexes.Ensure(c, c.Mongo); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("tag_events")
+
+	_, err := collection.InsertOne(c, event)
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// DeleteEvents removes the usages of a tag by a blog, so that a tag taken off a blog
+// does not count towards trending tags by it.
+func (t tag) DeleteEvents(c *app.Context, tag, blogID string) error {
+	collection := c.Mongo.Collection("tag_events")
+
+	_, err := collection.DeleteMany(c, bson.D{{Key: "tag", Value: tag}, {Key: "blog_id", Value: blogID}})
+	if err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// GetTrending compares the usage of tags since windowStart with their usage between baselineStart and windowStart.
+// Growth is the increase in usage relative to the baseline, a baseline of zero is counted as one.
+func (t tag) GetTrending(c *app.Context, windowStart, baselineStart time.Time, limit int64) ([]*models.TrendingTag, error) {
+	inWindow := bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$created_on", windowStart}}, 1, 0}}
+	inBaseline := bson.M{"$cond": bson.A{bson.M{"$lt": bson.A{"$created_on", windowStart}}, 1, 0}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_on": bson.M{"$gte": baselineStart}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$tag",
+			"count":          bson.M{"$sum": inWindow},
+			"baseline_count": bson.M{"$sum": inBaseline},
+		}}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$gt": bson.A{"$count", "$baseline_count"}}}}},
+		{{Key: "$addFields", Value: bson.M{"growth": bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{"$count", "$baseline_count"}},
+			bson.M{"$max": bson.A{"$baseline_count", 1}},
+		}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "growth", Value: -1}, {Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := c.Mongo.Collection("tag_events").Aggregate(c, pipeline)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	tags := make([]*models.TrendingTag, 0)
+
+	for cursor.Next(c) {
+		var tag models.TrendingTag
+
+		err = cursor.Decode(&tag)
+		if err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	err = cursor.Close(c)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return tags, nil
+}
@@ -3,12 +3,14 @@ package tag
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/test"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/constants"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/Aakanksha-jais/picshot-golang-backend/models"
@@ -22,6 +24,12 @@ func initializeTest() (*app.Context, stores.Tag) {
 	return &app.Context{Context: context.TODO(), App: a}, New()
 }
 
+// initializeDirectoryTest loads the test tags, which are listed by the tag directory with their blog counts.
+func initializeDirectoryTest() (*app.Context, stores.Tag) {
+	test.InitializeTestTagsCollection(a.Mongo.Database, a.Logger, "../../db")
+	return &app.Context{Context: context.TODO(), App: a}, New()
+}
+
 func TestTag_Get(t *testing.T) {
 	ctx, tag := initializeTest()
 
@@ -184,3 +192,108 @@ func TestTag_Delete(t *testing.T) {
 		assert.Equal(t, tests[i].err, err, "TEST [%v], failed.\n%s", i+1, tests[i].description)
 	}
 }
+
+func TestTag_GetAll(t *testing.T) {
+	ctx, tag := initializeDirectoryTest()
+
+	tests := []struct {
+		description string
+		page        *models.Page
+		sortBy      string
+		output      []*models.Tag
+	}{
+		{
+			description: "sort by popularity, first page",
+			page:        &models.Page{Limit: 4, PageNo: 1},
+			sortBy:      models.TagSortPopularity,
+			output:      []*models.Tag{{Name: "#life", BlogCount: 4}, {Name: "#trending", BlogCount: 4}, {Name: "#love", BlogCount: 3}, {Name: "#caffiene", BlogCount: 1}},
+		},
+		{
+			description: "sort by name, second page",
+			page:        &models.Page{Limit: 2, PageNo: 2},
+			sortBy:      models.TagSortName,
+			output:      []*models.Tag{{Name: "#cocoa", BlogCount: 1}, {Name: "#coffee", BlogCount: 1}},
+		},
+		{description: "page out of range", page: &models.Page{Limit: 2, PageNo: 100}, sortBy: models.TagSortName, output: []*models.Tag{}},
+	}
+
+	for i := range tests {
+		output, err := tag.GetAll(ctx, tests[i].page, tests[i].sortBy)
+
+		assert.Equal(t, tests[i].output, output, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+	}
+}
+
+func TestTag_GetByPrefix(t *testing.T) {
+	ctx, tag := initializeDirectoryTest()
+
+	tests := []struct {
+		description string
+		prefix      string
+		limit       int64
+		output      []*models.Tag
+	}{
+		{description: "prefix with multiple matches", prefix: "#m", limit: 3, output: []*models.Tag{{Name: "#markmanson", BlogCount: 1}, {Name: "#memories", BlogCount: 1}, {Name: "#movie", BlogCount: 1}}},
+		{description: "more popular tags first", prefix: "#l", limit: 10, output: []*models.Tag{{Name: "#life", BlogCount: 4}, {Name: "#love", BlogCount: 3}}},
+		{description: "regex characters are matched literally", prefix: "#.", limit: 10, output: []*models.Tag{}},
+	}
+
+	for i := range tests {
+		output, err := tag.GetByPrefix(ctx, tests[i].prefix, tests[i].limit)
+
+		assert.Equal(t, tests[i].output, output, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tests[i].description)
+	}
+}
+
+// initializeEventsTest clears the usage events of tags.
+func initializeEventsTest(t *testing.T) (*app.Context, stores.Tag) {
+	ctx := &app.Context{Context: context.TODO(), App: a}
+
+	if _, err := ctx.Mongo.Collection("tag_events").DeleteMany(ctx, bson.D{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return ctx, New()
+}
+
+//nolint:lll // test cases need to be readable
+func TestTag_DeleteEvents(t *testing.T) {
+	ctx, tag := initializeEventsTest(t)
+
+	now := time.Now()
+
+	for _, event := range []*models.TagEvent{
+		{Tag: "#life", BlogID: "UMS672XR8J", CreatedOn: now},
+		{Tag: "#life", BlogID: "ABK7SH2V37", CreatedOn: now},
+		{Tag: "#love", BlogID: "UMS672XR8J", CreatedOn: now},
+	} {
+		assert.Nil(t, tag.AddEvent(ctx, event), "TEST, failed.\nusage of a tag")
+	}
+
+	tests := []struct {
+		description string
+		tag         string
+		blogID      string
+		output      []*models.TrendingTag
+	}{
+		{description: "usage of a tag taken off a blog", tag: "#life", blogID: "UMS672XR8J", output: []*models.TrendingTag{{Name: "#life", Count: 1, Growth: 1}, {Name: "#love", Count: 1, Growth: 1}}},
+		{description: "last usage of a tag", tag: "#love", blogID: "UMS672XR8J", output: []*models.TrendingTag{{Name: "#life", Count: 1, Growth: 1}}},
+		{description: "tag without usages", tag: "#love", blogID: "UMS672XR8J", output: []*models.TrendingTag{{Name: "#life", Count: 1, Growth: 1}}},
+	}
+
+	for i, tc := range tests {
+		err := tag.DeleteEvents(ctx, tc.tag, tc.blogID)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		output, err := tag.GetTrending(ctx, now.Add(-time.Hour), now.Add(-2*time.Hour), 10)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Nil(t, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
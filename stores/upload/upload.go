@@ -0,0 +1,144 @@
+package upload
+
+import (
+	"time"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/datastore"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type upload struct {
+	indexes *datastore.MongoIndexes
+}
+
+// New returns an upload store. Uploads are confirmed through conditional updates, and attached
+// or expired through conditional deletes, so that each happens once alone.
+func New() stores.Upload {
+	return upload{indexes: datastore.NewMongoIndexes("uploads",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("upload_expires_at"),
+		},
+	)}
+}
+
+// Get retrieves an upload by its ID.
+func (u upload) Get(c *app.Context, id string) (*models.Upload, error) {
+	collection := c.Mongo.Collection("uploads")
+
+	res := collection.FindOne(c, bson.D{{Key: "_id", Value: id}})
+
+	return decode(res, id)
+}
+
+// Create creates an upload.
+func (u upload) Create(c *app.Context, model *models.Upload) error {
+	if err := u.indexes.Ensure(c, c.Mongo); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	collection := c.Mongo.Collection("uploads")
+
+	if _, err := collection.InsertOne(c, model); err != nil {
+		return errors.DBError{Err: err}
+	}
+
+	return nil
+}
+
+// Confirm sets the image of a pending upload that has not expired, and the time it must be attached by.
+func (u upload) Confirm(c *app.Context, id string, image *models.Image, expiresAt time.Time) (*models.Upload, error) {
+	collection := c.Mongo.Collection("uploads")
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "status", Value: models.UploadPending},
+		{Key: "expires_at", Value: bson.M{"$gt": time.Now()}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.UploadConfirmed},
+		{Key: "image", Value: image},
+		{Key: "expires_at", Value: expiresAt},
+	}}}
+
+	res := collection.FindOneAndUpdate(c, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	return decode(res, id)
+}
+
+// Attach removes a confirmed upload of an account that has not expired, and returns it.
+func (u upload) Attach(c *app.Context, id string, accountID int64) (*models.Upload, error) {
+	collection := c.Mongo.Collection("uploads")
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "account_id", Value: accountID},
+		{Key: "status", Value: models.UploadConfirmed},
+		{Key: "expires_at", Value: bson.M{"$gt": time.Now()}},
+	}
+
+	return decode(collection.FindOneAndDelete(c, filter), id)
+}
+
+// GetExpired retrieves uploads that expired before a time, oldest first.
+func (u upload) GetExpired(c *app.Context, before time.Time, limit int64) ([]*models.Upload, error) {
+	collection := c.Mongo.Collection("uploads")
+
+	opts := options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(limit)
+
+	cursor, err := collection.Find(c, bson.D{{Key: "expires_at", Value: bson.M{"$lt": before}}}, opts)
+	if err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	uploads := make([]*models.Upload, 0)
+
+	for cursor.Next(c) {
+		var upload models.Upload
+
+		if err = cursor.Decode(&upload); err != nil {
+			return nil, errors.DBError{Err: err}
+		}
+
+		uploads = append(uploads, &upload)
+	}
+
+	if err = cursor.Close(c); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return uploads, nil
+}
+
+// Expire removes an upload that expired before a time, and returns it.
+func (u upload) Expire(c *app.Context, id string, before time.Time) (*models.Upload, error) {
+	collection := c.Mongo.Collection("uploads")
+
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "expires_at", Value: bson.M{"$lt": before}}}
+
+	return decode(collection.FindOneAndDelete(c, filter), id)
+}
+
+func decode(res *mongo.SingleResult, id string) (*models.Upload, error) {
+	switch err := res.Err(); err {
+	case nil:
+	case mongo.ErrNoDocuments:
+		return nil, errors.EntityNotFound{Entity: "upload", ID: id}
+	default:
+		return nil, errors.DBError{Err: err}
+	}
+
+	var upload models.Upload
+
+	if err := res.Decode(&upload); err != nil {
+		return nil, errors.DBError{Err: err}
+	}
+
+	return &upload, nil
+}
@@ -0,0 +1,120 @@
+package upload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aakanksha-jais/picshot-golang-backend/models"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/app"
+	"github.com/Aakanksha-jais/picshot-golang-backend/pkg/errors"
+	"github.com/Aakanksha-jais/picshot-golang-backend/stores"
+)
+
+func initializeTest() (*app.Context, stores.Upload) {
+	ctx := &app.Context{Context: context.TODO(), App: a}
+	_ = ctx.Mongo.Collection("uploads").Drop(ctx)
+
+	upload := New()
+
+	for _, model := range getUploads() {
+		_ = upload.Create(ctx, model)
+	}
+
+	return ctx, upload
+}
+
+// expiry times are relative to now, rounded to the precision of mongo
+func in(d time.Duration) time.Time {
+	return time.Now().Add(d).Truncate(time.Millisecond).UTC()
+}
+
+//nolint:gochecknoglobals // fixed for all the uploads of a test run
+var (
+	future = in(time.Hour)
+	past   = in(-time.Hour)
+)
+
+//nolint:lll // test cases need to be readable
+func getUploads() []*models.Upload {
+	return []*models.Upload{
+		{ID: "u1", AccountID: 2, Name: "2_upload_u1.png", ContentType: "image/png", Size: 100, Status: models.UploadPending, CreatedOn: past, ExpiresAt: future},
+		{ID: "u2", AccountID: 2, Name: "2_upload_u2.png", ContentType: "image/png", Size: 100, Status: models.UploadConfirmed, Image: &models.Image{ID: "2_a"}, CreatedOn: past, ExpiresAt: future},
+		{ID: "u3", AccountID: 3, Name: "3_upload_u3.jpg", ContentType: "image/jpeg", Size: 100, Status: models.UploadPending, CreatedOn: past, ExpiresAt: past},
+	}
+}
+
+func TestUpload_Confirm(t *testing.T) {
+	ctx, upload := initializeTest()
+
+	expiresAt := in(24 * time.Hour)
+	image := &models.Image{ID: "2_b"}
+
+	confirmed := *getUploads()[0]
+	confirmed.Status, confirmed.Image, confirmed.ExpiresAt = models.UploadConfirmed, image, expiresAt
+
+	tests := []struct {
+		description string
+		id          string
+		output      *models.Upload
+		err         error
+	}{
+		{description: "pending upload", id: "u1", output: &confirmed},
+		{description: "upload confirmed already", id: "u1", err: errors.EntityNotFound{Entity: "upload", ID: "u1"}},
+		{description: "expired upload", id: "u3", err: errors.EntityNotFound{Entity: "upload", ID: "u3"}},
+	}
+
+	for i, tc := range tests {
+		output, err := upload.Confirm(ctx, tc.id, image, expiresAt)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestUpload_Attach(t *testing.T) {
+	ctx, upload := initializeTest()
+
+	tests := []struct {
+		description string
+		id          string
+		accountID   int64
+		output      *models.Upload
+		err         error
+	}{
+		{description: "upload of another account", id: "u2", accountID: 3, err: errors.EntityNotFound{Entity: "upload", ID: "u2"}},
+		{description: "pending upload", id: "u1", accountID: 2, err: errors.EntityNotFound{Entity: "upload", ID: "u1"}},
+		{description: "confirmed upload", id: "u2", accountID: 2, output: getUploads()[1]},
+		{description: "upload attached already", id: "u2", accountID: 2, err: errors.EntityNotFound{Entity: "upload", ID: "u2"}},
+	}
+
+	for i, tc := range tests {
+		output, err := upload.Attach(ctx, tc.id, tc.accountID)
+
+		assert.Equal(t, tc.output, output, "TEST [%v], failed.\n%s", i+1, tc.description)
+
+		assert.Equal(t, tc.err, err, "TEST [%v], failed.\n%s", i+1, tc.description)
+	}
+}
+
+func TestUpload_Expire(t *testing.T) {
+	ctx, upload := initializeTest()
+
+	expired, err := upload.GetExpired(ctx, time.Now(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Upload{getUploads()[2]}, expired)
+
+	_, err = upload.Expire(ctx, "u1", time.Now())
+	assert.Equal(t, errors.EntityNotFound{Entity: "upload", ID: "u1"}, err, "an upload that has not expired is kept")
+
+	output, err := upload.Expire(ctx, "u3", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, getUploads()[2], output)
+
+	_, err = upload.Get(ctx, "u3")
+	assert.Equal(t, errors.EntityNotFound{Entity: "upload", ID: "u3"}, err)
+}